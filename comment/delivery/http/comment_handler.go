@@ -1,57 +1,215 @@
 package delivery
 
 import (
-	"fmt"
-	"mygram-api/comment/delivery/http/middleware"
+	"errors"
 	"mygram-api/comment/utils"
 	"mygram-api/domain"
 	"mygram-api/helpers"
+	"mygram-api/middleware"
+	"mygram-api/pagination"
+	"mygram-api/policy"
 	"net/http"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type commentHandler struct {
 	commentUseCase domain.CommentUseCase
 	photoUseCase   domain.PhotoUseCase
+	likeUseCase    domain.LikeUseCase
 }
 
-func NewCommentHandler(routers *gin.Engine, commentUseCase domain.CommentUseCase, photoUseCase domain.PhotoUseCase) {
-	handler := &commentHandler{commentUseCase, photoUseCase}
+func NewCommentHandler(routers *gin.Engine, commentUseCase domain.CommentUseCase, photoUseCase domain.PhotoUseCase, likeUseCase domain.LikeUseCase) {
+	handler := &commentHandler{commentUseCase, photoUseCase, likeUseCase}
+
+	routers.GET("/photos/:photoId/comments", middleware.Authentication(domain.ScopeCommentsRead), handler.FetchByPhoto)
 
 	router := routers.Group("/comments")
 	{
-		router.Use(middleware.Authentication())
-		router.GET("", handler.Fetch)
-		router.POST("", handler.Store)
-		router.PUT("/:commentId", middleware.Authorization(handler.commentUseCase), handler.Update)
-		router.DELETE("/:commentId", middleware.Authorization(handler.commentUseCase), handler.Delete)
+		router.GET("", middleware.Authentication(domain.ScopeCommentsRead), handler.Fetch)
+		router.POST("", middleware.Authentication(domain.ScopeCommentsWrite), middleware.VerifiedEmail(), handler.Store)
+		router.GET("/:commentId", middleware.Authentication(domain.ScopeCommentsRead), handler.GetByID)
+		router.GET("/:commentId/replies", middleware.Authentication(domain.ScopeCommentsRead), handler.FetchReplies)
+		router.PUT("/:commentId", middleware.Authentication(domain.ScopeCommentsWrite), middleware.Authorization[domain.Comment](handler.commentUseCase, "commentId", policy.ResourceComment, policy.ActionUpdate), handler.Update)
+		router.DELETE("/:commentId", middleware.Authentication(domain.ScopeCommentsWrite), middleware.Authorization[domain.Comment](handler.commentUseCase, "commentId", policy.ResourceComment, policy.ActionDelete), handler.Delete)
 	}
 }
 
 // Fetch godoc
 // @Summary			Fetch all comments
-// @Description	Get all comments with authentication user
+// @Description	Get a page of the comments of the authenticated user, newest first
 // @Tags        comments
 // @Accept      json
 // @Produce     json
+// @Param       cursor					query			string	false	"Cursor of the page, the next_cursor of the previous one"
+// @Param       limit						query			int			false	"Items per page, 20 by default and at most 100"
+// @Param       sort						query			string	false	"newest (default) or oldest first"
+// @Param       created_after		query			string	false	"Only items created after this RFC 3339 time"
+// @Param       created_before	query			string	false	"Only items created before this RFC 3339 time"
 // @Success     200	{object}	utils.ResponseDataFetchedComment
 // @Failure     400	{object}	utils.ResponseMessage
 // @Failure     401	{object}	utils.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /comments     [get]
 func (handler *commentHandler) Fetch(ctx *gin.Context) {
+	var comments []domain.Comment
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	query, err := pagination.Parse(ctx.Request.URL.Query())
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	next, err := handler.commentUseCase.Fetch(ctx.Request.Context(), &comments, userID, query)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponsePage{
+		Status:     "success",
+		Data:       comments,
+		NextCursor: next,
+	})
+}
+
+// FetchByPhoto godoc
+// @Summary			Fetch the comments of a photo
+// @Description	Get a page of the top-level comments left on a photo, oldest first unless sort says otherwise, each with a summary of its author and how many replies it has. total counts every comment on the photo, replies included. Deleted comments that have replies stay as "[deleted]"
+// @Tags        comments
+// @Accept      json
+// @Produce     json
+// @Param       id							path			string	true	"Photo ID"
+// @Param       user_id					query			string	false	"Only comments of this user"
+// @Param       cursor					query			string	false	"Cursor of the page, the next_cursor of the previous one"
+// @Param       limit						query			int			false	"Items per page, 20 by default and at most 100"
+// @Param       sort						query			string	false	"oldest (default) or newest first"
+// @Param       created_after		query			string	false	"Only items created after this RFC 3339 time"
+// @Param       created_before	query			string	false	"Only items created before this RFC 3339 time"
+// @Success     200	{object}	utils.ResponseDataPhotoComments
+// @Failure     400	{object}	utils.ResponseMessage
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Failure     500	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /photos/{id}/comments	[get]
+func (handler *commentHandler) FetchByPhoto(ctx *gin.Context) {
 	var (
 		comments []domain.Comment
+		photo    domain.Photo
+	)
+
+	photoID := ctx.Param("photoId")
+
+	query, err := pagination.Parse(ctx.Request.URL.Query())
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
 
-		err error
+		return
+	}
+
+	// Comments read like a conversation, from the first one down.
+	if ctx.Query("sort") == "" {
+		query.Sort = pagination.SortOldest
+	}
+
+	if err = handler.photoUseCase.GetByID(ctx.Request.Context(), &photo, photoID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourcePhoto, photoID)
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	next, total, err := handler.commentUseCase.FetchByPhoto(ctx.Request.Context(), &comments, photoID, query)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	liked, err := handler.likedComments(ctx, comments)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponsePage{
+		Status: "success",
+		Data: utils.PhotoComments{
+			Total:    total,
+			Comments: utils.NewPhotoComments(comments, liked),
+		},
+		NextCursor: next,
+	})
+}
+
+// FetchReplies godoc
+// @Summary			Fetch the replies to a comment
+// @Description	Get a page of the direct replies to a comment, oldest first unless sort says otherwise, each with a summary of its author and how many replies it has in turn
+// @Tags        comments
+// @Accept      json
+// @Produce     json
+// @Param       id							path			string	true	"Comment ID"
+// @Param       cursor					query			string	false	"Cursor of the page, the next_cursor of the previous one"
+// @Param       limit						query			int			false	"Items per page, 20 by default and at most 100"
+// @Param       sort						query			string	false	"oldest (default) or newest first"
+// @Param       created_after		query			string	false	"Only items created after this RFC 3339 time"
+// @Param       created_before	query			string	false	"Only items created before this RFC 3339 time"
+// @Success     200	{object}	utils.ResponseDataReplies
+// @Failure     400	{object}	utils.ResponseMessage
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Failure     500	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /comments/{id}/replies	[get]
+func (handler *commentHandler) FetchReplies(ctx *gin.Context) {
+	var (
+		replies []domain.Comment
+		comment domain.Comment
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	commentID := ctx.Param("commentId")
 
-	if err = handler.commentUseCase.Fetch(ctx.Request.Context(), &comments, userID); err != nil {
+	query, err := pagination.Parse(ctx.Request.URL.Query())
+
+	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -60,15 +218,125 @@ func (handler *commentHandler) Fetch(ctx *gin.Context) {
 		return
 	}
 
+	if ctx.Query("sort") == "" {
+		query.Sort = pagination.SortOldest
+	}
+
+	if err = handler.commentUseCase.GetByID(ctx.Request.Context(), &comment, commentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourceComment, commentID)
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	next, err := handler.commentUseCase.FetchReplies(ctx.Request.Context(), &replies, commentID, query)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	liked, err := handler.likedComments(ctx, replies)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponsePage{
+		Status:     "success",
+		Data:       utils.NewPhotoComments(replies, liked),
+		NextCursor: next,
+	})
+}
+
+// GetByID godoc
+// @Summary			Fetch a comment
+// @Description	Get a comment by id with its author and the photo it was left on
+// @Tags        comments
+// @Accept      json
+// @Produce     json
+// @Param       id	path			string	true	"Comment ID"
+// @Success     200	{object}	utils.ResponseDataCommentDetail
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Failure     500	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /comments/{id}	[get]
+func (handler *commentHandler) GetByID(ctx *gin.Context) {
+	var comment domain.Comment
+
+	commentID := ctx.Param("commentId")
+
+	err := handler.commentUseCase.GetDetailByID(ctx.Request.Context(), &comment, commentID)
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourceComment, commentID)
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	liked, err := handler.likedComments(ctx, []domain.Comment{comment})
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	commentDetail := utils.CommentDetail{
+		PhotoComment: utils.NewPhotoComment(comment, liked[comment.ID]),
+		PhotoID:      comment.PhotoID,
+	}
+
+	if comment.Photo != nil {
+		commentDetail.Photo = &utils.Photo{
+			ID:       comment.Photo.ID,
+			Title:    comment.Photo.Title,
+			Caption:  comment.Photo.Caption,
+			PhotoUrl: comment.Photo.PhotoUrl,
+			UserID:   comment.Photo.UserID,
+		}
+	}
+
 	ctx.JSON(http.StatusOK, helpers.ResponseData{
 		Status: "success",
-		Data:   comments,
+		Data:   commentDetail,
 	})
 }
 
 // Store godoc
 // @Summary			Add a comment
-// @Description	create and store a comment with authentication user
+// @Description	create and store a comment with authentication user. A comment with a parent_id replies to that comment, which must be on the same photo, and replies nest at most 3 levels deep
 // @Tags        comments
 // @Accept      json
 // @Produce     json
@@ -76,7 +344,10 @@ func (handler *commentHandler) Fetch(ctx *gin.Context) {
 // @Success     201		{object}  utils.ResponseDataAddedComment
 // @Failure     400		{object}	utils.ResponseMessage
 // @Failure     401		{object}	utils.ResponseMessage
+// @Failure     403		{object}	utils.ResponseMessage
+// @Failure     404		{object}	utils.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /comments	[post]
 func (handler *commentHandler) Store(ctx *gin.Context) {
 	var (
@@ -85,8 +356,7 @@ func (handler *commentHandler) Store(ctx *gin.Context) {
 		err     error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	userID := middleware.CurrentPrincipal(ctx).UserID
 
 	if err = ctx.ShouldBindJSON(&comment); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -100,9 +370,15 @@ func (handler *commentHandler) Store(ctx *gin.Context) {
 	photoID := comment.PhotoID
 
 	if err = handler.photoUseCase.GetByID(ctx.Request.Context(), &photo, photoID); err != nil {
-		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
-			Status:  "fail",
-			Message: fmt.Sprintf("photo with id %s doesn't exist", photoID),
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourcePhoto, photoID)
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
 		})
 
 		return
@@ -111,6 +387,12 @@ func (handler *commentHandler) Store(ctx *gin.Context) {
 	comment.UserID = userID
 
 	if err = handler.commentUseCase.Store(ctx.Request.Context(), &comment); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) && comment.ParentID != nil {
+			middleware.AbortNotFound(ctx, policy.ResourceComment, *comment.ParentID)
+
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -125,6 +407,7 @@ func (handler *commentHandler) Store(ctx *gin.Context) {
 			ID:        comment.ID,
 			UserID:    comment.UserID,
 			PhotoID:   comment.PhotoID,
+			ParentID:  comment.ParentID,
 			Message:   comment.Message,
 			CreatedAt: comment.CreatedAt,
 		},
@@ -142,8 +425,10 @@ func (handler *commentHandler) Store(ctx *gin.Context) {
 // @Success     200		{object}  utils.ResponseDataUpdatedComment
 // @Failure     400		{object}	utils.ResponseMessage
 // @Failure     401		{object}	utils.ResponseMessage
+// @Failure     403		{object}	utils.ResponseMessage
 // @Failure     404		{object}	utils.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /comments/{id}	[put]
 func (handler *commentHandler) Update(ctx *gin.Context) {
 	var (
@@ -152,10 +437,6 @@ func (handler *commentHandler) Update(ctx *gin.Context) {
 		err     error
 	)
 
-	commentID := ctx.Param("commentId")
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
-
 	if err = ctx.ShouldBindJSON(&comment); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
@@ -166,11 +447,16 @@ func (handler *commentHandler) Update(ctx *gin.Context) {
 	}
 
 	updatedComment := domain.Comment{
-		UserID:  userID,
 		Message: comment.Message,
 	}
 
-	if photo, err = handler.commentUseCase.Update(ctx.Request.Context(), updatedComment, commentID); err != nil {
+	if photo, err = handler.commentUseCase.Update(ctx.Request.Context(), middleware.Loaded[domain.Comment](ctx), updatedComment); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourceComment, ctx.Param("commentId"))
+
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -194,7 +480,7 @@ func (handler *commentHandler) Update(ctx *gin.Context) {
 
 // Delete godoc
 // @Summary			Delete a comment
-// @Description	Delete a comment by id with authentication user
+// @Description	Delete a comment by id with authentication user. Moderators and admins can delete any comment
 // @Tags        comments
 // @Accept      json
 // @Produce     json
@@ -202,13 +488,21 @@ func (handler *commentHandler) Update(ctx *gin.Context) {
 // @Success     200 {object}	utils.ResponseMessageDeletedComment
 // @Failure     400 {object}	utils.ResponseMessage
 // @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
 // @Failure     404	{object}	utils.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /comments/{id}	[delete]
 func (handler *commentHandler) Delete(ctx *gin.Context) {
 	commentID := ctx.Param("commentId")
 
 	if err := handler.commentUseCase.Delete(ctx.Request.Context(), commentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourceComment, commentID)
+
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -221,3 +515,14 @@ func (handler *commentHandler) Delete(ctx *gin.Context) {
 		"message": "your comment has been successfully deleted",
 	})
 }
+
+// likedComments tells which of comments the caller likes.
+func (handler *commentHandler) likedComments(ctx *gin.Context, comments []domain.Comment) (map[string]bool, error) {
+	commentIDs := []string{}
+
+	for _, comment := range comments {
+		commentIDs = append(commentIDs, comment.ID)
+	}
+
+	return handler.likeUseCase.LikedBy(ctx.Request.Context(), domain.LikeTargetComment, middleware.CurrentPrincipal(ctx).UserID, commentIDs)
+}
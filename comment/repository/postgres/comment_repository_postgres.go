@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"mygram-api/domain"
+	"mygram-api/pagination"
 	"time"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type commentRepository struct {
@@ -18,17 +20,62 @@ func NewCommentRepository(db *gorm.DB) *commentRepository {
 	return &commentRepository{db}
 }
 
-func (commentRepository *commentRepository) Fetch(ctx context.Context, comments *[]domain.Comment, userID string) (err error) {
+func (commentRepository *commentRepository) Fetch(ctx context.Context, comments *[]domain.Comment, userID string, query pagination.Query) (next string, err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	if err = commentRepository.db.WithContext(ctx).Where("user_id = ?", userID).Preload("User", func(db *gorm.DB) *gorm.DB {
+	query.UserID = userID
+
+	if err = commentRepository.db.WithContext(ctx).Where("comments.deleted = ?", false).Scopes(query.Scope("comments")).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "email", "username", "profile_image_url")
 	}).Preload("Photo", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "user_id", "title", "photo_url", "caption")
 	}).Find(&comments).Error; err != nil {
-		return err
+		return "", err
+	}
+
+	return pagination.Trim(query, comments, func(comment domain.Comment) pagination.Cursor {
+		return pagination.NewCursor(comment.CreatedAt, comment.ID)
+	}), nil
+}
+
+// FetchByPhoto loads a page of the top-level comments left on the photo with
+// photoID, each with a summary of its author and how many replies it has.
+func (commentRepository *commentRepository) FetchByPhoto(ctx context.Context, comments *[]domain.Comment, photoID string, query pagination.Query) (next string, err error) {
+	return commentRepository.fetchThread(ctx, comments, query, "comments.photo_id = ? AND comments.parent_id IS NULL", photoID)
+}
+
+// FetchReplies loads a page of the direct replies to the comment with parentID.
+func (commentRepository *commentRepository) FetchReplies(ctx context.Context, comments *[]domain.Comment, parentID string, query pagination.Query) (next string, err error) {
+	return commentRepository.fetchThread(ctx, comments, query, "comments.parent_id = ?", parentID)
+}
+
+func (commentRepository *commentRepository) fetchThread(ctx context.Context, comments *[]domain.Comment, query pagination.Query, condition string, value string) (next string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = commentRepository.db.WithContext(ctx).Scopes(domain.WithReplyCount).Where(condition, value).Scopes(query.Scope("comments")).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "display_name", "profile_image_url")
+	}).Find(&comments).Error; err != nil {
+		return "", err
+	}
+
+	return pagination.Trim(query, comments, func(comment domain.Comment) pagination.Cursor {
+		return pagination.NewCursor(comment.CreatedAt, comment.ID)
+	}), nil
+}
+
+// CountByPhoto tells how many top-level comments, tombstones included, were left
+// on the photo with photoID, which is what FetchByPhoto pages through.
+func (commentRepository *commentRepository) CountByPhoto(ctx context.Context, photoID string) (count int64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = commentRepository.db.WithContext(ctx).Model(&domain.Comment{}).Where("photo_id = ? AND parent_id IS NULL", photoID).Count(&count).Error; err != nil {
+		return 0, err
 	}
 
 	return
@@ -62,41 +109,107 @@ func (commentRepository *commentRepository) GetByID(ctx context.Context, comment
 	return
 }
 
-func (commentRepository *commentRepository) Update(ctx context.Context, comment domain.Comment, id string) (photo domain.Photo, err error) {
+// GetDetailByID loads the comment with id along with its author, the photo it
+// was left on and how many replies it has.
+func (commentRepository *commentRepository) GetDetailByID(ctx context.Context, comment *domain.Comment, id string) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	c := domain.Comment{}
+	if err = commentRepository.db.WithContext(ctx).Scopes(domain.WithReplyCount).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "display_name", "profile_image_url")
+	}).Preload("Photo", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "user_id", "title", "photo_url", "caption")
+	}).First(&comment, "comments.id = ?", id).Error; err != nil {
+		return err
+	}
 
-	photo = domain.Photo{}
+	return
+}
 
-	if err = commentRepository.db.WithContext(ctx).First(&c, &id).Error; err != nil {
-		return photo, err
-	}
+// Update applies the non-zero fields of updatedComment to comment, which callers
+// have already loaded, and returns the photo the comment belongs to.
+func (commentRepository *commentRepository) Update(ctx context.Context, comment domain.Comment, updatedComment domain.Comment) (photo domain.Photo, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
-	if err = commentRepository.db.WithContext(ctx).Model(&c).Updates(comment).Error; err != nil {
+	defer cancel()
+
+	photo = domain.Photo{}
+
+	if err = commentRepository.db.WithContext(ctx).Model(&comment).Updates(updatedComment).Error; err != nil {
 		return photo, err
 	}
 
-	if err = commentRepository.db.WithContext(ctx).First(&photo, comment.PhotoID).Error; err != nil {
+	if err = commentRepository.db.WithContext(ctx).First(&photo, "id = ?", comment.PhotoID).Error; err != nil {
 		return photo, err
 	}
 
 	return photo, nil
 }
 
+// Delete removes the comment with id. A comment with replies is kept as a
+// tombstone instead so the conversation under it still reads, and a tombstone
+// goes away along with its last reply.
 func (commentRepository *commentRepository) Delete(ctx context.Context, id string) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	if err = commentRepository.db.WithContext(ctx).First(&domain.Comment{}, &id).Error; err != nil {
-		return err
-	}
+	return commentRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var comment domain.Comment
 
-	if err = commentRepository.db.WithContext(ctx).Delete(&domain.Comment{}, &id).Error; err != nil {
-		return err
+		// The lock keeps replies from being added to the comment while it goes.
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&comment, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		replies, err := countReplies(tx, comment.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if replies > 0 {
+			return tx.Model(&comment).UpdateColumns(map[string]interface{}{
+				"message":    domain.CommentTombstone,
+				"deleted":    true,
+				"updated_at": time.Now(),
+			}).Error
+		}
+
+		if err = tx.Delete(&domain.Comment{}, "id = ?", comment.ID).Error; err != nil {
+			return err
+		}
+
+		for parentID := comment.ParentID; parentID != nil; {
+			var parent domain.Comment
+
+			if err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&parent, "id = ?", *parentID).Error; err != nil {
+				return err
+			}
+
+			if !parent.Deleted {
+				return nil
+			}
+
+			if replies, err = countReplies(tx, parent.ID); err != nil || replies > 0 {
+				return err
+			}
+
+			if err = tx.Delete(&domain.Comment{}, "id = ?", parent.ID).Error; err != nil {
+				return err
+			}
+
+			parentID = parent.ParentID
+		}
+
+		return nil
+	})
+}
+
+func countReplies(tx *gorm.DB, id string) (count int64, err error) {
+	if err = tx.Model(&domain.Comment{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
 	}
 
 	return
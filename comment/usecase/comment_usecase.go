@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"mygram-api/domain"
+	"mygram-api/pagination"
 )
 
 type commentUseCase struct {
@@ -13,15 +14,65 @@ func NewCommentUseCase(commentRepository domain.CommentRepository) *commentUseCa
 	return &commentUseCase{commentRepository}
 }
 
-func (commentUseCase *commentUseCase) Fetch(ctx context.Context, comments *[]domain.Comment, userID string) (err error) {
-	if err = commentUseCase.commentRepository.Fetch(ctx, comments, userID); err != nil {
-		return err
+func (commentUseCase *commentUseCase) Fetch(ctx context.Context, comments *[]domain.Comment, userID string, query pagination.Query) (next string, err error) {
+	if next, err = commentUseCase.commentRepository.Fetch(ctx, comments, userID, query); err != nil {
+		return "", err
 	}
 
-	return
+	return next, nil
+}
+
+// FetchByPhoto returns a page of the comments on the photo with photoID along
+// with how many comments the photo has in total.
+func (commentUseCase *commentUseCase) FetchByPhoto(ctx context.Context, comments *[]domain.Comment, photoID string, query pagination.Query) (next string, total int64, err error) {
+	if next, err = commentUseCase.commentRepository.FetchByPhoto(ctx, comments, photoID, query); err != nil {
+		return "", 0, err
+	}
+
+	if total, err = commentUseCase.commentRepository.CountByPhoto(ctx, photoID); err != nil {
+		return "", 0, err
+	}
+
+	return next, total, nil
 }
 
+// FetchReplies returns a page of the direct replies to the comment with
+// parentID.
+func (commentUseCase *commentUseCase) FetchReplies(ctx context.Context, comments *[]domain.Comment, parentID string, query pagination.Query) (next string, err error) {
+	if next, err = commentUseCase.commentRepository.FetchReplies(ctx, comments, parentID, query); err != nil {
+		return "", err
+	}
+
+	return next, nil
+}
+
+// Store adds comment, as a reply when it has a ParentID. Replies go under a
+// comment on the same photo that hasn't been deleted, at most MaxCommentDepth
+// levels down.
 func (commentUseCase *commentUseCase) Store(ctx context.Context, comment *domain.Comment) (err error) {
+	if comment.ParentID != nil && *comment.ParentID == "" {
+		comment.ParentID = nil
+	}
+
+	if comment.ParentID != nil {
+		var parent domain.Comment
+
+		if err = commentUseCase.commentRepository.GetByID(ctx, &parent, *comment.ParentID); err != nil {
+			return err
+		}
+
+		switch {
+		case parent.PhotoID != comment.PhotoID:
+			return domain.ErrCommentParentInvalid
+		case parent.Deleted:
+			return domain.ErrCommentParentDeleted
+		case parent.Depth >= domain.MaxCommentDepth:
+			return domain.ErrCommentTooDeep
+		}
+
+		comment.Depth = parent.Depth + 1
+	}
+
 	if err = commentUseCase.commentRepository.Store(ctx, comment); err != nil {
 		return err
 	}
@@ -37,8 +88,20 @@ func (commentUseCase *commentUseCase) GetByID(ctx context.Context, comment *doma
 	return
 }
 
-func (commentUseCase *commentUseCase) Update(ctx context.Context, comment domain.Comment, id string) (photo domain.Photo, err error) {
-	if photo, err = commentUseCase.commentRepository.Update(ctx, comment, id); err != nil {
+func (commentUseCase *commentUseCase) GetDetailByID(ctx context.Context, comment *domain.Comment, id string) (err error) {
+	if err = commentUseCase.commentRepository.GetDetailByID(ctx, comment, id); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (commentUseCase *commentUseCase) Update(ctx context.Context, comment domain.Comment, updatedComment domain.Comment) (photo domain.Photo, err error) {
+	if comment.Deleted {
+		return photo, domain.ErrCommentDeleted
+	}
+
+	if photo, err = commentUseCase.commentRepository.Update(ctx, comment, updatedComment); err != nil {
 		return photo, err
 	}
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"mygram-api/domain"
 	"mygram-api/domain/mocks"
+	"mygram-api/pagination"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/asaskevich/govalidator"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func TestFetch(t *testing.T) {
@@ -34,11 +36,115 @@ func TestFetch(t *testing.T) {
 	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
 
 	t.Run("fetch all comments correctly", func(t *testing.T) {
-		mockCommentRepository.On("Fetch", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mock.AnythingOfType("string")).Return(nil).Once()
+		mockCommentRepository.On("Fetch", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mock.AnythingOfType("string"), mock.AnythingOfType("pagination.Query")).Return("", nil).Once()
 
-		err := commentUseCase.Fetch(context.Background(), &mockComments, mockComment.UserID)
+		next, err := commentUseCase.Fetch(context.Background(), &mockComments, mockComment.UserID, pagination.Query{Limit: 20})
 
 		assert.NoError(t, err)
+		assert.Empty(t, next)
+	})
+
+	t.Run("fetch a page of comments", func(t *testing.T) {
+		mockCommentRepository.On("Fetch", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mockComment.UserID, pagination.Query{Limit: 1}).Return("next-cursor", nil).Once()
+
+		next, err := commentUseCase.Fetch(context.Background(), &mockComments, mockComment.UserID, pagination.Query{Limit: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", next)
+		mockCommentRepository.AssertExpectations(t)
+	})
+}
+
+func TestFetchByPhoto(t *testing.T) {
+	now := time.Now()
+	mockComments := []domain.Comment{
+		{ID: "comment-123", UserID: "user-123", PhotoID: "photo-123", Message: "A message", CreatedAt: &now, User: &domain.User{ID: "user-123", Username: "johndoe"}},
+	}
+	query := pagination.Query{Limit: 1, Sort: pagination.SortOldest}
+
+	mockCommentRepository := new(mocks.CommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+
+	t.Run("fetch the comments of a photo correctly", func(t *testing.T) {
+		var comments []domain.Comment
+
+		mockCommentRepository.On("FetchByPhoto", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), "photo-123", query).Run(func(args mock.Arguments) {
+			*args.Get(1).(*[]domain.Comment) = mockComments
+		}).Return("next-cursor", nil).Once()
+		mockCommentRepository.On("CountByPhoto", mock.Anything, "photo-123").Return(int64(2), nil).Once()
+
+		next, total, err := commentUseCase.FetchByPhoto(context.Background(), &comments, "photo-123", query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", next)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, mockComments, comments)
+		mockCommentRepository.AssertExpectations(t)
+	})
+
+	t.Run("fetch the comments of a photo with a failing repository", func(t *testing.T) {
+		var comments []domain.Comment
+
+		mockCommentRepository.On("FetchByPhoto", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), "photo-234", query).Return("", errors.New("connection refused")).Once()
+
+		next, total, err := commentUseCase.FetchByPhoto(context.Background(), &comments, "photo-234", query)
+
+		assert.Error(t, err)
+		assert.Empty(t, next)
+		assert.Zero(t, total)
+		mockCommentRepository.AssertExpectations(t)
+	})
+
+	t.Run("fetch the comments of a photo with a failing count", func(t *testing.T) {
+		var comments []domain.Comment
+
+		mockCommentRepository.On("FetchByPhoto", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), "photo-345", query).Return("", nil).Once()
+		mockCommentRepository.On("CountByPhoto", mock.Anything, "photo-345").Return(int64(0), errors.New("connection refused")).Once()
+
+		next, total, err := commentUseCase.FetchByPhoto(context.Background(), &comments, "photo-345", query)
+
+		assert.Error(t, err)
+		assert.Empty(t, next)
+		assert.Zero(t, total)
+		mockCommentRepository.AssertExpectations(t)
+	})
+}
+
+func TestFetchReplies(t *testing.T) {
+	now := time.Now()
+	parentID := "comment-123"
+	mockReplies := []domain.Comment{
+		{ID: "comment-234", UserID: "user-234", PhotoID: "photo-123", ParentID: &parentID, Message: "A reply", Depth: 1, CreatedAt: &now},
+	}
+	query := pagination.Query{Limit: 20, Sort: pagination.SortOldest}
+
+	mockCommentRepository := new(mocks.CommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+
+	t.Run("fetch the replies to a comment correctly", func(t *testing.T) {
+		var replies []domain.Comment
+
+		mockCommentRepository.On("FetchReplies", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), parentID, query).Run(func(args mock.Arguments) {
+			*args.Get(1).(*[]domain.Comment) = mockReplies
+		}).Return("", nil).Once()
+
+		next, err := commentUseCase.FetchReplies(context.Background(), &replies, parentID, query)
+
+		assert.NoError(t, err)
+		assert.Empty(t, next)
+		assert.Equal(t, mockReplies, replies)
+		mockCommentRepository.AssertExpectations(t)
+	})
+
+	t.Run("fetch the replies to a comment with a failing repository", func(t *testing.T) {
+		var replies []domain.Comment
+
+		mockCommentRepository.On("FetchReplies", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), parentID, query).Return("", errors.New("connection refused")).Once()
+
+		_, err := commentUseCase.FetchReplies(context.Background(), &replies, parentID, query)
+
+		assert.Error(t, err)
+		mockCommentRepository.AssertExpectations(t)
 	})
 }
 
@@ -147,6 +253,80 @@ func TestStore(t *testing.T) {
 	})
 }
 
+func TestStoreReply(t *testing.T) {
+	parent := func(id string, photoID string, depth int, deleted bool) domain.Comment {
+		return domain.Comment{ID: id, UserID: "user-234", PhotoID: photoID, Message: "A comment", Depth: depth, Deleted: deleted}
+	}
+	reply := func(parentID string) domain.Comment {
+		return domain.Comment{UserID: "user-123", PhotoID: "photo-123", ParentID: &parentID, Message: "A reply"}
+	}
+
+	mockCommentRepository := new(mocks.CommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+
+	mockCommentRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Comment"), mock.AnythingOfType("string")).Return(func(ctx context.Context, comment *domain.Comment, id string) error {
+		switch id {
+		case "comment-top":
+			*comment = parent(id, "photo-123", 0, false)
+		case "comment-other-photo":
+			*comment = parent(id, "photo-234", 0, false)
+		case "comment-deleted":
+			*comment = parent(id, "photo-123", 0, true)
+		case "comment-deepest":
+			*comment = parent(id, "photo-123", domain.MaxCommentDepth, false)
+		default:
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+
+	t.Run("add a reply correctly", func(t *testing.T) {
+		comment := reply("comment-top")
+
+		mockCommentRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(nil).Once()
+
+		err := commentUseCase.Store(context.Background(), &comment)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, comment.Depth)
+		mockCommentRepository.AssertExpectations(t)
+	})
+
+	t.Run("add a comment with an empty parent id", func(t *testing.T) {
+		comment := reply("")
+
+		mockCommentRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(nil).Once()
+
+		err := commentUseCase.Store(context.Background(), &comment)
+
+		assert.NoError(t, err)
+		assert.Nil(t, comment.ParentID)
+		assert.Equal(t, 0, comment.Depth)
+		mockCommentRepository.AssertExpectations(t)
+	})
+
+	for _, tc := range []struct {
+		name     string
+		parentID string
+		err      error
+	}{
+		{"add a reply to a comment that doesn't exist", "comment-404", gorm.ErrRecordNotFound},
+		{"add a reply to a comment on another photo", "comment-other-photo", domain.ErrCommentParentInvalid},
+		{"add a reply to a deleted comment", "comment-deleted", domain.ErrCommentParentDeleted},
+		{"add a reply nested too deep", "comment-deepest", domain.ErrCommentTooDeep},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			comment := reply(tc.parentID)
+
+			err := commentUseCase.Store(context.Background(), &comment)
+
+			assert.ErrorIs(t, err, tc.err)
+			mockCommentRepository.AssertNumberOfCalls(t, "Store", 2)
+		})
+	}
+}
+
 func TestGetBy(t *testing.T) {
 	var mockComment *domain.Comment
 
@@ -189,6 +369,49 @@ func TestGetBy(t *testing.T) {
 	})
 }
 
+func TestGetDetailByID(t *testing.T) {
+	now := time.Now()
+
+	mockComment := domain.Comment{
+		ID:        "comment-123",
+		UserID:    "user-123",
+		PhotoID:   "photo-123",
+		Message:   "A message",
+		CreatedAt: &now,
+		UpdatedAt: &now,
+		User:      &domain.User{ID: "user-123", Username: "johndoe"},
+		Photo:     &domain.Photo{ID: "photo-123", Title: "A Title", UserID: "user-234"},
+	}
+
+	mockCommentRepository := new(mocks.CommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+
+	t.Run("get detail by id correctly", func(t *testing.T) {
+		var comment domain.Comment
+
+		mockCommentRepository.On("GetDetailByID", mock.Anything, mock.AnythingOfType("*domain.Comment"), "comment-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Comment) = mockComment
+		}).Return(nil).Once()
+
+		err := commentUseCase.GetDetailByID(context.Background(), &comment, "comment-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockComment, comment)
+		mockCommentRepository.AssertExpectations(t)
+	})
+
+	t.Run("get detail by id with not found comment", func(t *testing.T) {
+		var comment domain.Comment
+
+		mockCommentRepository.On("GetDetailByID", mock.Anything, mock.AnythingOfType("*domain.Comment"), "comment-234").Return(gorm.ErrRecordNotFound).Once()
+
+		err := commentUseCase.GetDetailByID(context.Background(), &comment, "comment-234")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockCommentRepository.AssertExpectations(t)
+	})
+}
+
 func TestUpdate(t *testing.T) {
 	now := time.Now()
 	mockUpdatedComment := domain.Photo{
@@ -204,14 +427,14 @@ func TestUpdate(t *testing.T) {
 	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
 
 	t.Run("update comment correctly", func(t *testing.T) {
-		tempMockCommentID := "comment-123"
+		tempMockComment := domain.Comment{ID: "comment-123", UserID: "user-123"}
 		tempMockUpdateComment := domain.Comment{
 			Message: "A new comment",
 		}
 
-		mockCommentRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Comment"), mock.AnythingOfType("string")).Return(mockUpdatedComment, nil).Once()
+		mockCommentRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Comment"), mock.AnythingOfType("domain.Comment")).Return(mockUpdatedComment, nil).Once()
 
-		comment, err := commentUseCase.Update(context.Background(), tempMockUpdateComment, tempMockCommentID)
+		comment, err := commentUseCase.Update(context.Background(), tempMockComment, tempMockUpdateComment)
 
 		assert.NoError(t, err)
 
@@ -238,14 +461,14 @@ func TestUpdate(t *testing.T) {
 	})
 
 	t.Run("update comment with empty message", func(t *testing.T) {
-		tempMockCommentID := "Comment-123"
+		tempMockComment := domain.Comment{ID: "Comment-123", UserID: "user-123"}
 		tempMockUpdateComment := domain.Comment{
 			Message: "",
 		}
 
-		mockCommentRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Comment"), mock.AnythingOfType("string")).Return(mockUpdatedComment, nil).Once()
+		mockCommentRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Comment"), mock.AnythingOfType("domain.Comment")).Return(mockUpdatedComment, nil).Once()
 
-		comment, err := commentUseCase.Update(context.Background(), tempMockUpdateComment, tempMockCommentID)
+		comment, err := commentUseCase.Update(context.Background(), tempMockComment, tempMockUpdateComment)
 
 		assert.NoError(t, err)
 
@@ -272,12 +495,12 @@ func TestUpdate(t *testing.T) {
 	})
 
 	t.Run("update comment with not contain property", func(t *testing.T) {
-		tempMockCommentID := "comment-123"
+		tempMockComment := domain.Comment{ID: "comment-123", UserID: "user-123"}
 		tempMockUpdateComment := domain.Comment{}
 
-		mockCommentRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Comment"), mock.AnythingOfType("string")).Return(mockUpdatedComment, nil).Once()
+		mockCommentRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Comment"), mock.AnythingOfType("domain.Comment")).Return(mockUpdatedComment, nil).Once()
 
-		comment, err := commentUseCase.Update(context.Background(), tempMockUpdateComment, tempMockCommentID)
+		comment, err := commentUseCase.Update(context.Background(), tempMockComment, tempMockUpdateComment)
 
 		assert.NoError(t, err)
 
@@ -302,6 +525,15 @@ func TestUpdate(t *testing.T) {
 		assert.Equal(t, mockUpdatedComment.UpdatedAt, tempMockUpdatedComment.UpdatedAt)
 		mockCommentRepository.AssertExpectations(t)
 	})
+
+	t.Run("update a deleted comment", func(t *testing.T) {
+		tempMockComment := domain.Comment{ID: "comment-123", UserID: "user-123", Message: domain.CommentTombstone, Deleted: true}
+
+		_, err := commentUseCase.Update(context.Background(), tempMockComment, domain.Comment{Message: "A new comment"})
+
+		assert.ErrorIs(t, err, domain.ErrCommentDeleted)
+		mockCommentRepository.AssertExpectations(t)
+	})
 }
 
 func TestDelete(t *testing.T) {
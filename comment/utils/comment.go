@@ -8,6 +8,13 @@ type User struct {
 	Email    string `json:"email"`
 }
 
+type Owner struct {
+	ID              string `json:"id" example:"here is the generated user id"`
+	Username        string `json:"username" example:"johndoe"`
+	DisplayName     string `json:"display_name" example:"John Doe"`
+	ProfileImageUrl string `json:"profile_image_url" example:"https://www.example.com/image.jpg"`
+}
+
 type Photo struct {
 	ID       string `json:"id"`
 	Title    string `json:"title"`
@@ -28,19 +35,64 @@ type FetchedComment struct {
 }
 
 type ResponseDataFetchedComment struct {
-	Status string           `json:"status" example:"success"`
-	Data   []FetchedComment `json:"data"`
+	Status     string           `json:"status" example:"success"`
+	Data       []FetchedComment `json:"data"`
+	NextCursor string           `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6InBob3RvLTEyMyJ9"`
+}
+
+type CommentDetail struct {
+	PhotoComment
+	PhotoID string `json:"photo_id"`
+	Photo   *Photo `json:"photo"`
+}
+
+type ResponseDataCommentDetail struct {
+	Status string        `json:"status" example:"success"`
+	Data   CommentDetail `json:"data"`
+}
+
+type PhotoComment struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	ParentID   *string    `json:"parent_id,omitempty"`
+	Message    string     `json:"message"`
+	Deleted    bool       `json:"deleted" example:"false"`
+	ReplyCount int64      `json:"reply_count" example:"3"`
+	LikeCount  int64      `json:"like_count" example:"7"`
+	LikedByMe  bool       `json:"liked_by_me" example:"false"`
+	CreatedAt  *time.Time `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+	User       *Owner     `json:"user"`
+}
+
+type PhotoComments struct {
+	Total    int64          `json:"total" example:"42"`
+	Comments []PhotoComment `json:"comments"`
+}
+
+type ResponseDataPhotoComments struct {
+	Status     string        `json:"status" example:"success"`
+	Data       PhotoComments `json:"data"`
+	NextCursor string        `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6ImNvbW1lbnQtMTIzIn0"`
+}
+
+type ResponseDataReplies struct {
+	Status     string         `json:"status" example:"success"`
+	Data       []PhotoComment `json:"data"`
+	NextCursor string         `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6ImNvbW1lbnQtMTIzIn0"`
 }
 
 type AddComment struct {
-	Message string `json:"message" example:"A comment"`
-	PhotoID string `json:"photo_id" example:"photo-123"`
+	Message  string `json:"message" example:"A comment"`
+	PhotoID  string `json:"photo_id" example:"photo-123"`
+	ParentID string `json:"parent_id,omitempty" example:"comment-123"`
 }
 
 type AddedComment struct {
 	ID        string     `json:"id" example:"here is the generated comment id"`
 	UserID    string     `json:"user_id" example:"here is the generated user id"`
 	PhotoID   string     `json:"photo_id" example:"here is the generated photo id"`
+	ParentID  *string    `json:"parent_id,omitempty" example:"comment-123"`
 	Message   string     `json:"message" example:"A comment"`
 	CreatedAt *time.Time `json:"created_at" example:"the created at generated here"`
 }
@@ -0,0 +1,53 @@
+package utils
+
+import "mygram-api/domain"
+
+// NewPhotoComments shapes a page of comments for a thread, liked tells which of
+// them the caller likes.
+func NewPhotoComments(comments []domain.Comment, liked map[string]bool) []PhotoComment {
+	shaped := []PhotoComment{}
+
+	for _, comment := range comments {
+		shaped = append(shaped, NewPhotoComment(comment, liked[comment.ID]))
+	}
+
+	return shaped
+}
+
+// NewPhotoComment shapes comment for a thread. Deleted comments kept for their
+// replies don't tell who wrote them.
+func NewPhotoComment(comment domain.Comment, likedByMe bool) PhotoComment {
+	shaped := PhotoComment{
+		ID:         comment.ID,
+		UserID:     comment.UserID,
+		ParentID:   comment.ParentID,
+		Message:    comment.Message,
+		Deleted:    comment.Deleted,
+		ReplyCount: comment.ReplyCount,
+		LikeCount:  comment.LikeCount,
+		LikedByMe:  likedByMe,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+		User:       newOwner(comment.User),
+	}
+
+	if comment.Deleted {
+		shaped.UserID, shaped.User = "", nil
+	}
+
+	return shaped
+}
+
+// newOwner summarizes the author of a comment.
+func newOwner(user *domain.User) *Owner {
+	if user == nil {
+		return nil
+	}
+
+	return &Owner{
+		ID:              user.ID,
+		Username:        user.Username,
+		DisplayName:     user.DisplayName,
+		ProfileImageUrl: user.ProfileImageUrl,
+	}
+}
@@ -34,7 +34,7 @@ func StartDB() *gorm.DB {
 		log.Fatal("Error connecting to database: ", err)
 	}
 
-	if err = db.AutoMigrate(&domain.User{}, &domain.Photo{}, &domain.Comment{}, &domain.SocialMedia{}); err != nil {
+	if err = db.AutoMigrate(&domain.User{}, &domain.Photo{}, &domain.PhotoVariant{}, &domain.Comment{}, &domain.PhotoLike{}, &domain.CommentLike{}, &domain.SocialMedia{}, &domain.RefreshToken{}, &domain.RevokedToken{}, &domain.UserRevocation{}, &domain.PasswordReset{}, &domain.RecoveryCode{}, &domain.LockoutEvent{}, &domain.APIKey{}, &domain.ExternalIdentity{}, &domain.Session{}); err != nil {
 		log.Fatal("Error migrating database: ", err.Error())
 	}
 
@@ -11,8 +11,8 @@ const docTemplate = `{
         "description": "{{escape .Description}}",
         "title": "{{.Title}}",
         "contact": {
-            "name": "musshal",
-            "email": "musthafafaishal@gmail.com"
+            "name": "hafidmust",
+            "email": "hafidalimustaqim13@gmail.com"
         },
         "license": {
             "name": "MIT License",
@@ -23,103 +23,129 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/comments": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Publish the public keys access tokens are signed with, as a JSON Web Key Set, so other services can verify them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Fetch the token verification keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.JWKSet"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get all comments with authentication user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Fetch every account, for admins only",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "admin"
                 ],
-                "summary": "Fetch all comments",
+                "summary": "Fetch all users",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataFetchedComment"
+                            "$ref": "#/definitions/utils.ResponseDataAdminUsers"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/users/{userId}": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "create and store a comment with authentication user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Delete any account but the last admin, for admins only",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "admin"
                 ],
-                "summary": "Add a comment",
+                "summary": "Delete a user",
                 "parameters": [
                     {
-                        "description": "Add Comment",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/utils.AddComment"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataAddedComment"
+                            "$ref": "#/definitions/utils.ResponseMessageAdminDeletedUser"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
             }
         },
-        "/comments/{id}": {
+        "/admin/users/{userId}/role": {
             "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update a comment by id with authentication user",
+                "description": "Make a user a regular user, a moderator or an admin, for admins only. The user is signed out everywhere so the new role applies at once. The last admin cannot be demoted",
                 "consumes": [
                     "application/json"
                 ],
@@ -127,24 +153,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "admin"
                 ],
-                "summary": "Update a comment",
+                "summary": "Change the role of a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Comment ID",
-                        "name": "id",
+                        "description": "User ID",
+                        "name": "userId",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update Comment",
+                        "description": "Change Role",
                         "name": "json",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/utils.UpdateComment"
+                            "$ref": "#/definitions/utils.ChangeRole"
                         }
                     }
                 ],
@@ -152,91 +178,167 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataUpdatedComment"
+                            "$ref": "#/definitions/utils.ResponseMessageChangedRole"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Delete a comment by id with authentication user",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/auth/{provider}/callback": {
+            "get": {
+                "description": "The provider redirects here. Links the identity to the account with the same verified email or creates a new account, then answers like /users/login",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "users"
                 ],
-                "summary": "Delete a comment",
+                "summary": "Finish signing in with a provider",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Comment ID",
-                        "name": "id",
+                        "description": "Provider",
+                        "name": "provider",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization Code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseMessageDeletedComment"
+                            "$ref": "#/definitions/utils.ResponseDataLoggedinUser"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataMFAPending"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_comment_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
             }
         },
-        "/photos": {
+        "/auth/{provider}/start": {
+            "get": {
+                "description": "Redirect to the OpenID Connect provider to sign in with the authorization code flow and PKCE",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Start signing in with a provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Get all photos with authentication user",
+                "description": "Get a page of the comments of the authenticated user, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -244,26 +346,58 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "photos"
+                    "comments"
+                ],
+                "summary": "Fetch all comments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, the next_cursor of the previous one",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "newest (default) or oldest first",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only items created after this RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only items created before this RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Fetch all photos",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataFetchedPhoto"
+                            "$ref": "#/definitions/utils.ResponseDataFetchedComment"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_photo_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_photo_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
@@ -272,9 +406,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Create and store a photo with authentication user",
+                "description": "create and store a comment with authentication user. A comment with a parent_id replies to that comment, which must be on the same photo, and replies nest at most 3 levels deep",
                 "consumes": [
                     "application/json"
                 ],
@@ -282,17 +419,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "photos"
+                    "comments"
                 ],
-                "summary": "Store a photo",
+                "summary": "Add a comment",
                 "parameters": [
                     {
-                        "description": "Add Photo",
+                        "description": "Add Comment",
                         "name": "json",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/utils.AddPhoto"
+                            "$ref": "#/definitions/utils.AddComment"
                         }
                     }
                 ],
@@ -300,32 +437,47 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataAddedPhoto"
+                            "$ref": "#/definitions/utils.ResponseDataAddedComment"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_photo_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_photo_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
             }
         },
-        "/photos/{id}": {
-            "put": {
+        "/comments/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Update a photo by id with authentication user",
+                "description": "Get a comment by id with its author and the photo it was left on",
                 "consumes": [
                     "application/json"
                 ],
@@ -333,61 +485,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "photos"
+                    "comments"
                 ],
-                "summary": "Update a photo",
+                "summary": "Fetch a comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Photo ID",
+                        "description": "Comment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Photo",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/utils.UpdatePhoto"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataUpdatedPhoto"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/mygram-api_photo_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseDataCommentDetail"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_photo_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_photo_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Delete a photo by id with authentication user",
+                "description": "Update a comment by id with authentication user",
                 "consumes": [
                     "application/json"
                 ],
@@ -395,54 +541,70 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "photos"
+                    "comments"
                 ],
-                "summary": "Delete a photo",
+                "summary": "Update a comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Photo ID",
+                        "description": "Comment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update Comment",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.UpdateComment"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseMessageDeletedPhoto"
+                            "$ref": "#/definitions/utils.ResponseDataUpdatedComment"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_photo_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_photo_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_photo_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
-            }
-        },
-        "/socialmedias": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Get all social media with authentication user",
+                "description": "Delete a comment by id with authentication user. Moderators and admins can delete any comment",
                 "consumes": [
                     "application/json"
                 ],
@@ -450,150 +612,177 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "socialmedias"
+                    "comments"
+                ],
+                "summary": "Delete a comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Fetch all social media",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataFetchedSocialMedia"
+                            "$ref": "#/definitions/utils.ResponseMessageDeletedComment"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/comments/{id}/like": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Create and store a social media with authentication user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Like a comment as the authenticated user. Liking a comment twice counts once",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "socialmedias"
+                    "likes"
                 ],
-                "summary": "Add a social media",
+                "summary": "Like a comment",
                 "parameters": [
                     {
-                        "description": "Add Social Media",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/utils.AddSocialMedia"
-                        }
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataAddedSocialMedia"
+                            "$ref": "#/definitions/utils.ResponseDataLiked"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
-            }
-        },
-        "/socialmedias/{id}": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Update a social media by id with authentication user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Take back the like of the authenticated user on a comment, if there was one",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "socialmedias"
+                    "likes"
                 ],
-                "summary": "Update a social media",
+                "summary": "Unlike a comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SocialMedia ID",
+                        "description": "Comment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update Social Media",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/utils.UpdateSocialMedia"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataUpdatedSocialMedia"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/mygram-api_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseDataLiked"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/comments/{id}/replies": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Delete a social media by id with authentication user",
+                "description": "Get a page of the direct replies to a comment, oldest first unless sort says otherwise, each with a summary of its author and how many replies it has in turn",
                 "consumes": [
                     "application/json"
                 ],
@@ -601,54 +790,93 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "socialmedias"
+                    "comments"
                 ],
-                "summary": "Delete a social media",
+                "summary": "Fetch the replies to a comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SocialMedia ID",
+                        "description": "Comment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, the next_cursor of the previous one",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "oldest (default) or newest first",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only items created after this RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only items created before this RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseMessageDeletedSocialMedia"
+                            "$ref": "#/definitions/utils.ResponseDataReplies"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_socialmedia_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
             }
         },
-        "/users": {
-            "put": {
+        "/photos": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Update a user with authentication user",
+                "description": "Get a page of photos, newest first, with authentication user. Uploaded photos list smaller variants, narrowest first, once they have been rendered",
                 "consumes": [
                     "application/json"
                 ],
@@ -656,54 +884,78 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "photos"
                 ],
-                "summary": "Update a user",
+                "summary": "Fetch all photos",
                 "parameters": [
                     {
-                        "description": "Update User",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/utils.UpdateUser"
-                        }
+                        "type": "string",
+                        "description": "Only photos of this user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, the next_cursor of the previous one",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "newest (default) or oldest first",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only items created after this RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only items created before this RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataUpdatedUser"
+                            "$ref": "#/definitions/utils.ResponseDataFetchedPhoto"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_user_utils.ResponseMessage"
-                        }
-                    },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/mygram-api_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
                     }
                 ],
-                "description": "Delete a user with authentication user",
+                "description": "Create and store a photo with authentication user",
                 "consumes": [
                     "application/json"
                 ],
@@ -711,86 +963,142 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "photos"
+                ],
+                "summary": "Store a photo",
+                "parameters": [
+                    {
+                        "description": "Add Photo",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.AddPhoto"
+                        }
+                    }
                 ],
-                "summary": "Delete a user",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseMessageDeletedUser"
+                            "$ref": "#/definitions/utils.ResponseDataAddedPhoto"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
             }
         },
-        "/users/login": {
+        "/photos/upload": {
             "post": {
-                "description": "Authentication a user and retrieve a token",
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Upload a JPEG, PNG, GIF or WebP image of at most 10 MB and store it as a photo of the authenticated user. The image is turned upright and stored without its EXIF data, camera details and where it was taken are kept with the photo. The location stays private until published",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "photos"
                 ],
-                "summary": "Login a user",
+                "summary": "Upload a photo",
                 "parameters": [
                     {
-                        "description": "Login User",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/utils.LoginUser"
-                        }
+                        "type": "file",
+                        "description": "Image",
+                        "name": "photo",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Title",
+                        "name": "title",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Caption",
+                        "name": "caption",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataLoggedinUser"
+                            "$ref": "#/definitions/utils.ResponseDataAddedPhoto"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
             }
         },
-        "/users/register": {
-            "post": {
-                "description": "create and store a user",
+        "/photos/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Get a photo by id with its owner, variants, how many comments it has and the first 20 top-level ones, oldest first, each with its reply count. The rest follow from GET /photos/{id}/comments with next_comments_cursor as the cursor",
                 "consumes": [
                     "application/json"
                 ],
@@ -798,359 +1106,3810 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "photos"
                 ],
-                "summary": "Register a user",
+                "summary": "Fetch a photo",
                 "parameters": [
                     {
-                        "description": "Register User",
+                        "type": "string",
+                        "description": "Photo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataPhotoDetail"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Update a photo by id with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Update a photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Photo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Photo",
                         "name": "json",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/utils.RegisterUser"
+                            "$ref": "#/definitions/utils.UpdatePhoto"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/utils.ResponseDataRegisteredUser"
+                            "$ref": "#/definitions/utils.ResponseDataUpdatedPhoto"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Delete a photo by id with authentication user. Moderators and admins can delete any photo",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Delete a photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Photo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageDeletedPhoto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/photos/{id}/comments": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Get a page of the top-level comments left on a photo, oldest first unless sort says otherwise, each with a summary of its author and how many replies it has. total counts every comment on the photo, replies included. Deleted comments that have replies stay as \"[deleted]\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Fetch the comments of a photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Photo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only comments of this user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, the next_cursor of the previous one",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "oldest (default) or newest first",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only items created after this RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only items created before this RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataPhotoComments"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mygram-api_user_utils.ResponseMessage"
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/photos/{id}/like": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Like a photo as the authenticated user. Liking a photo twice counts once",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "likes"
+                ],
+                "summary": "Like a photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Photo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataLiked"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Take back the like of the authenticated user on a photo, if there was one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "likes"
+                ],
+                "summary": "Unlike a photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Photo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataLiked"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/photos/{id}/likes": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Get a page of the users who like a photo, most recent first, along with how many likes it has in total",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "likes"
+                ],
+                "summary": "Fetch the likes of a photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Photo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, the next_cursor of the previous one",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "newest (default) or oldest first",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataPhotoLikes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/photos/{id}/location": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Show or hide where an uploaded photo was taken, as read from its EXIF data. Locations are hidden until their owner publishes them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Publish the location of a photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Photo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Publish Location",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.PublishLocation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataPublishedLocation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/socialmedias": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Get a page of the social media of the authenticated user, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "socialmedias"
+                ],
+                "summary": "Fetch all social media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page, the next_cursor of the previous one",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "newest (default) or oldest first",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only items created after this RFC 3339 time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only items created before this RFC 3339 time",
+                        "name": "created_before",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataFetchedSocialMedia"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Create and store a social media with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "socialmedias"
+                ],
+                "summary": "Add a social media",
+                "parameters": [
+                    {
+                        "description": "Add Social Media",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.AddSocialMedia"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataAddedSocialMedia"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/socialmedias/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Get a social media by id with its owner",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "socialmedias"
+                ],
+                "summary": "Fetch a social media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SocialMedia ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataSocialMediaDetail"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Update a social media by id with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "socialmedias"
+                ],
+                "summary": "Update a social media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SocialMedia ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Social Media",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.UpdateSocialMedia"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataUpdatedSocialMedia"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    },
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "description": "Delete a social media by id with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "socialmedias"
+                ],
+                "summary": "Delete a social media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SocialMedia ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageDeletedSocialMedia"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update the fields of the authenticated user that are present in the body, the others stay as they are. A new email only replaces the current one once it is verified",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "description": "Update User",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.UpdateUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataUpdatedUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a user with authentication user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageDeletedUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update the fields of the authenticated user that are present in the body, the others stay as they are. A new email only replaces the current one once it is verified",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "description": "Update User",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.UpdateUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataUpdatedUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Enable two-factor authentication with a code from the authenticator app and retrieve one-time recovery codes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Confirm two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Confirm Two-Factor Authentication",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.MFAConfirm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataMFARecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Disable two-factor authentication with the password and a current code or a recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Disable two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Disable Two-Factor Authentication",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.MFADisable"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageMFADisabled"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa/setup": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Generate a TOTP secret for the authenticated user. It only takes effect once confirmed with a code",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set up two-factor authentication",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataMFASetup"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Fetch the API keys of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Fetch API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataAPIKeys"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create an API key for scripts and integrations. The key is only shown in this response, send it in the X-API-Key header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create an API key",
+                "parameters": [
+                    {
+                        "description": "Create API Key",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.CreateAPIKey"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataCreatedAPIKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/api-keys/{apiKeyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete an API key of the authenticated user, it stops working right away",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API Key ID",
+                        "name": "apiKeyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageDeletedAPIKey"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "description": "Authentication a user and retrieve an access token and a refresh token. Users with two-factor authentication get an mfa token to complete the login at /users/login/2fa instead",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Login a user",
+                "parameters": [
+                    {
+                        "description": "Login User",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.LoginUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataLoggedinUser"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataMFAPending"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login/2fa": {
+            "post": {
+                "description": "Exchange the mfa token from the login response and a current code or a recovery code for an access token and a refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Complete a two-factor login",
+                "parameters": [
+                    {
+                        "description": "Two-Factor Login",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.MFALogin"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataLoggedinUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "End the current session, revoking its access and refresh tokens, and revoke the refresh token given in the body",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Logout a user",
+                "parameters": [
+                    {
+                        "description": "Logout",
+                        "name": "json",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Logout"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageLoggedOut"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout-all": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revoke every access token and refresh token of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Logout a user everywhere",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageLoggedOut"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Fetch the profile of the authenticated user, including the private fields only they get to see",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Fetch the authenticated user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataMyProfile"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update the display name, bio, age and avatar of the authenticated user. Fields left out stay as they are",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update the profile of the authenticated user",
+                "parameters": [
+                    {
+                        "description": "Update Profile",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.UpdateProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataMyProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/avatar": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replace the avatar of the authenticated user with a JPEG, PNG, GIF or WebP image of at most 5 MB. It is cropped to a square and stored in several sizes without any of its metadata",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Upload an avatar",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Avatar image",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataAvatar"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove the avatar of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete the avatar",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageDeletedAvatar"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Change the password of the authenticated user, sign out every other login and retrieve fresh tokens",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Change a password",
+                "parameters": [
+                    {
+                        "description": "Change Password",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.ChangePassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataRefreshedToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password/forgot": {
+            "post": {
+                "description": "Mail a single-use password reset link to the account registered with the email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Request a password reset",
+                "parameters": [
+                    {
+                        "description": "Forgot Password",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.ForgotPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageForgotPassword"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password/reset": {
+            "post": {
+                "description": "Choose a new password with the token from the password reset email and sign out everywhere",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Reset a password",
+                "parameters": [
+                    {
+                        "description": "Reset Password",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResetPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageResetPassword"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/register": {
+            "post": {
+                "description": "create and store a user and mail a link to verify the email address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Register a user",
+                "parameters": [
+                    {
+                        "description": "Register User",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.RegisterUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataRegisteredUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Fetch the devices the authenticated user is logged in on, the one making the request is marked as current",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Fetch sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataSessions"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/sessions/history": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Fetch the latest logins of the authenticated user, including the sessions that have ended",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Fetch the login history",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataLoginHistory"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/sessions/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Log the authenticated user out on one of their devices, its tokens stop working right away",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "End a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageRevokedSession"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/token/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token and a rotated refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Refresh an access token",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.RefreshToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataRefreshedToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/verify-email": {
+            "post": {
+                "description": "Confirm the email address with the token from the verification email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Verify an email address",
+                "parameters": [
+                    {
+                        "description": "Verify Email",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/utils.VerifyEmail"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageVerifiedEmail"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/verify-email/resend": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mail a new verification link for the email address that still needs confirming",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Resend the verification email",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessageResentVerification"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}": {
+            "get": {
+                "description": "Fetch the public profile of a user by their username",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Fetch a user profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseDataPublicProfile"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ResponseMessage"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "helpers.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "helpers.JWKSet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/helpers.JWK"
+                    }
+                }
+            }
+        },
+        "mygram-api_comment_utils.Owner": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                },
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "mygram-api_like_utils.Owner": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                },
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "mygram-api_photo_utils.Owner": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                },
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "mygram-api_socialmedia_utils.Owner": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                },
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "utils.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "the created at generated here"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated api key id"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "the last used at generated here"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nightly backup"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "mgk_Xq3vB9aL"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "photos:read",
+                        "comments:write"
+                    ]
+                }
+            }
+        },
+        "utils.AddComment": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "A comment"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": "comment-123"
+                },
+                "photo_id": {
+                    "type": "string",
+                    "example": "photo-123"
+                }
+            }
+        },
+        "utils.AddPhoto": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string",
+                    "example": "A caption"
+                },
+                "photo_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "A Title"
+                }
+            }
+        },
+        "utils.AddSocialMedia": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Example"
+                },
+                "social_media_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/johndoe"
+                }
+            }
+        },
+        "utils.AddedComment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "the created at generated here"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated comment id"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "A comment"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": "comment-123"
+                },
+                "photo_id": {
+                    "type": "string",
+                    "example": "here is the generated photo id"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                }
+            }
+        },
+        "utils.AddedPhoto": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/utils.PhotoMetadata"
+                },
+                "photo_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.AddedSocialMedia": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "the created at generated here"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "the social media id generated here"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Example"
+                },
+                "social_media_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/johndoe"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                }
+            }
+        },
+        "utils.AdminUser": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "the created at generated here"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@example.com"
+                },
+                "email_verified_at": {
+                    "type": "string",
+                    "example": "the verified at generated here"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "two_factor": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "utils.Avatar": {
+            "type": "object",
+            "properties": {
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "/media/avatars/user-123/V1StGXR8Z5jdHi6B-512.jpg"
+                },
+                "sizes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.AvatarSize"
+                    }
+                }
+            }
+        },
+        "utils.AvatarSize": {
+            "type": "object",
+            "properties": {
+                "size": {
+                    "type": "integer",
+                    "example": 512
+                },
+                "url": {
+                    "type": "string",
+                    "example": "/media/avatars/user-123/V1StGXR8Z5jdHi6B-512.jpg"
+                }
+            }
+        },
+        "utils.ChangePassword": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "secret"
+                },
+                "new_password": {
+                    "type": "string",
+                    "example": "newsecret"
+                }
+            }
+        },
+        "utils.ChangeRole": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "moderator"
+                }
+            }
+        },
+        "utils.CommentDetail": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "id": {
+                    "type": "string"
+                },
+                "like_count": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "liked_by_me": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "message": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "photo": {
+                    "$ref": "#/definitions/utils.Photo"
+                },
+                "photo_id": {
+                    "type": "string"
+                },
+                "reply_count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/mygram-api_comment_utils.Owner"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.CreateAPIKey": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "nightly backup"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "photos:read",
+                        "comments:write"
+                    ]
+                }
+            }
+        },
+        "utils.CreatedAPIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "the created at generated here"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated api key id"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "the api key generated here, shown only once"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "the last used at generated here"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nightly backup"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "mgk_Xq3vB9aL"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "photos:read",
+                        "comments:write"
+                    ]
+                }
+            }
+        },
+        "utils.FetchedComment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "photo": {
+                    "$ref": "#/definitions/utils.Photo"
+                },
+                "photo_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/utils.User"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.FetchedPhoto": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "like_count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "liked_by_me": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "metadata": {
+                    "$ref": "#/definitions/utils.PhotoMetadata"
+                },
+                "photo_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/utils.User"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.PhotoVariant"
+                    }
+                }
+            }
+        },
+        "utils.ForgotPassword": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@example.com"
+                }
+            }
+        },
+        "utils.Liked": {
+            "type": "object",
+            "properties": {
+                "like_count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "liked": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "utils.LoggedinUser": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "the refresh token generated here"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "the token generated here"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "utils.LoginHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "the created at generated here"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated session id"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "the revoked at generated here"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (X11; Linux x86_64) Firefox/118.0"
+                }
+            }
+        },
+        "utils.LoginUser": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "secret"
+                }
+            }
+        },
+        "utils.Logout": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "the refresh token generated here"
+                }
+            }
+        },
+        "utils.MFAConfirm": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "utils.MFADisable": {
+            "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "secret"
+                }
+            }
+        },
+        "utils.MFALogin": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "mfa_token": {
+                    "type": "string",
+                    "example": "the mfa token from the login response"
+                }
+            }
+        },
+        "utils.MFAPending": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "mfa_token": {
+                    "type": "string",
+                    "example": "the mfa token generated here"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "mfa_pending"
+                }
+            }
+        },
+        "utils.MFARecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ABCDE-FGHIJ",
+                        "KLMNO-PQRST"
+                    ]
+                }
+            }
+        },
+        "utils.MFASetup": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "type": "string",
+                    "example": "otpauth://totp/MyGram:johndoe@example.com?algorithm=SHA1\u0026digits=6\u0026issuer=MyGram\u0026period=30\u0026secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "utils.MyProfile": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "bio": {
+                    "type": "string",
+                    "example": "Sunsets and street food."
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "the created at generated here"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@example.com"
+                },
+                "email_verified": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "follower_count": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                },
+                "mfa_enabled": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "pending_email": {
+                    "type": "string",
+                    "example": "newjohndoe@example.com"
+                },
+                "photo_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "social_medias": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.ProfileSocialMedia"
+                    }
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "utils.Photo": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "photo_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.PhotoComment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "id": {
+                    "type": "string"
+                },
+                "like_count": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "liked_by_me": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "message": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "reply_count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/mygram-api_comment_utils.Owner"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.PhotoComments": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.PhotoComment"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "utils.PhotoDetail": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "comment_count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.PhotoComment"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "like_count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "liked_by_me": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "metadata": {
+                    "$ref": "#/definitions/utils.PhotoMetadata"
+                },
+                "next_comments_cursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6ImNvbW1lbnQtMTIzIn0"
+                },
+                "photo_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/mygram-api_photo_utils.Owner"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.PhotoVariant"
+                    }
+                }
+            }
+        },
+        "utils.PhotoLike": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/mygram-api_like_utils.Owner"
+                }
+            }
+        },
+        "utils.PhotoLikes": {
+            "type": "object",
+            "properties": {
+                "likes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.PhotoLike"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "utils.PhotoLocation": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number",
+                    "example": 52.5163
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 13.3777
+                }
+            }
+        },
+        "utils.PhotoMetadata": {
+            "type": "object",
+            "properties": {
+                "camera_make": {
+                    "type": "string",
+                    "example": "Apple"
+                },
+                "camera_model": {
+                    "type": "string",
+                    "example": "iPhone 13"
+                },
+                "height": {
+                    "type": "integer",
+                    "example": 3024
+                },
+                "lens_model": {
+                    "type": "string",
+                    "example": "iPhone 13 back camera 5.1mm f/1.6"
+                },
+                "location": {
+                    "$ref": "#/definitions/utils.PhotoLocation"
+                },
+                "taken_at": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 4032
+                }
+            }
+        },
+        "utils.PhotoVariant": {
+            "type": "object",
+            "properties": {
+                "format": {
+                    "type": "string",
+                    "example": "jpeg"
+                },
+                "height": {
+                    "type": "integer",
+                    "example": 240
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image-320w.jpg"
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 320
+                }
+            }
+        },
+        "utils.ProfileSocialMedia": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated social media id"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Social Media"
+                },
+                "social_media_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/social-media"
+                }
+            }
+        },
+        "utils.PublicProfile": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string",
+                    "example": "Sunsets and street food."
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "the created at generated here"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "follower_count": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                },
+                "photo_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
+                },
+                "social_medias": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.ProfileSocialMedia"
+                    }
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "utils.PublishLocation": {
+            "type": "object",
+            "required": [
+                "public"
+            ],
+            "properties": {
+                "public": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "utils.PublishedLocation": {
+            "type": "object",
+            "properties": {
+                "location": {
+                    "$ref": "#/definitions/utils.PhotoLocation"
+                },
+                "public": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "utils.RefreshToken": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "the refresh token generated here"
+                }
+            }
+        },
+        "utils.RefreshedToken": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "the refresh token generated here"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "the token generated here"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "utils.RegisterUser": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "secret"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "utils.RegisteredUser": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@example.com"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "the user id generated here"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "johndoe"
+                }
+            }
+        },
+        "utils.ResetPassword": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "newsecret"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "the token from the password reset email"
+                }
+            }
+        },
+        "utils.ResponseDataAPIKeys": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.APIKey"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataAddedComment": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.AddedComment"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataAddedPhoto": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.AddedPhoto"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataAddedSocialMedia": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.AddedSocialMedia"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataAdminUsers": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.AdminUser"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataAvatar": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.Avatar"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataCommentDetail": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.CommentDetail"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
             }
-        }
-    },
-    "definitions": {
-        "mygram-api_comment_utils.ResponseMessage": {
+        },
+        "utils.ResponseDataCreatedAPIKey": {
             "type": "object",
             "properties": {
                 "data": {
-                    "type": "string",
-                    "example": "the error explained here"
+                    "$ref": "#/definitions/utils.CreatedAPIKey"
                 },
                 "status": {
                     "type": "string",
-                    "example": "fail"
+                    "example": "success"
                 }
             }
         },
-        "mygram-api_photo_utils.ResponseMessage": {
+        "utils.ResponseDataFetchedComment": {
             "type": "object",
             "properties": {
                 "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.FetchedComment"
+                    }
+                },
+                "next_cursor": {
                     "type": "string",
-                    "example": "the error explained here"
+                    "example": "eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6InBob3RvLTEyMyJ9"
                 },
                 "status": {
                     "type": "string",
-                    "example": "fail"
+                    "example": "success"
                 }
             }
         },
-        "mygram-api_socialmedia_utils.ResponseMessage": {
+        "utils.ResponseDataFetchedPhoto": {
             "type": "object",
             "properties": {
                 "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.FetchedPhoto"
+                    }
+                },
+                "next_cursor": {
                     "type": "string",
-                    "example": "the error explained here"
+                    "example": "eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6InBob3RvLTEyMyJ9"
                 },
                 "status": {
                     "type": "string",
-                    "example": "fail"
+                    "example": "success"
                 }
             }
         },
-        "mygram-api_user_utils.ResponseMessage": {
+        "utils.ResponseDataFetchedSocialMedia": {
             "type": "object",
             "properties": {
                 "data": {
+                    "$ref": "#/definitions/utils.SocialMedias"
+                },
+                "next_cursor": {
                     "type": "string",
-                    "example": "the error explained here"
+                    "example": "eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6InBob3RvLTEyMyJ9"
                 },
                 "status": {
                     "type": "string",
-                    "example": "fail"
+                    "example": "success"
                 }
             }
         },
-        "utils.AddComment": {
+        "utils.ResponseDataLiked": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "A comment"
+                "data": {
+                    "$ref": "#/definitions/utils.Liked"
                 },
-                "photo_id": {
+                "status": {
                     "type": "string",
-                    "example": "photo-123"
+                    "example": "success"
                 }
             }
         },
-        "utils.AddPhoto": {
+        "utils.ResponseDataLoggedinUser": {
             "type": "object",
             "properties": {
-                "caption": {
-                    "type": "string",
-                    "example": "A caption"
+                "data": {
+                    "$ref": "#/definitions/utils.LoggedinUser"
                 },
-                "photo_url": {
+                "status": {
                     "type": "string",
-                    "example": "https://www.example.com/image.jpg"
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataLoginHistory": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.LoginHistoryEntry"
+                    }
                 },
-                "title": {
+                "status": {
                     "type": "string",
-                    "example": "A Title"
+                    "example": "success"
                 }
             }
         },
-        "utils.AddSocialMedia": {
+        "utils.ResponseDataMFAPending": {
             "type": "object",
             "properties": {
-                "name": {
-                    "type": "string",
-                    "example": "Example"
+                "data": {
+                    "$ref": "#/definitions/utils.MFAPending"
                 },
-                "social_media_url": {
+                "status": {
                     "type": "string",
-                    "example": "https://www.example.com/johndoe"
+                    "example": "mfa_required"
                 }
             }
         },
-        "utils.AddedComment": {
+        "utils.ResponseDataMFARecoveryCodes": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "data": {
+                    "$ref": "#/definitions/utils.MFARecoveryCodes"
+                },
+                "status": {
                     "type": "string",
-                    "example": "the created at generated here"
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataMFASetup": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.MFASetup"
                 },
-                "id": {
+                "status": {
                     "type": "string",
-                    "example": "here is the generated comment id"
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataMyProfile": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.MyProfile"
                 },
-                "message": {
+                "status": {
                     "type": "string",
-                    "example": "A comment"
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataPhotoComments": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.PhotoComments"
                 },
-                "photo_id": {
+                "next_cursor": {
                     "type": "string",
-                    "example": "here is the generated photo id"
+                    "example": "eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6ImNvbW1lbnQtMTIzIn0"
                 },
-                "user_id": {
+                "status": {
                     "type": "string",
-                    "example": "here is the generated user id"
+                    "example": "success"
                 }
             }
         },
-        "utils.AddedPhoto": {
+        "utils.ResponseDataPhotoDetail": {
             "type": "object",
             "properties": {
-                "caption": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "photo_url": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/utils.PhotoDetail"
                 },
-                "user_id": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "success"
                 }
             }
         },
-        "utils.AddedSocialMedia": {
+        "utils.ResponseDataPhotoLikes": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "the created at generated here"
+                "data": {
+                    "$ref": "#/definitions/utils.PhotoLikes"
                 },
-                "id": {
+                "next_cursor": {
                     "type": "string",
-                    "example": "the social media id generated here"
+                    "example": "eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6Imxpa2UtMTIzIn0"
                 },
-                "name": {
+                "status": {
                     "type": "string",
-                    "example": "Example"
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataPublicProfile": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.PublicProfile"
                 },
-                "social_media_url": {
+                "status": {
                     "type": "string",
-                    "example": "https://www.example.com/johndoe"
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataPublishedLocation": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.PublishedLocation"
                 },
-                "user_id": {
+                "status": {
                     "type": "string",
-                    "example": "here is the generated user id"
+                    "example": "success"
                 }
             }
         },
-        "utils.FetchedComment": {
+        "utils.ResponseDataRefreshedToken": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "message": {
-                    "type": "string"
-                },
-                "photo": {
-                    "$ref": "#/definitions/utils.Photo"
-                },
-                "photo_id": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "user": {
-                    "$ref": "#/definitions/utils.User"
+                "data": {
+                    "$ref": "#/definitions/utils.RefreshedToken"
                 },
-                "user_id": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "success"
                 }
             }
         },
-        "utils.FetchedPhoto": {
+        "utils.ResponseDataRegisteredUser": {
             "type": "object",
             "properties": {
-                "caption": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/utils.RegisteredUser"
                 },
-                "photo_url": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataReplies": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.PhotoComment"
+                    }
                 },
-                "title": {
-                    "type": "string"
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6ImNvbW1lbnQtMTIzIn0"
                 },
-                "updated_at": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataSessions": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.Session"
+                    }
                 },
-                "user": {
-                    "$ref": "#/definitions/utils.User"
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "utils.ResponseDataSocialMediaDetail": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.SocialMediaDetail"
                 },
-                "user_id": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "success"
                 }
             }
         },
-        "utils.LoggedinUser": {
+        "utils.ResponseDataUpdatedComment": {
             "type": "object",
             "properties": {
-                "token": {
+                "data": {
+                    "$ref": "#/definitions/utils.UpdatedComment"
+                },
+                "status": {
                     "type": "string",
-                    "example": "the token generated here"
+                    "example": "success"
                 }
             }
         },
-        "utils.LoginUser": {
+        "utils.ResponseDataUpdatedPhoto": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "johndoe@example.com"
+                "data": {
+                    "$ref": "#/definitions/utils.UpdatedPhoto"
                 },
-                "password": {
+                "status": {
                     "type": "string",
-                    "example": "secret"
+                    "example": "success"
                 }
             }
         },
-        "utils.Photo": {
+        "utils.ResponseDataUpdatedSocialMedia": {
             "type": "object",
             "properties": {
-                "caption": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "photo_url": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/utils.UpdatedSocialMedia"
                 },
-                "user_id": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "success"
                 }
             }
         },
-        "utils.RegisterUser": {
+        "utils.ResponseDataUpdatedUser": {
             "type": "object",
             "properties": {
-                "age": {
-                    "type": "integer",
-                    "example": 8
-                },
-                "email": {
-                    "type": "string",
-                    "example": "johndoe@example.com"
-                },
-                "password": {
-                    "type": "string",
-                    "example": "secret"
+                "data": {
+                    "$ref": "#/definitions/utils.UpdatedUser"
                 },
-                "username": {
+                "status": {
                     "type": "string",
-                    "example": "johndoe"
+                    "example": "success"
                 }
             }
         },
-        "utils.RegisteredUser": {
+        "utils.ResponseMessage": {
             "type": "object",
             "properties": {
-                "age": {
-                    "type": "integer",
-                    "example": 8
-                },
-                "email": {
-                    "type": "string",
-                    "example": "johndoe@example.com"
-                },
-                "id": {
+                "data": {
                     "type": "string",
-                    "example": "the user id generated here"
+                    "example": "the error explained here"
                 },
-                "username": {
+                "status": {
                     "type": "string",
-                    "example": "johndoe"
+                    "example": "fail"
                 }
             }
         },
-        "utils.ResponseDataAddedComment": {
+        "utils.ResponseMessageAdminDeletedUser": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/utils.AddedComment"
+                "message": {
+                    "type": "string",
+                    "example": "the account has been successfully deleted"
                 },
                 "status": {
                     "type": "string",
@@ -1158,11 +4917,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataAddedPhoto": {
+        "utils.ResponseMessageChangedRole": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/utils.AddedPhoto"
+                "message": {
+                    "type": "string",
+                    "example": "the role has been successfully changed"
                 },
                 "status": {
                     "type": "string",
@@ -1170,11 +4930,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataAddedSocialMedia": {
+        "utils.ResponseMessageDeletedAPIKey": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/utils.AddedSocialMedia"
+                "message": {
+                    "type": "string",
+                    "example": "the API key has been successfully deleted"
                 },
                 "status": {
                     "type": "string",
@@ -1182,14 +4943,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataFetchedComment": {
+        "utils.ResponseMessageDeletedAvatar": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/utils.FetchedComment"
-                    }
+                "message": {
+                    "type": "string",
+                    "example": "your avatar has been successfully deleted"
                 },
                 "status": {
                     "type": "string",
@@ -1197,14 +4956,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataFetchedPhoto": {
+        "utils.ResponseMessageDeletedComment": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/utils.FetchedPhoto"
-                    }
+                "message": {
+                    "type": "string",
+                    "example": "your comment has been successfully deleted"
                 },
                 "status": {
                     "type": "string",
@@ -1212,11 +4969,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataFetchedSocialMedia": {
+        "utils.ResponseMessageDeletedPhoto": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/utils.SocialMedias"
+                "message": {
+                    "type": "string",
+                    "example": "your photo has been successfully deleted"
                 },
                 "status": {
                     "type": "string",
@@ -1224,11 +4982,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataLoggedinUser": {
+        "utils.ResponseMessageDeletedSocialMedia": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/utils.LoggedinUser"
+                "message": {
+                    "type": "string",
+                    "example": "your social media has been successfully deleted"
                 },
                 "status": {
                     "type": "string",
@@ -1236,11 +4995,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataRegisteredUser": {
+        "utils.ResponseMessageDeletedUser": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/utils.RegisteredUser"
+                "message": {
+                    "type": "string",
+                    "example": "your account has been successfully deleted"
                 },
                 "status": {
                     "type": "string",
@@ -1248,11 +5008,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataUpdatedComment": {
+        "utils.ResponseMessageForgotPassword": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/utils.UpdatedComment"
+                "message": {
+                    "type": "string",
+                    "example": "if the email you entered is registered, a password reset link has been sent to it"
                 },
                 "status": {
                     "type": "string",
@@ -1260,11 +5021,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataUpdatedPhoto": {
+        "utils.ResponseMessageLoggedOut": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/utils.UpdatedPhoto"
+                "message": {
+                    "type": "string",
+                    "example": "you have been successfully logged out"
                 },
                 "status": {
                     "type": "string",
@@ -1272,11 +5034,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataUpdatedSocialMedia": {
+        "utils.ResponseMessageMFADisabled": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/utils.UpdatedSocialMedia"
+                "message": {
+                    "type": "string",
+                    "example": "two-factor authentication has been disabled"
                 },
                 "status": {
                     "type": "string",
@@ -1284,11 +5047,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseDataUpdatedUser": {
+        "utils.ResponseMessageResentVerification": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/utils.UpdatedUser"
+                "message": {
+                    "type": "string",
+                    "example": "a new verification link has been sent to your email"
                 },
                 "status": {
                     "type": "string",
@@ -1296,12 +5060,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseMessageDeletedComment": {
+        "utils.ResponseMessageResetPassword": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string",
-                    "example": "your comment has been successfully deleted"
+                    "example": "your password has been successfully reset"
                 },
                 "status": {
                     "type": "string",
@@ -1309,12 +5073,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseMessageDeletedPhoto": {
+        "utils.ResponseMessageRevokedSession": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string",
-                    "example": "your photo has been successfully deleted"
+                    "example": "the session has been successfully ended"
                 },
                 "status": {
                     "type": "string",
@@ -1322,12 +5086,12 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseMessageDeletedSocialMedia": {
+        "utils.ResponseMessageVerifiedEmail": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string",
-                    "example": "your social media has been successfully deleted"
+                    "example": "your email has been successfully verified"
                 },
                 "status": {
                     "type": "string",
@@ -1335,16 +5099,36 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ResponseMessageDeletedUser": {
+        "utils.Session": {
             "type": "object",
             "properties": {
-                "message": {
+                "created_at": {
                     "type": "string",
-                    "example": "your account has been successfully deleted"
+                    "example": "the created at generated here"
                 },
-                "status": {
+                "current": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "expires_at": {
                     "type": "string",
-                    "example": "success"
+                    "example": "the expires at generated here"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated session id"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "last_seen_at": {
+                    "type": "string",
+                    "example": "the last seen at generated here"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (X11; Linux x86_64) Firefox/118.0"
                 }
             }
         },
@@ -1380,6 +5164,38 @@ const docTemplate = `{
                 }
             }
         },
+        "utils.SocialMediaDetail": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "here is the generated created at"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "here is the generated social media id"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Example"
+                },
+                "social_media_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/johndoe"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "here is the generated updated at"
+                },
+                "user": {
+                    "$ref": "#/definitions/mygram-api_socialmedia_utils.Owner"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "here is the generated user id"
+                }
+            }
+        },
         "utils.SocialMedias": {
             "type": "object",
             "properties": {
@@ -1417,6 +5233,27 @@ const docTemplate = `{
                 }
             }
         },
+        "utils.UpdateProfile": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "bio": {
+                    "type": "string",
+                    "example": "Sunsets and street food."
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
+                }
+            }
+        },
         "utils.UpdateSocialMedia": {
             "type": "object",
             "properties": {
@@ -1433,10 +5270,22 @@ const docTemplate = `{
         "utils.UpdateUser": {
             "type": "object",
             "properties": {
+                "age": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "bio": {
+                    "type": "string",
+                    "example": "Sunsets and street food."
+                },
                 "email": {
                     "type": "string",
                     "example": "newjohndoe@example.com"
                 },
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
+                },
                 "username": {
                     "type": "string",
                     "example": "newjohndoe"
@@ -1521,14 +5370,26 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 8
                 },
+                "bio": {
+                    "type": "string",
+                    "example": "Sunsets and street food."
+                },
                 "email": {
                     "type": "string",
-                    "example": "newjohndoe@example.com"
+                    "example": "johndoe@example.com"
                 },
                 "id": {
                     "type": "string",
                     "example": "here is the generated user id"
                 },
+                "pending_email": {
+                    "type": "string",
+                    "example": "newjohndoe@example.com"
+                },
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
+                },
                 "updated_at": {
                     "type": "string",
                     "example": "the updated at generated here"
@@ -1543,21 +5404,32 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "email": {
-                    "type": "string",
-                    "example": "johndoe@example.com"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "here is the generated user id"
+                    "type": "string"
                 },
                 "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.VerifyEmail": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
                     "type": "string",
-                    "example": "johndoe"
+                    "example": "the token from the verification email"
                 }
             }
         }
     },
     "securityDefinitions": {
+        "ApiKey": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
         "Bearer": {
             "type": "apiKey",
             "name": "Authorization",
@@ -1573,7 +5445,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/",
 	Schemes:          []string{},
 	Title:            "MyGram API",
-	Description:      "MyGram is a free photo sharing app written in Go. People can share, view, and comment photos by everyone. Anyone can create an account by registering an email address and creating a username.",
+	Description:      "MyGram is a free photo sharing app written in Go.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 }
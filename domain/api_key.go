@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrAPIKeyInvalid       = errors.New("the API key is invalid or has expired")
+	ErrAPIKeyScopeInvalid  = errors.New("the scopes must be one or more of photos:read, photos:write, comments:read, comments:write, socialmedias:read and socialmedias:write")
+	ErrAPIKeyExpiryInvalid = errors.New("the expiry of an API key must lie in the future")
+)
+
+type Scope string
+
+const (
+	ScopePhotosRead        Scope = "photos:read"
+	ScopePhotosWrite       Scope = "photos:write"
+	ScopeCommentsRead      Scope = "comments:read"
+	ScopeCommentsWrite     Scope = "comments:write"
+	ScopeSocialMediasRead  Scope = "socialmedias:read"
+	ScopeSocialMediasWrite Scope = "socialmedias:write"
+)
+
+func (scope Scope) IsValid() bool {
+	switch scope {
+	case ScopePhotosRead, ScopePhotosWrite, ScopeCommentsRead, ScopeCommentsWrite, ScopeSocialMediasRead, ScopeSocialMediasWrite:
+		return true
+	}
+
+	return false
+}
+
+// APIKey lets scripts act for a user within Scopes without a password. Only the
+// hash of the key is stored; Prefix is the start of the key and is kept so the
+// user can tell their keys apart.
+type APIKey struct {
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID     string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	Name       string     `gorm:"type:VARCHAR(50);not null" json:"name"`
+	Prefix     string     `gorm:"type:VARCHAR(20);not null" json:"prefix"`
+	KeyHash    string     `gorm:"type:VARCHAR(64);uniqueIndex;not null" json:"-"`
+	Scopes     []Scope    `gorm:"serializer:json;type:TEXT;not null" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User       *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type APIKeyUseCase interface {
+	Create(context.Context, *APIKey) (string, error)
+	Fetch(context.Context, *[]APIKey, string) error
+	Delete(context.Context, string, string) error
+	Authenticate(context.Context, string) (Principal, error)
+}
+
+type APIKeyRepository interface {
+	Store(context.Context, *APIKey) error
+	Fetch(context.Context, *[]APIKey, string) error
+	GetByHash(context.Context, *APIKey, string) error
+	Touch(context.Context, string, time.Time) error
+	Delete(context.Context, string, string) error
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"context"
+	"io"
+)
+
+// AvatarSizes are the edges, in pixels, of the square renditions made of every
+// avatar. ProfileImageUrl points at the largest one.
+var AvatarSizes = []int{64, 128, 256, 512}
+
+type AvatarSize struct {
+	Size int
+	URL  string
+}
+
+type AvatarUseCase interface {
+	Set(context.Context, string, io.Reader) ([]AvatarSize, error)
+	Delete(context.Context, string) error
+	RemoveFiles(context.Context, string)
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrImageUnsupported = errors.New("the file you uploaded is not a JPEG, PNG, GIF or WebP image")
+	ErrImageTooLarge    = errors.New("the image you uploaded is too large")
+)
+
+// BlobStore keeps uploaded files under keys like "avatars/user-123/abc.jpg" and
+// knows the URL each one is served from.
+type BlobStore interface {
+	Put(ctx context.Context, key string, contentType string, body io.Reader) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
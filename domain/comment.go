@@ -2,21 +2,45 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"mygram-api/pagination"
 	"time"
 
 	"github.com/asaskevich/govalidator"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrCommentParentInvalid = errors.New("the comment you reply to belongs to another photo")
+	ErrCommentParentDeleted = errors.New("the comment you reply to has been deleted")
+	ErrCommentTooDeep       = fmt.Errorf("replies can't be nested more than %d levels deep", MaxCommentDepth)
+	ErrCommentDeleted       = errors.New("the comment has been deleted")
+)
+
+// MaxCommentDepth is how deep replies nest. Top-level comments have depth 0.
+const MaxCommentDepth = 3
+
+// CommentTombstone replaces the message of a deleted comment that is kept for
+// the sake of its replies. Tombstones left behind by a deleted account have no
+// UserID anymore.
+const CommentTombstone = "[deleted]"
+
 type Comment struct {
-	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
-	UserID    string     `gorm:"type:VARCHAR(50);not null" json:"user_id"`
-	PhotoID   string     `gorm:"type:VARCHAR(50);not null" form:"photo_id" json:"photo_id"`
-	Message   string     `gorm:"not null" valid:"required" form:"message" json:"message" example:"A comment"`
-	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
-	UpdatedAt *time.Time `gorm:"not null;autoCreateTime" json:"updated_at,omitempty"`
-	User      *User      `gorm:"foreignKey:UserID;constraint:opUpdate:CASCADE,onDelete:CASCADE" json:"user"`
-	Photo     *Photo     `gorm:"foreignKey:PhotoID;constraint:opUpdate:CASCADE,onDelete:CASCADE" json:"photo"`
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID     string     `gorm:"type:VARCHAR(50)" json:"user_id"`
+	PhotoID    string     `gorm:"type:VARCHAR(50);not null" form:"photo_id" json:"photo_id"`
+	ParentID   *string    `gorm:"type:VARCHAR(50);index" form:"parent_id" json:"parent_id,omitempty"`
+	Message    string     `gorm:"not null" valid:"required" form:"message" json:"message" example:"A comment"`
+	Depth      int        `gorm:"not null;default:0" json:"-"`
+	Deleted    bool       `gorm:"not null;default:false" json:"-"`
+	ReplyCount int64      `gorm:"->;-:migration" json:"-"`
+	LikeCount  int64      `gorm:"not null;default:0" json:"-"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	UpdatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"updated_at,omitempty"`
+	User       *User      `gorm:"foreignKey:UserID;constraint:opUpdate:CASCADE,onDelete:SET NULL" json:"user"`
+	Photo      *Photo     `gorm:"foreignKey:PhotoID;constraint:opUpdate:CASCADE,onDelete:CASCADE" json:"photo"`
+	Parent     *Comment   `gorm:"foreignKey:ParentID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
 }
 
 func (c *Comment) BeforeCreate(db *gorm.DB) (err error) {
@@ -34,18 +58,35 @@ func (c *Comment) BeforeUpdate(db *gorm.DB) (err error) {
 	return
 }
 
+func (c Comment) OwnerID() string {
+	return c.UserID
+}
+
+// WithReplyCount selects comments along with how many direct replies each has,
+// which fills ReplyCount.
+func WithReplyCount(db *gorm.DB) *gorm.DB {
+	return db.Select("comments.*, (SELECT COUNT(*) FROM comments AS replies WHERE replies.parent_id = comments.id) AS reply_count")
+}
+
 type CommentUseCase interface {
-	Fetch(context.Context, *[]Comment, string) error
+	Fetch(context.Context, *[]Comment, string, pagination.Query) (string, error)
+	FetchByPhoto(context.Context, *[]Comment, string, pagination.Query) (string, int64, error)
+	FetchReplies(context.Context, *[]Comment, string, pagination.Query) (string, error)
 	Store(context.Context, *Comment) error
 	GetByID(context.Context, *Comment, string) error
-	Update(context.Context, Comment, string) (Photo, error)
+	GetDetailByID(context.Context, *Comment, string) error
+	Update(context.Context, Comment, Comment) (Photo, error)
 	Delete(context.Context, string) error
 }
 
 type CommentRepository interface {
-	Fetch(context.Context, *[]Comment, string) error
+	Fetch(context.Context, *[]Comment, string, pagination.Query) (string, error)
+	FetchByPhoto(context.Context, *[]Comment, string, pagination.Query) (string, error)
+	FetchReplies(context.Context, *[]Comment, string, pagination.Query) (string, error)
+	CountByPhoto(context.Context, string) (int64, error)
 	Store(context.Context, *Comment) error
 	GetByID(context.Context, *Comment, string) error
-	Update(context.Context, Comment, string) (Photo, error)
+	GetDetailByID(context.Context, *Comment, string) error
+	Update(context.Context, Comment, Comment) (Photo, error)
 	Delete(context.Context, string) error
 }
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"mygram-api/pagination"
+	"time"
+)
+
+// LikeTarget is the kind of thing a user can like.
+type LikeTarget string
+
+const (
+	LikeTargetPhoto   LikeTarget = "photo"
+	LikeTargetComment LikeTarget = "comment"
+)
+
+// PhotoLike records that a user likes a photo. A user likes a photo at most
+// once, and the photo keeps count in its LikeCount.
+type PhotoLike struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	PhotoID   string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_photo_likes_photo_user,priority:1" json:"photo_id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_photo_likes_photo_user,priority:2;index" json:"user_id"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	Photo     *Photo     `gorm:"foreignKey:PhotoID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// CommentLike records that a user likes a comment, like PhotoLike does for
+// photos.
+type CommentLike struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	CommentID string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_comment_likes_comment_user,priority:1" json:"comment_id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_comment_likes_comment_user,priority:2;index" json:"user_id"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	Comment   *Comment   `gorm:"foreignKey:CommentID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// LikeUseCase likes and unlikes photos and comments. Like and Unlike can be
+// repeated safely and return how many likes the target has afterwards.
+type LikeUseCase interface {
+	Like(context.Context, LikeTarget, string, string) (int64, error)
+	Unlike(context.Context, LikeTarget, string, string) (int64, error)
+	FetchByPhoto(context.Context, *[]PhotoLike, string, pagination.Query) (string, int64, error)
+	LikedBy(context.Context, LikeTarget, string, []string) (map[string]bool, error)
+}
+
+type LikeRepository interface {
+	Like(context.Context, LikeTarget, string, string) (int64, error)
+	Unlike(context.Context, LikeTarget, string, string) (int64, error)
+	FetchByPhoto(context.Context, *[]PhotoLike, string, pagination.Query) (string, error)
+	Count(context.Context, LikeTarget, string) (int64, error)
+	LikedBy(context.Context, LikeTarget, string, []string) (map[string]bool, error)
+}
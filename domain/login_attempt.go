@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("the email or password you entered is wrong")
+	ErrLoginThrottled     = errors.New("too many failed login attempts, please try again later")
+)
+
+// LoginThrottledError is returned while failed attempts hold back logins for an
+// account or an IP address. It matches ErrLoginThrottled with errors.Is.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (err *LoginThrottledError) Error() string {
+	return ErrLoginThrottled.Error()
+}
+
+func (err *LoginThrottledError) Is(target error) bool {
+	return target == ErrLoginThrottled
+}
+
+// LoginAttempt counts the consecutive failed logins for a key, which is either
+// an account or an IP address.
+type LoginAttempt struct {
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// LockoutEvent records that an account got locked, so its owner can be told and
+// support can see it happened.
+type LockoutEvent struct {
+	ID          string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID      string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	IPAddress   string     `gorm:"type:VARCHAR(45)" json:"ip_address"`
+	Failures    int        `gorm:"not null" json:"failures"`
+	LockedUntil *time.Time `gorm:"not null" json:"locked_until"`
+	CreatedAt   *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User        *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type LoginAttemptUseCase interface {
+	Check(context.Context, string, string) error
+	Fail(context.Context, string, string) error
+	Succeed(context.Context, string, string) error
+}
+
+// LoginAttemptStore keeps the failure counters. Fail must increment atomically,
+// and a key that has not failed for the given ttl starts over from zero.
+type LoginAttemptStore interface {
+	Get(context.Context, string) (LoginAttempt, error)
+	Fail(context.Context, string, time.Time, time.Duration) (LoginAttempt, error)
+	Reset(context.Context, string) error
+}
+
+type LockoutEventRepository interface {
+	Store(context.Context, *LockoutEvent) error
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrMFANotSetUp       = errors.New("two-factor authentication has not been set up yet")
+	ErrMFACodeInvalid    = errors.New("the code you entered is invalid")
+	ErrMFAPendingInvalid = errors.New("the login you are completing is invalid or has expired")
+)
+
+// RecoveryCode lets a user sign in once without their authenticator. Only the
+// hash of the code is stored.
+type RecoveryCode struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:VARCHAR(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type MFASetup struct {
+	Secret string
+	URI    string
+}
+
+type MFAUseCase interface {
+	Setup(context.Context, string) (MFASetup, error)
+	Confirm(context.Context, string, string) ([]string, error)
+	Disable(context.Context, string, string, string) error
+	Challenge(context.Context, User) (string, error)
+	Pending(context.Context, string) (User, error)
+	Verify(context.Context, string, string) (User, error)
+}
+
+type MFARepository interface {
+	SetTOTPSecret(context.Context, string, string) error
+	EnableTOTP(context.Context, string, int64, []RecoveryCode) error
+	DisableTOTP(context.Context, string) error
+	UseTOTPStep(context.Context, string, int64) error
+	UseRecoveryCode(context.Context, string, string) error
+}
@@ -0,0 +1,100 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APIKeyUseCase is an autogenerated mock type for the APIKeyUseCase type
+type APIKeyUseCase struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: _a0, _a1
+func (_m *APIKeyUseCase) Authenticate(_a0 context.Context, _a1 string) (domain.Principal, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.Principal
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Principal); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.Principal)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: _a0, _a1
+func (_m *APIKeyUseCase) Create(_a0 context.Context, _a1 *domain.APIKey) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.APIKey) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.APIKey) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: _a0, _a1, _a2
+func (_m *APIKeyUseCase) Delete(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fetch provides a mock function with given fields: _a0, _a1, _a2
+func (_m *APIKeyUseCase) Fetch(_a0 context.Context, _a1 *[]domain.APIKey, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.APIKey, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAPIKeyUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAPIKeyUseCase creates a new instance of APIKeyUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAPIKeyUseCase(t mockConstructorTestingTNewAPIKeyUseCase) *APIKeyUseCase {
+	mock := &APIKeyUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
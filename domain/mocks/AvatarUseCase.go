@@ -0,0 +1,73 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+	domain "mygram-api/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AvatarUseCase is an autogenerated mock type for the AvatarUseCase type
+type AvatarUseCase struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: _a0, _a1
+func (_m *AvatarUseCase) Delete(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveFiles provides a mock function with given fields: _a0, _a1
+func (_m *AvatarUseCase) RemoveFiles(_a0 context.Context, _a1 string) {
+	_m.Called(_a0, _a1)
+}
+
+// Set provides a mock function with given fields: _a0, _a1, _a2
+func (_m *AvatarUseCase) Set(_a0 context.Context, _a1 string, _a2 io.Reader) ([]domain.AvatarSize, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 []domain.AvatarSize
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) []domain.AvatarSize); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AvatarSize)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAvatarUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAvatarUseCase creates a new instance of AvatarUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAvatarUseCase(t mockConstructorTestingTNewAvatarUseCase) *AvatarUseCase {
+	mock := &AvatarUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 	domain "mygram-api/domain"
+	pagination "mygram-api/pagination"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -14,6 +15,27 @@ type CommentRepository struct {
 	mock.Mock
 }
 
+// CountByPhoto provides a mock function with given fields: _a0, _a1
+func (_m *CommentRepository) CountByPhoto(_a0 context.Context, _a1 string) (int64, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: _a0, _a1
 func (_m *CommentRepository) Delete(_a0 context.Context, _a1 string) error {
 	ret := _m.Called(_a0, _a1)
@@ -28,12 +50,75 @@ func (_m *CommentRepository) Delete(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: _a0, _a1, _a2
-func (_m *CommentRepository) Fetch(_a0 context.Context, _a1 *[]domain.Comment, _a2 string) error {
+// Fetch provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *CommentRepository) Fetch(_a0 context.Context, _a1 *[]domain.Comment, _a2 string, _a3 pagination.Query) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string, pagination.Query) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *[]domain.Comment, string, pagination.Query) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByPhoto provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *CommentRepository) FetchByPhoto(_a0 context.Context, _a1 *[]domain.Comment, _a2 string, _a3 pagination.Query) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string, pagination.Query) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *[]domain.Comment, string, pagination.Query) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchReplies provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *CommentRepository) FetchReplies(_a0 context.Context, _a1 *[]domain.Comment, _a2 string, _a3 pagination.Query) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string, pagination.Query) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *[]domain.Comment, string, pagination.Query) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *CommentRepository) GetByID(_a0 context.Context, _a1 *domain.Comment, _a2 string) error {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Comment, string) error); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
@@ -42,8 +127,8 @@ func (_m *CommentRepository) Fetch(_a0 context.Context, _a1 *[]domain.Comment, _
 	return r0
 }
 
-// GetByID provides a mock function with given fields: _a0, _a1, _a2
-func (_m *CommentRepository) GetByID(_a0 context.Context, _a1 *domain.Comment, _a2 string) error {
+// GetDetailByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *CommentRepository) GetDetailByID(_a0 context.Context, _a1 *domain.Comment, _a2 string) error {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
@@ -71,18 +156,18 @@ func (_m *CommentRepository) Store(_a0 context.Context, _a1 *domain.Comment) err
 }
 
 // Update provides a mock function with given fields: _a0, _a1, _a2
-func (_m *CommentRepository) Update(_a0 context.Context, _a1 domain.Comment, _a2 string) (domain.Photo, error) {
+func (_m *CommentRepository) Update(_a0 context.Context, _a1 domain.Comment, _a2 domain.Comment) (domain.Photo, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 domain.Photo
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Comment, string) domain.Photo); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Comment, domain.Comment) domain.Photo); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Get(0).(domain.Photo)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, domain.Comment, string) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Comment, domain.Comment) error); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
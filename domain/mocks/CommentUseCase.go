@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 	domain "mygram-api/domain"
+	pagination "mygram-api/pagination"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -28,12 +29,82 @@ func (_m *CommentUseCase) Delete(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: _a0, _a1, _a2
-func (_m *CommentUseCase) Fetch(_a0 context.Context, _a1 *[]domain.Comment, _a2 string) error {
+// Fetch provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *CommentUseCase) Fetch(_a0 context.Context, _a1 *[]domain.Comment, _a2 string, _a3 pagination.Query) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string, pagination.Query) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *[]domain.Comment, string, pagination.Query) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByPhoto provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *CommentUseCase) FetchByPhoto(_a0 context.Context, _a1 *[]domain.Comment, _a2 string, _a3 pagination.Query) (string, int64, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string, pagination.Query) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, *[]domain.Comment, string, pagination.Query) int64); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *[]domain.Comment, string, pagination.Query) error); ok {
+		r2 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchReplies provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *CommentUseCase) FetchReplies(_a0 context.Context, _a1 *[]domain.Comment, _a2 string, _a3 pagination.Query) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string, pagination.Query) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *[]domain.Comment, string, pagination.Query) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *CommentUseCase) GetByID(_a0 context.Context, _a1 *domain.Comment, _a2 string) error {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Comment, string) error); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
@@ -42,8 +113,8 @@ func (_m *CommentUseCase) Fetch(_a0 context.Context, _a1 *[]domain.Comment, _a2
 	return r0
 }
 
-// GetByID provides a mock function with given fields: _a0, _a1, _a2
-func (_m *CommentUseCase) GetByID(_a0 context.Context, _a1 *domain.Comment, _a2 string) error {
+// GetDetailByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *CommentUseCase) GetDetailByID(_a0 context.Context, _a1 *domain.Comment, _a2 string) error {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
@@ -71,18 +142,18 @@ func (_m *CommentUseCase) Store(_a0 context.Context, _a1 *domain.Comment) error
 }
 
 // Update provides a mock function with given fields: _a0, _a1, _a2
-func (_m *CommentUseCase) Update(_a0 context.Context, _a1 domain.Comment, _a2 string) (domain.Photo, error) {
+func (_m *CommentUseCase) Update(_a0 context.Context, _a1 domain.Comment, _a2 domain.Comment) (domain.Photo, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 domain.Photo
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Comment, string) domain.Photo); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Comment, domain.Comment) domain.Photo); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Get(0).(domain.Photo)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, domain.Comment, string) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Comment, domain.Comment) error); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
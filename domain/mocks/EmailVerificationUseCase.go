@@ -0,0 +1,81 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailVerificationUseCase is an autogenerated mock type for the EmailVerificationUseCase type
+type EmailVerificationUseCase struct {
+	mock.Mock
+}
+
+// PrepareEmailChange provides a mock function with given fields: _a0, _a1, _a2
+func (_m *EmailVerificationUseCase) PrepareEmailChange(_a0 context.Context, _a1 string, _a2 string) (*string, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *string); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Send provides a mock function with given fields: _a0, _a1
+func (_m *EmailVerificationUseCase) Send(_a0 context.Context, _a1 domain.User) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Verify provides a mock function with given fields: _a0, _a1
+func (_m *EmailVerificationUseCase) Verify(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewEmailVerificationUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEmailVerificationUseCase creates a new instance of EmailVerificationUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEmailVerificationUseCase(t mockConstructorTestingTNewEmailVerificationUseCase) *EmailVerificationUseCase {
+	mock := &EmailVerificationUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExternalIdentityRepository is an autogenerated mock type for the ExternalIdentityRepository type
+type ExternalIdentityRepository struct {
+	mock.Mock
+}
+
+// GetBySubject provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *ExternalIdentityRepository) GetBySubject(_a0 context.Context, _a1 *domain.ExternalIdentity, _a2 string, _a3 string) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ExternalIdentity, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store provides a mock function with given fields: _a0, _a1
+func (_m *ExternalIdentityRepository) Store(_a0 context.Context, _a1 *domain.ExternalIdentity) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ExternalIdentity) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewExternalIdentityRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewExternalIdentityRepository creates a new instance of ExternalIdentityRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewExternalIdentityRepository(t mockConstructorTestingTNewExternalIdentityRepository) *ExternalIdentityRepository {
+	mock := &ExternalIdentityRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
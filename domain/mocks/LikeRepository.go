@@ -0,0 +1,138 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+	pagination "mygram-api/pagination"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LikeRepository is an autogenerated mock type for the LikeRepository type
+type LikeRepository struct {
+	mock.Mock
+}
+
+// Count provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LikeRepository) Count(_a0 context.Context, _a1 domain.LikeTarget, _a2 string) (int64, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LikeTarget, string) int64); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.LikeTarget, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByPhoto provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *LikeRepository) FetchByPhoto(_a0 context.Context, _a1 *[]domain.PhotoLike, _a2 string, _a3 pagination.Query) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.PhotoLike, string, pagination.Query) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *[]domain.PhotoLike, string, pagination.Query) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Like provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *LikeRepository) Like(_a0 context.Context, _a1 domain.LikeTarget, _a2 string, _a3 string) (int64, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LikeTarget, string, string) int64); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.LikeTarget, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LikedBy provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *LikeRepository) LikedBy(_a0 context.Context, _a1 domain.LikeTarget, _a2 string, _a3 []string) (map[string]bool, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 map[string]bool
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LikeTarget, string, []string) map[string]bool); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]bool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.LikeTarget, string, []string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unlike provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *LikeRepository) Unlike(_a0 context.Context, _a1 domain.LikeTarget, _a2 string, _a3 string) (int64, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LikeTarget, string, string) int64); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.LikeTarget, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewLikeRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLikeRepository creates a new instance of LikeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLikeRepository(t mockConstructorTestingTNewLikeRepository) *LikeRepository {
+	mock := &LikeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,124 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+	pagination "mygram-api/pagination"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LikeUseCase is an autogenerated mock type for the LikeUseCase type
+type LikeUseCase struct {
+	mock.Mock
+}
+
+// FetchByPhoto provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *LikeUseCase) FetchByPhoto(_a0 context.Context, _a1 *[]domain.PhotoLike, _a2 string, _a3 pagination.Query) (string, int64, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.PhotoLike, string, pagination.Query) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, *[]domain.PhotoLike, string, pagination.Query) int64); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *[]domain.PhotoLike, string, pagination.Query) error); ok {
+		r2 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Like provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *LikeUseCase) Like(_a0 context.Context, _a1 domain.LikeTarget, _a2 string, _a3 string) (int64, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LikeTarget, string, string) int64); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.LikeTarget, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LikedBy provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *LikeUseCase) LikedBy(_a0 context.Context, _a1 domain.LikeTarget, _a2 string, _a3 []string) (map[string]bool, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 map[string]bool
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LikeTarget, string, []string) map[string]bool); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]bool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.LikeTarget, string, []string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unlike provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *LikeUseCase) Unlike(_a0 context.Context, _a1 domain.LikeTarget, _a2 string, _a3 string) (int64, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LikeTarget, string, string) int64); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.LikeTarget, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewLikeUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLikeUseCase creates a new instance of LikeUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLikeUseCase(t mockConstructorTestingTNewLikeUseCase) *LikeUseCase {
+	mock := &LikeUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
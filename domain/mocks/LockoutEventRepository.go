@@ -0,0 +1,44 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LockoutEventRepository is an autogenerated mock type for the LockoutEventRepository type
+type LockoutEventRepository struct {
+	mock.Mock
+}
+
+// Store provides a mock function with given fields: _a0, _a1
+func (_m *LockoutEventRepository) Store(_a0 context.Context, _a1 *domain.LockoutEvent) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.LockoutEvent) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewLockoutEventRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLockoutEventRepository creates a new instance of LockoutEventRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLockoutEventRepository(t mockConstructorTestingTNewLockoutEventRepository) *LockoutEventRepository {
+	mock := &LockoutEventRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
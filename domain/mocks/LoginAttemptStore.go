@@ -0,0 +1,87 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LoginAttemptStore is an autogenerated mock type for the LoginAttemptStore type
+type LoginAttemptStore struct {
+	mock.Mock
+}
+
+// Fail provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *LoginAttemptStore) Fail(_a0 context.Context, _a1 string, _a2 time.Time, _a3 time.Duration) (domain.LoginAttempt, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 domain.LoginAttempt
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) domain.LoginAttempt); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(domain.LoginAttempt)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Duration) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: _a0, _a1
+func (_m *LoginAttemptStore) Get(_a0 context.Context, _a1 string) (domain.LoginAttempt, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.LoginAttempt
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.LoginAttempt); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.LoginAttempt)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reset provides a mock function with given fields: _a0, _a1
+func (_m *LoginAttemptStore) Reset(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewLoginAttemptStore interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLoginAttemptStore creates a new instance of LoginAttemptStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLoginAttemptStore(t mockConstructorTestingTNewLoginAttemptStore) *LoginAttemptStore {
+	mock := &LoginAttemptStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,71 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LoginAttemptUseCase is an autogenerated mock type for the LoginAttemptUseCase type
+type LoginAttemptUseCase struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LoginAttemptUseCase) Check(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fail provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LoginAttemptUseCase) Fail(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Succeed provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LoginAttemptUseCase) Succeed(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewLoginAttemptUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLoginAttemptUseCase creates a new instance of LoginAttemptUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLoginAttemptUseCase(t mockConstructorTestingTNewLoginAttemptUseCase) *LoginAttemptUseCase {
+	mock := &LoginAttemptUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,44 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LoginNotifier is an autogenerated mock type for the LoginNotifier type
+type LoginNotifier struct {
+	mock.Mock
+}
+
+// NewDevice provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LoginNotifier) NewDevice(_a0 context.Context, _a1 domain.User, _a2 domain.Session) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User, domain.Session) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewLoginNotifier interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLoginNotifier creates a new instance of LoginNotifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLoginNotifier(t mockConstructorTestingTNewLoginNotifier) *LoginNotifier {
+	mock := &LoginNotifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,72 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OIDCUseCase is an autogenerated mock type for the OIDCUseCase type
+type OIDCUseCase struct {
+	mock.Mock
+}
+
+// Callback provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *OIDCUseCase) Callback(_a0 context.Context, _a1 string, _a2 string, _a3 string, _a4 string) (domain.User, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) domain.User); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Start provides a mock function with given fields: _a0, _a1
+func (_m *OIDCUseCase) Start(_a0 context.Context, _a1 string) (domain.OIDCLogin, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.OIDCLogin
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.OIDCLogin); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.OIDCLogin)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewOIDCUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOIDCUseCase creates a new instance of OIDCUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOIDCUseCase(t mockConstructorTestingTNewOIDCUseCase) *OIDCUseCase {
+	mock := &OIDCUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
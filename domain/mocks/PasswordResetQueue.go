@@ -0,0 +1,30 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// PasswordResetQueue is an autogenerated mock type for the PasswordResetQueue type
+type PasswordResetQueue struct {
+	mock.Mock
+}
+
+// Enqueue provides a mock function with given fields: _a0
+func (_m *PasswordResetQueue) Enqueue(_a0 string) {
+	_m.Called(_a0)
+}
+
+type mockConstructorTestingTNewPasswordResetQueue interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordResetQueue creates a new instance of PasswordResetQueue. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordResetQueue(t mockConstructorTestingTNewPasswordResetQueue) *PasswordResetQueue {
+	mock := &PasswordResetQueue{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
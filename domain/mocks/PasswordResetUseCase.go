@@ -0,0 +1,57 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordResetUseCase is an autogenerated mock type for the PasswordResetUseCase type
+type PasswordResetUseCase struct {
+	mock.Mock
+}
+
+// Forgot provides a mock function with given fields: _a0, _a1
+func (_m *PasswordResetUseCase) Forgot(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reset provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PasswordResetUseCase) Reset(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPasswordResetUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordResetUseCase creates a new instance of PasswordResetUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordResetUseCase(t mockConstructorTestingTNewPasswordResetUseCase) *PasswordResetUseCase {
+	mock := &PasswordResetUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
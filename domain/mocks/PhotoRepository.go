@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 	domain "mygram-api/domain"
+	pagination "mygram-api/pagination"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -14,6 +15,27 @@ type PhotoRepository struct {
 	mock.Mock
 }
 
+// CountByStorageKey provides a mock function with given fields: _a0, _a1
+func (_m *PhotoRepository) CountByStorageKey(_a0 context.Context, _a1 string) (int64, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: _a0, _a1
 func (_m *PhotoRepository) Delete(_a0 context.Context, _a1 string) error {
 	ret := _m.Called(_a0, _a1)
@@ -28,18 +50,25 @@ func (_m *PhotoRepository) Delete(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: _a0, _a1
-func (_m *PhotoRepository) Fetch(_a0 context.Context, _a1 *[]domain.Photo) error {
-	ret := _m.Called(_a0, _a1)
+// Fetch provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PhotoRepository) Fetch(_a0 context.Context, _a1 *[]domain.Photo, _a2 pagination.Query) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Photo) error); ok {
-		r0 = rf(_a0, _a1)
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Photo, pagination.Query) string); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(string)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *[]domain.Photo, pagination.Query) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetByID provides a mock function with given fields: _a0, _a1, _a2
@@ -56,6 +85,20 @@ func (_m *PhotoRepository) GetByID(_a0 context.Context, _a1 *domain.Photo, _a2 s
 	return r0
 }
 
+// GetDetailByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PhotoRepository) GetDetailByID(_a0 context.Context, _a1 *domain.PhotoDetail, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.PhotoDetail, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Store provides a mock function with given fields: _a0, _a1
 func (_m *PhotoRepository) Store(_a0 context.Context, _a1 *domain.Photo) error {
 	ret := _m.Called(_a0, _a1)
@@ -71,18 +114,18 @@ func (_m *PhotoRepository) Store(_a0 context.Context, _a1 *domain.Photo) error {
 }
 
 // Update provides a mock function with given fields: _a0, _a1, _a2
-func (_m *PhotoRepository) Update(_a0 context.Context, _a1 domain.Photo, _a2 string) (domain.Photo, error) {
+func (_m *PhotoRepository) Update(_a0 context.Context, _a1 domain.Photo, _a2 domain.Photo) (domain.Photo, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 domain.Photo
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Photo, string) domain.Photo); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Photo, domain.Photo) domain.Photo); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Get(0).(domain.Photo)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, domain.Photo, string) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Photo, domain.Photo) error); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
@@ -91,6 +134,20 @@ func (_m *PhotoRepository) Update(_a0 context.Context, _a1 domain.Photo, _a2 str
 	return r0, r1
 }
 
+// UpdateLocationPublic provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PhotoRepository) UpdateLocationPublic(_a0 context.Context, _a1 string, _a2 bool) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewPhotoRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -4,7 +4,9 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	domain "mygram-api/domain"
+	pagination "mygram-api/pagination"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -28,18 +30,25 @@ func (_m *PhotoUseCase) Delete(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: _a0, _a1
-func (_m *PhotoUseCase) Fetch(_a0 context.Context, _a1 *[]domain.Photo) error {
-	ret := _m.Called(_a0, _a1)
+// Fetch provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PhotoUseCase) Fetch(_a0 context.Context, _a1 *[]domain.Photo, _a2 pagination.Query) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Photo) error); ok {
-		r0 = rf(_a0, _a1)
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Photo, pagination.Query) string); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(string)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *[]domain.Photo, pagination.Query) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetByID provides a mock function with given fields: _a0, _a1, _a2
@@ -56,6 +65,34 @@ func (_m *PhotoUseCase) GetByID(_a0 context.Context, _a1 *domain.Photo, _a2 stri
 	return r0
 }
 
+// GetDetailByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PhotoUseCase) GetDetailByID(_a0 context.Context, _a1 *domain.PhotoDetail, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.PhotoDetail, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishLocation provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PhotoUseCase) PublishLocation(_a0 context.Context, _a1 string, _a2 bool) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Store provides a mock function with given fields: _a0, _a1
 func (_m *PhotoUseCase) Store(_a0 context.Context, _a1 *domain.Photo) error {
 	ret := _m.Called(_a0, _a1)
@@ -71,18 +108,18 @@ func (_m *PhotoUseCase) Store(_a0 context.Context, _a1 *domain.Photo) error {
 }
 
 // Update provides a mock function with given fields: _a0, _a1, _a2
-func (_m *PhotoUseCase) Update(_a0 context.Context, _a1 domain.Photo, _a2 string) (domain.Photo, error) {
+func (_m *PhotoUseCase) Update(_a0 context.Context, _a1 domain.Photo, _a2 domain.Photo) (domain.Photo, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 domain.Photo
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Photo, string) domain.Photo); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Photo, domain.Photo) domain.Photo); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Get(0).(domain.Photo)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, domain.Photo, string) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Photo, domain.Photo) error); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
@@ -91,6 +128,20 @@ func (_m *PhotoUseCase) Update(_a0 context.Context, _a1 domain.Photo, _a2 string
 	return r0, r1
 }
 
+// Upload provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PhotoUseCase) Upload(_a0 context.Context, _a1 *domain.Photo, _a2 io.Reader) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Photo, io.Reader) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewPhotoUseCase interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,34 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "mygram-api/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhotoVariantGenerator is an autogenerated mock type for the PhotoVariantGenerator type
+type PhotoVariantGenerator struct {
+	mock.Mock
+}
+
+// Enqueue provides a mock function with given fields: _a0, _a1
+func (_m *PhotoVariantGenerator) Enqueue(_a0 domain.Photo, _a1 []byte) {
+	_m.Called(_a0, _a1)
+}
+
+type mockConstructorTestingTNewPhotoVariantGenerator interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPhotoVariantGenerator creates a new instance of PhotoVariantGenerator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPhotoVariantGenerator(t mockConstructorTestingTNewPhotoVariantGenerator) *PhotoVariantGenerator {
+	mock := &PhotoVariantGenerator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
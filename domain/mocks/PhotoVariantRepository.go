@@ -0,0 +1,58 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhotoVariantRepository is an autogenerated mock type for the PhotoVariantRepository type
+type PhotoVariantRepository struct {
+	mock.Mock
+}
+
+// FetchByPhotoID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PhotoVariantRepository) FetchByPhotoID(_a0 context.Context, _a1 *[]domain.PhotoVariant, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.PhotoVariant, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store provides a mock function with given fields: _a0, _a1
+func (_m *PhotoVariantRepository) Store(_a0 context.Context, _a1 *domain.PhotoVariant) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.PhotoVariant) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPhotoVariantRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPhotoVariantRepository creates a new instance of PhotoVariantRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPhotoVariantRepository(t mockConstructorTestingTNewPhotoVariantRepository) *PhotoVariantRepository {
+	mock := &PhotoVariantRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
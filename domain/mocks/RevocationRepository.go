@@ -0,0 +1,94 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RevocationRepository is an autogenerated mock type for the RevocationRepository type
+type RevocationRepository struct {
+	mock.Mock
+}
+
+// IsRevoked provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *RevocationRepository) IsRevoked(_a0 context.Context, _a1 string, _a2 string, _a3 string, _a4 time.Time) (bool, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Time) bool); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, time.Time) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeSession provides a mock function with given fields: _a0, _a1
+func (_m *RevocationRepository) RevokeSession(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeToken provides a mock function with given fields: _a0, _a1
+func (_m *RevocationRepository) RevokeToken(_a0 context.Context, _a1 domain.RevokedToken) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RevokedToken) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeUser provides a mock function with given fields: _a0, _a1, _a2
+func (_m *RevocationRepository) RevokeUser(_a0 context.Context, _a1 string, _a2 time.Time) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewRevocationRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRevocationRepository creates a new instance of RevocationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRevocationRepository(t mockConstructorTestingTNewRevocationRepository) *RevocationRepository {
+	mock := &RevocationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
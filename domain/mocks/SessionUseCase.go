@@ -0,0 +1,58 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SessionUseCase is an autogenerated mock type for the SessionUseCase type
+type SessionUseCase struct {
+	mock.Mock
+}
+
+// Fetch provides a mock function with given fields: _a0, _a1, _a2
+func (_m *SessionUseCase) Fetch(_a0 context.Context, _a1 *[]domain.Session, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Session, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// History provides a mock function with given fields: _a0, _a1, _a2
+func (_m *SessionUseCase) History(_a0 context.Context, _a1 *[]domain.Session, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Session, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewSessionUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSessionUseCase creates a new instance of SessionUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSessionUseCase(t mockConstructorTestingTNewSessionUseCase) *SessionUseCase {
+	mock := &SessionUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
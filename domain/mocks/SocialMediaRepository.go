@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 	domain "mygram-api/domain"
+	pagination "mygram-api/pagination"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -28,12 +29,33 @@ func (_m *SocialMediaRepository) Delete(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: _a0, _a1, _a2
-func (_m *SocialMediaRepository) Fetch(_a0 context.Context, _a1 *[]domain.SocialMedia, _a2 string) error {
+// Fetch provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *SocialMediaRepository) Fetch(_a0 context.Context, _a1 *[]domain.SocialMedia, _a2 string, _a3 pagination.Query) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.SocialMedia, string, pagination.Query) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *[]domain.SocialMedia, string, pagination.Query) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *SocialMediaRepository) GetByID(_a0 context.Context, _a1 *domain.SocialMedia, _a2 string) error {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.SocialMedia, string) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.SocialMedia, string) error); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
@@ -42,8 +64,8 @@ func (_m *SocialMediaRepository) Fetch(_a0 context.Context, _a1 *[]domain.Social
 	return r0
 }
 
-// GetByID provides a mock function with given fields: _a0, _a1, _a2
-func (_m *SocialMediaRepository) GetByID(_a0 context.Context, _a1 *domain.SocialMedia, _a2 string) error {
+// GetDetailByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *SocialMediaRepository) GetDetailByID(_a0 context.Context, _a1 *domain.SocialMedia, _a2 string) error {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
@@ -71,18 +93,18 @@ func (_m *SocialMediaRepository) Store(_a0 context.Context, _a1 *domain.SocialMe
 }
 
 // Update provides a mock function with given fields: _a0, _a1, _a2
-func (_m *SocialMediaRepository) Update(_a0 context.Context, _a1 domain.SocialMedia, _a2 string) (domain.SocialMedia, error) {
+func (_m *SocialMediaRepository) Update(_a0 context.Context, _a1 domain.SocialMedia, _a2 domain.SocialMedia) (domain.SocialMedia, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 domain.SocialMedia
-	if rf, ok := ret.Get(0).(func(context.Context, domain.SocialMedia, string) domain.SocialMedia); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.SocialMedia, domain.SocialMedia) domain.SocialMedia); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Get(0).(domain.SocialMedia)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, domain.SocialMedia, string) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, domain.SocialMedia, domain.SocialMedia) error); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
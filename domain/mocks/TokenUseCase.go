@@ -0,0 +1,128 @@
+// Code generated by mockery v2.14.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "mygram-api/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TokenUseCase is an autogenerated mock type for the TokenUseCase type
+type TokenUseCase struct {
+	mock.Mock
+}
+
+// Issue provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TokenUseCase) Issue(_a0 context.Context, _a1 domain.User, _a2 domain.Client) (domain.Token, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 domain.Token
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User, domain.Client) domain.Token); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(domain.Token)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.User, domain.Client) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Refresh provides a mock function with given fields: _a0, _a1
+func (_m *TokenUseCase) Refresh(_a0 context.Context, _a1 string) (domain.Token, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.Token
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Token); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.Token)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: _a0, _a1
+func (_m *TokenUseCase) Revoke(_a0 context.Context, _a1 domain.RevokedToken) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RevokedToken) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAll provides a mock function with given fields: _a0, _a1
+func (_m *TokenUseCase) RevokeAll(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeRefreshToken provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TokenUseCase) RevokeRefreshToken(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeSession provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TokenUseCase) RevokeSession(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewTokenUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTokenUseCase creates a new instance of TokenUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTokenUseCase(t mockConstructorTestingTNewTokenUseCase) *TokenUseCase {
+	mock := &TokenUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -28,6 +28,76 @@ func (_m *UserRepository) Delete(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
+// Fetch provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) Fetch(_a0 context.Context, _a1 *[]domain.User) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.User) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByEmail provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) GetByEmail(_a0 context.Context, _a1 *domain.User, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) GetByID(_a0 context.Context, _a1 *domain.User, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetProfileByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) GetProfileByID(_a0 context.Context, _a1 *domain.Profile, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Profile, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetProfileByUsername provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) GetProfileByUsername(_a0 context.Context, _a1 *domain.Profile, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Profile, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Login provides a mock function with given fields: _a0, _a1
 func (_m *UserRepository) Login(_a0 context.Context, _a1 *domain.User) error {
 	ret := _m.Called(_a0, _a1)
@@ -56,20 +126,20 @@ func (_m *UserRepository) Register(_a0 context.Context, _a1 *domain.User) error
 	return r0
 }
 
-// Update provides a mock function with given fields: _a0, _a1
-func (_m *UserRepository) Update(_a0 context.Context, _a1 domain.User) (domain.User, error) {
-	ret := _m.Called(_a0, _a1)
+// Update provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) Update(_a0 context.Context, _a1 string, _a2 domain.UserUpdate) (domain.User, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 domain.User
-	if rf, ok := ret.Get(0).(func(context.Context, domain.User) domain.User); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.UserUpdate) domain.User); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Get(0).(domain.User)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, domain.User) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.UserUpdate) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -77,6 +147,62 @@ func (_m *UserRepository) Update(_a0 context.Context, _a1 domain.User) (domain.U
 	return r0, r1
 }
 
+// UpdateAvatar provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *UserRepository) UpdateAvatar(_a0 context.Context, _a1 string, _a2 string, _a3 string) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) UpdatePassword(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRole provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) UpdateRole(_a0 context.Context, _a1 string, _a2 domain.Role) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Role) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyEmail provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) VerifyEmail(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewUserRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrOIDCProviderUnknown    = errors.New("the sign-in provider you asked for isn't configured")
+	ErrOIDCLoginInvalid       = errors.New("the sign-in attempt is invalid or has expired, start it again")
+	ErrOIDCEmailUnverified    = errors.New("the provider didn't confirm that your email address is verified")
+	ErrOIDCAccountUnverified  = errors.New("an account with this email exists but its email isn't verified, verify it or sign in with your password to proceed")
+	ErrOIDCIDTokenInvalid     = errors.New("the provider returned an invalid ID token")
+	ErrOIDCCodeExchangeFailed = errors.New("the provider refused the authorization code")
+)
+
+// ExternalIdentity links an account at an OpenID Connect provider, identified
+// by the provider's subject, to a user. A user may have any number of them.
+type ExternalIdentity struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	Provider  string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_external_identities_provider_subject" json:"provider"`
+	Subject   string     `gorm:"type:VARCHAR(255);not null;uniqueIndex:idx_external_identities_provider_subject" json:"subject"`
+	Email     string     `gorm:"type:VARCHAR(50)" json:"email"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// OIDCClaims are the verified claims of an ID token.
+type OIDCClaims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+// OIDCLogin is a started login. The user is sent to URL, while Flow stays with
+// their browser and is handed back to Callback along with what the provider
+// returns. It binds the callback to the browser that started the login.
+type OIDCLogin struct {
+	URL  string
+	Flow string
+}
+
+// OIDCProvider runs the authorization code flow with PKCE against one provider.
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (OIDCClaims, error)
+}
+
+type OIDCUseCase interface {
+	Start(context.Context, string) (OIDCLogin, error)
+	Callback(context.Context, string, string, string, string) (User, error)
+}
+
+type ExternalIdentityRepository interface {
+	GetBySubject(context.Context, *ExternalIdentity, string, string) error
+	Store(context.Context, *ExternalIdentity) error
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrPasswordResetInvalid = errors.New("the password reset token you entered is invalid or has expired")
+
+// PasswordReset only ever stores the hash of the token that was mailed out, and
+// UsedAt makes every token single-use.
+type PasswordReset struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	TokenHash string     `gorm:"type:VARCHAR(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt *time.Time `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type PasswordResetUseCase interface {
+	Forgot(context.Context, string) error
+	Reset(context.Context, string, string) error
+}
+
+// PasswordResetQueue runs Forgot for the emails it is handed. Enqueue returns
+// right away, the lookup and the mail happen in the background.
+type PasswordResetQueue interface {
+	Enqueue(string)
+}
+
+type PasswordResetRepository interface {
+	Store(context.Context, *PasswordReset) error
+	Consume(context.Context, *PasswordReset, string) error
+}
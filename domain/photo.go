@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"io"
+	"mygram-api/pagination"
 	"time"
 
 	"github.com/asaskevich/govalidator"
@@ -9,15 +11,27 @@ import (
 )
 
 type Photo struct {
-	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
-	Title     string     `gorm:"type:VARCHAR(50);not null" valid:"required" form:"title" json:"title" example:"A Photo Title"`
-	Caption   string     `form:"caption" json:"caption"`
-	PhotoUrl  string     `gorm:"not null" valid:"required" form:"photo_url" json:"photo_url" example:"https://www.example.com/image.jpg"`
-	UserID    string     `gorm:"type:VARCHAR(50);not null" json:"user_id"`
-	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
-	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
-	UpdatedAt *time.Time `gorm:"not null;autoCreateTime" json:"updated_at,omitempty"`
-	Comment   *Comment   `json:"-"`
+	ID             string         `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	Title          string         `gorm:"type:VARCHAR(50);not null" valid:"required" form:"title" json:"title" example:"A Photo Title"`
+	Caption        string         `form:"caption" json:"caption"`
+	PhotoUrl       string         `gorm:"not null" valid:"required" form:"photo_url" json:"photo_url" example:"https://www.example.com/image.jpg"`
+	UserID         string         `gorm:"type:VARCHAR(50);not null" json:"user_id"`
+	StorageKey     string         `gorm:"type:VARCHAR(100);index" json:"-"`
+	Width          int            `json:"-"`
+	Height         int            `json:"-"`
+	CameraMake     string         `gorm:"type:VARCHAR(100)" json:"-"`
+	CameraModel    string         `gorm:"type:VARCHAR(100)" json:"-"`
+	LensModel      string         `gorm:"type:VARCHAR(100)" json:"-"`
+	TakenAt        *time.Time     `json:"-"`
+	Latitude       *float64       `json:"-"`
+	Longitude      *float64       `json:"-"`
+	LocationPublic bool           `gorm:"not null;default:false" json:"-"`
+	LikeCount      int64          `gorm:"not null;default:0" json:"-"`
+	User           *User          `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	CreatedAt      *time.Time     `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	UpdatedAt      *time.Time     `gorm:"not null;autoCreateTime" json:"updated_at,omitempty"`
+	Comment        *Comment       `json:"-"`
+	Variants       []PhotoVariant `json:"-"`
 }
 
 func (photo *Photo) BeforeCreate(db *gorm.DB) (err error) {
@@ -35,18 +49,38 @@ func (photo *Photo) BeforeUpdate(db *gorm.DB) (err error) {
 	return
 }
 
+func (photo Photo) OwnerID() string {
+	return photo.UserID
+}
+
+// PhotoDetail is a photo as its page shows it: with its owner, how many comments
+// it has and the first page of the top-level ones, oldest first. NextCursor continues that
+// page.
+type PhotoDetail struct {
+	Photo        Photo
+	CommentCount int64
+	Comments     []Comment
+	NextCursor   string
+}
+
 type PhotoUseCase interface {
-	Fetch(context.Context, *[]Photo) error
+	Fetch(context.Context, *[]Photo, pagination.Query) (string, error)
 	Store(context.Context, *Photo) error
+	Upload(context.Context, *Photo, io.Reader) error
+	PublishLocation(context.Context, string, bool) error
 	GetByID(context.Context, *Photo, string) error
-	Update(context.Context, Photo, string) (Photo, error)
+	GetDetailByID(context.Context, *PhotoDetail, string) error
+	Update(context.Context, Photo, Photo) (Photo, error)
 	Delete(context.Context, string) error
 }
 
 type PhotoRepository interface {
-	Fetch(context.Context, *[]Photo) error
+	Fetch(context.Context, *[]Photo, pagination.Query) (string, error)
 	Store(context.Context, *Photo) error
 	GetByID(context.Context, *Photo, string) error
-	Update(context.Context, Photo, string) (Photo, error)
+	GetDetailByID(context.Context, *PhotoDetail, string) error
+	Update(context.Context, Photo, Photo) (Photo, error)
 	Delete(context.Context, string) error
+	CountByStorageKey(context.Context, string) (int64, error)
+	UpdateLocationPublic(context.Context, string, bool) error
 }
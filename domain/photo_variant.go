@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PhotoVariant is a smaller rendition of an uploaded photo, for clients that
+// would otherwise download the full image to show it in a grid.
+type PhotoVariant struct {
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	PhotoID    string     `gorm:"type:VARCHAR(50);not null;index" json:"photo_id"`
+	Width      int        `gorm:"not null" json:"width"`
+	Height     int        `gorm:"not null" json:"height"`
+	Format     string     `gorm:"type:VARCHAR(10);not null" json:"format"`
+	Url        string     `gorm:"not null" json:"url"`
+	StorageKey string     `gorm:"type:VARCHAR(100);not null" json:"-"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	Photo      *Photo     `gorm:"foreignKey:PhotoID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// PhotoVariantGenerator renders the variants of an uploaded photo from its
+// original image. Enqueue returns right away, the work happens in the background.
+type PhotoVariantGenerator interface {
+	Enqueue(Photo, []byte)
+}
+
+type PhotoVariantRepository interface {
+	Store(context.Context, *PhotoVariant) error
+	FetchByPhotoID(context.Context, *[]PhotoVariant, string) error
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// Principal is whoever a request was authenticated as, through an access token
+// or an API key. The Authentication middleware stores it as "principal".
+type Principal struct {
+	UserID        string
+	Email         string
+	EmailVerified bool
+	Role          Role
+	// TokenID, SessionID and ExpiresAt describe the access token and are empty
+	// for API keys.
+	TokenID   string
+	SessionID string
+	ExpiresAt time.Time
+	// APIKeyID and Scopes are only set for API keys.
+	APIKeyID string
+	Scopes   []Scope
+}
+
+// HasScope reports whether the principal may act within scope. Access tokens
+// stand for the user themselves and aren't limited to any scope.
+func (principal Principal) HasScope(scope Scope) bool {
+	if principal.APIKeyID == "" {
+		return true
+	}
+
+	for _, granted := range principal.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+
+	return false
+}
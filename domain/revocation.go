@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RevokedToken blocks a single access token until it would have expired anyway.
+type RevokedToken struct {
+	JTI       string     `gorm:"primaryKey;type:VARCHAR(50)" json:"jti"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	ExpiresAt *time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+}
+
+// UserRevocation blocks every access token of a user issued before RevokedBefore.
+// It deliberately has no foreign key so the cutoff outlives a deleted account.
+type UserRevocation struct {
+	UserID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"user_id"`
+	RevokedBefore *time.Time `gorm:"not null" json:"revoked_before"`
+	UpdatedAt     *time.Time `gorm:"not null;autoUpdateTime" json:"updated_at,omitempty"`
+}
+
+type RevocationRepository interface {
+	RevokeToken(context.Context, RevokedToken) error
+	RevokeUser(context.Context, string, time.Time) error
+	RevokeSession(context.Context, string) error
+	IsRevoked(context.Context, string, string, string, time.Time) (bool, error)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Client describes where a login comes from.
+type Client struct {
+	UserAgent string
+	IP        string
+}
+
+// Session is a single login on a device. Its ID doubles as the family of the
+// refresh tokens it hands out and goes into every access token as "sid", so
+// ending the session ends both right away.
+type Session struct {
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID     string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	UserAgent  string     `gorm:"type:VARCHAR(255)" json:"user_agent"`
+	IP         string     `gorm:"type:VARCHAR(45)" json:"ip"`
+	DeviceHash string     `gorm:"type:VARCHAR(64);not null;index" json:"-"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	ExpiresAt  *time.Time `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User       *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// LoginNotifier is told about logins the user may want to know of.
+type LoginNotifier interface {
+	NewDevice(context.Context, User, Session) error
+}
+
+type SessionUseCase interface {
+	Fetch(context.Context, *[]Session, string) error
+	History(context.Context, *[]Session, string) error
+}
+
+type SessionRepository interface {
+	Store(context.Context, *Session) error
+	GetByID(context.Context, *Session, string) error
+	Fetch(context.Context, *[]Session, string) error
+	FetchHistory(context.Context, *[]Session, string, int) error
+	HasAny(context.Context, string) (bool, error)
+	HasDevice(context.Context, string, string) (bool, error)
+	Touch(context.Context, string, time.Time, time.Time) error
+	RevokeUser(context.Context, string) error
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"mygram-api/pagination"
 	"time"
 
 	"github.com/asaskevich/govalidator"
@@ -33,18 +34,24 @@ func (s *SocialMedia) BeforeUpdate(db *gorm.DB) (err error) {
 	return
 }
 
+func (s SocialMedia) OwnerID() string {
+	return s.UserID
+}
+
 type SocialMediaUseCase interface {
-	Fetch(context.Context, *[]SocialMedia, string) error
+	Fetch(context.Context, *[]SocialMedia, string, pagination.Query) (string, error)
 	Store(context.Context, *SocialMedia) error
 	GetByID(context.Context, *SocialMedia, string) error
-	Update(context.Context, SocialMedia, string) (SocialMedia, error)
+	GetDetailByID(context.Context, *SocialMedia, string) error
+	Update(context.Context, SocialMedia, SocialMedia) (SocialMedia, error)
 	Delete(context.Context, string) error
 }
 
 type SocialMediaRepository interface {
-	Fetch(context.Context, *[]SocialMedia, string) error
+	Fetch(context.Context, *[]SocialMedia, string, pagination.Query) (string, error)
 	Store(context.Context, *SocialMedia) error
 	GetByID(context.Context, *SocialMedia, string) error
-	Update(context.Context, SocialMedia, string) (SocialMedia, error)
+	GetDetailByID(context.Context, *SocialMedia, string) error
+	Update(context.Context, SocialMedia, SocialMedia) (SocialMedia, error)
 	Delete(context.Context, string) error
 }
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("the refresh token you entered is invalid")
+	ErrRefreshTokenExpired = errors.New("the refresh token you entered has expired")
+	ErrRefreshTokenReused  = errors.New("the refresh token you entered has already been used, sign in again to proceed")
+)
+
+// RefreshToken is a single link in a rotation chain. Every token minted from the
+// same login shares a FamilyID so a replayed token can take the whole chain down.
+type RefreshToken struct {
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID     string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	FamilyID   string     `gorm:"type:VARCHAR(50);not null;index" json:"family_id"`
+	TokenHash  string     `gorm:"type:VARCHAR(64);uniqueIndex;not null" json:"-"`
+	ReplacedBy string     `gorm:"type:VARCHAR(50)" json:"replaced_by,omitempty"`
+	ExpiresAt  *time.Time `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User       *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64
+}
+
+type TokenUseCase interface {
+	Issue(context.Context, User, Client) (Token, error)
+	Refresh(context.Context, string) (Token, error)
+	Revoke(context.Context, RevokedToken) error
+	RevokeRefreshToken(context.Context, string, string) error
+	RevokeSession(context.Context, string, string) error
+	RevokeAll(context.Context, string) error
+}
+
+type RefreshTokenRepository interface {
+	Store(context.Context, *RefreshToken) error
+	GetByHash(context.Context, *RefreshToken, string) error
+	Rotate(context.Context, *RefreshToken, *RefreshToken) error
+	RevokeFamily(context.Context, string) error
+	RevokeUser(context.Context, string) error
+}
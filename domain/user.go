@@ -2,32 +2,102 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"mygram-api/helpers"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/asaskevich/govalidator"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrPasswordTooShort         = errors.New("the password must be at least 6 characters long")
+	ErrPasswordIncorrect        = errors.New("the current password you entered is wrong")
+	ErrEmailVerificationInvalid = errors.New("the verification link you used is invalid or has expired")
+	ErrEmailAlreadyUsed         = errors.New("the email you entered has been used")
+	ErrEmailInvalid             = errors.New("the email you entered is not a valid email address")
+	ErrRoleInvalid              = errors.New("the role you entered is not one of user, moderator or admin")
+	ErrLastAdmin                = errors.New("the last admin cannot be demoted or deleted")
+	ErrAgeRequired              = errors.New("age: non zero value required")
+	ErrUsernameRequired         = errors.New("the username must not be empty")
+	ErrUsernameTooLong          = errors.New("the username must be at most 50 characters long")
+	ErrUsernameReserved         = errors.New("the username you entered is reserved")
+	ErrAgeInvalid               = errors.New("the age must be between 8 and 63")
+	ErrDisplayNameTooLong       = errors.New("the display name must be at most 50 characters long")
+	ErrBioTooLong               = errors.New("the bio must be at most 300 characters long")
+	ErrProfileImageUrlInvalid   = errors.New("the profile image url you entered is not a valid url")
+)
+
+// Role grants permissions beyond owning a resource, see the policy package.
+// Every account starts as RoleUser; other roles are only handed out by admins,
+// so the very first admin has to be promoted directly in the database.
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+func (role Role) IsValid() bool {
+	return role == RoleUser || role == RoleModerator || role == RoleAdmin
+}
+
+// User.Age is optional on the model because accounts created through an OpenID
+// Connect provider don't come with one; Register still demands it.
 type User struct {
 	ID              string         `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
 	Username        string         `gorm:"type:VARCHAR(50);uniqueIndex;not null" valid:"required" form:"username" json:"username" example:"johndoe"`
 	Email           string         `gorm:"type:VARCHAR(50);uniqueIndex;not null" valid:"email,required" form:"email" json:"email" example:"johndoe@example.com"`
 	Password        string         `gorm:"not null" valid:"required,minstringlength(6)" form:"password" json:"password,omitempty" example:"secret"`
-	Age             uint           `gorm:"not null" valid:"required,range(8|63)" form:"age" json:"age,omitempty" example:"8"`
+	Age             uint           `gorm:"not null" valid:"range(8|63)" form:"age" json:"age,omitempty" example:"8"`
 	ProfileImageUrl string         `json:"profileImageUrl,omitempty" example:"https://www.example.com/image.jpg"`
+	AvatarKey       string         `gorm:"type:VARCHAR(100)" json:"-"`
+	DisplayName     string         `gorm:"type:VARCHAR(50)" json:"display_name,omitempty" example:"John Doe"`
+	Bio             string         `gorm:"type:VARCHAR(300)" json:"bio,omitempty" example:"Sunsets and street food."`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at,omitempty"`
+	PendingEmail    string         `gorm:"type:VARCHAR(50)" json:"-"`
+	Role            Role           `gorm:"type:VARCHAR(20);not null;default:user" json:"-"`
+	TOTPSecret      string         `gorm:"column:totp_secret;type:VARCHAR(64)" json:"-"`
+	TOTPEnabledAt   *time.Time     `gorm:"column:totp_enabled_at" json:"-"`
+	TOTPLastStep    int64          `gorm:"column:totp_last_step;not null;default:0" json:"-"`
 	CreatedAt       *time.Time     `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
 	UpdatedAt       *time.Time     `gorm:"not null;autocreateTime" json:"updated_at,omitempty"`
 	Photos          *[]Photo       `json:"-"`
 	SocialMedias    *[]SocialMedia `json:"-"`
 }
 
+// reservedUsernames are the static paths under /users. GET /users/:username
+// never reaches the profile of a user named after one of them.
+var reservedUsernames = map[string]bool{
+	"2fa":          true,
+	"api-keys":     true,
+	"login":        true,
+	"logout":       true,
+	"logout-all":   true,
+	"me":           true,
+	"password":     true,
+	"register":     true,
+	"sessions":     true,
+	"token":        true,
+	"verify-email": true,
+}
+
+// IsReservedUsername tells whether username, in any case, is taken by a route.
+func IsReservedUsername(username string) bool {
+	return reservedUsernames[strings.ToLower(username)]
+}
+
 func (user *User) BeforeCreate(db *gorm.DB) (err error) {
 	if _, err := govalidator.ValidateStruct(user); err != nil {
 		return err
 	}
 
-	user.Password = helpers.Hash(user.Password)
+	if user.Password, err = helpers.Hash(user.Password); err != nil {
+		return err
+	}
 
 	return
 }
@@ -40,16 +110,109 @@ func (user *User) BeforeUpdate(db *gorm.DB) (err error) {
 	return
 }
 
+// Profile is a user as others get to see them, along with a few numbers about
+// their activity. FollowerCount stays 0 until users can follow each other.
+type Profile struct {
+	User          User
+	PhotoCount    int64
+	FollowerCount int64
+	SocialMedias  []SocialMedia
+}
+
+// UserUpdate lists the fields of a user to change, nil ones stay as they are.
+// A new email goes into PendingEmail, it only takes over once it is verified.
+type UserUpdate struct {
+	Username        *string
+	DisplayName     *string
+	Bio             *string
+	Age             *uint
+	ProfileImageUrl *string
+	PendingEmail    *string
+}
+
+// Validate applies the rules of the User model to the fields that are set.
+func (userUpdate UserUpdate) Validate() error {
+	if userUpdate.Username != nil && *userUpdate.Username == "" {
+		return ErrUsernameRequired
+	}
+
+	if userUpdate.Username != nil && utf8.RuneCountInString(*userUpdate.Username) > 50 {
+		return ErrUsernameTooLong
+	}
+
+	if userUpdate.Username != nil && IsReservedUsername(*userUpdate.Username) {
+		return ErrUsernameReserved
+	}
+
+	if userUpdate.DisplayName != nil && utf8.RuneCountInString(*userUpdate.DisplayName) > 50 {
+		return ErrDisplayNameTooLong
+	}
+
+	if userUpdate.Bio != nil && utf8.RuneCountInString(*userUpdate.Bio) > 300 {
+		return ErrBioTooLong
+	}
+
+	if userUpdate.Age != nil && (*userUpdate.Age < 8 || *userUpdate.Age > 63) {
+		return ErrAgeInvalid
+	}
+
+	if userUpdate.ProfileImageUrl != nil && *userUpdate.ProfileImageUrl != "" && !govalidator.IsURL(*userUpdate.ProfileImageUrl) {
+		return ErrProfileImageUrlInvalid
+	}
+
+	if userUpdate.PendingEmail != nil && !govalidator.IsEmail(*userUpdate.PendingEmail) {
+		return ErrEmailInvalid
+	}
+
+	return nil
+}
+
+// ValidatePassword applies the same rules to a new password that the valid tag
+// on User.Password and Register apply on registration. The upper bound keeps
+// clients from handing the hasher arbitrarily much work, whichever it is.
+func ValidatePassword(password string) error {
+	if len(password) < 6 {
+		return ErrPasswordTooShort
+	}
+
+	if len(password) > helpers.PasswordMaxBytes {
+		return helpers.ErrPasswordTooLong
+	}
+
+	return nil
+}
+
 type UserUseCase interface {
 	Register(context.Context, *User) error
 	Login(context.Context, *User) error
-	Update(context.Context, User) (User, error)
+	GetByID(context.Context, *User, string) error
+	Fetch(context.Context, *[]User) error
+	Update(context.Context, string, UserUpdate) (User, error)
+	GetProfileByID(context.Context, *Profile, string) error
+	GetProfileByUsername(context.Context, *Profile, string) error
+	ChangePassword(context.Context, string, string, string) error
+	ChangeRole(context.Context, string, Role) error
 	Delete(context.Context, string) error
 }
 
 type UserRepository interface {
 	Register(context.Context, *User) error
 	Login(context.Context, *User) error
-	Update(context.Context, User) (User, error)
+	GetByID(context.Context, *User, string) error
+	GetByEmail(context.Context, *User, string) error
+	Update(context.Context, string, UserUpdate) (User, error)
+	Fetch(context.Context, *[]User) error
+	GetProfileByID(context.Context, *Profile, string) error
+	GetProfileByUsername(context.Context, *Profile, string) error
+	UpdatePassword(context.Context, string, string) error
+	UpdateAvatar(context.Context, string, string, string) error
+	UpdateRole(context.Context, string, Role) error
+	VerifyEmail(context.Context, string, string) error
 	Delete(context.Context, string) error
 }
+
+type EmailVerificationUseCase interface {
+	Send(context.Context, User) error
+	Verify(context.Context, string) error
+	PrepareEmailChange(context.Context, string, string) (*string, error)
+}
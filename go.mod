@@ -8,11 +8,13 @@ require (
 	github.com/gin-gonic/gin v1.8.1
 	github.com/joho/godotenv v1.4.0
 	github.com/matoous/go-nanoid/v2 v2.0.0
+	github.com/rwcarlsen/goexif v0.0.0-20190401172101-9e8deecbddbd
 	github.com/stretchr/testify v1.8.0
 	github.com/swaggo/files v0.0.0-20220728132757-551d4a08d97a
 	github.com/swaggo/gin-swagger v1.3.2
 	github.com/swaggo/swag v1.8.7
 	golang.org/x/crypto v0.1.0
+	golang.org/x/image v0.1.0
 	gorm.io/driver/postgres v1.4.4
 	gorm.io/gorm v1.24.0
 )
@@ -0,0 +1,121 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+var ErrPasswordHashInvalid = errors.New("the password hash is malformed")
+
+type Argon2idParams struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// Argon2idParamsFromEnv reads ARGON2_MEMORY (KiB), ARGON2_ITERATIONS and
+// ARGON2_PARALLELISM, defaulting to 64 MiB, 3 passes and 2 lanes.
+func Argon2idParamsFromEnv() Argon2idParams {
+	return Argon2idParams{
+		Memory:      uint32(uintFromEnv("ARGON2_MEMORY", 64*1024, 32)),
+		Iterations:  uint32(uintFromEnv("ARGON2_ITERATIONS", 3, 32)),
+		Parallelism: uint8(uintFromEnv("ARGON2_PARALLELISM", 2, 8)),
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// argon2idHasher encodes hashes in the PHC string format used by the reference
+// implementation: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>.
+type argon2idHasher struct {
+	params Argon2idParams
+}
+
+func NewArgon2idHasher(params Argon2idParams) *argon2idHasher {
+	return &argon2idHasher{params}
+}
+
+func (argon2idHasher *argon2idHasher) Hash(password string) (string, error) {
+	params := argon2idHasher.params
+	salt := make([]byte, params.SaltLength)
+
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (argon2idHasher *argon2idHasher) Verify(encoded string, password string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(encoded)
+
+	if err != nil {
+		return false, err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+func (argon2idHasher *argon2idHasher) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (argon2idHasher *argon2idHasher) NeedsRehash(encoded string) bool {
+	params, _, key, err := decodeArgon2id(encoded)
+
+	if err != nil {
+		return true
+	}
+
+	return params.Memory < argon2idHasher.params.Memory ||
+		params.Iterations < argon2idHasher.params.Iterations ||
+		params.Parallelism < argon2idHasher.params.Parallelism ||
+		uint32(len(key)) < argon2idHasher.params.KeyLength
+}
+
+func decodeArgon2id(encoded string) (params Argon2idParams, salt []byte, key []byte, err error) {
+	var version int
+
+	parts := strings.Split(encoded, "$")
+
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrPasswordHashInvalid
+	}
+
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrPasswordHashInvalid
+	}
+
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrPasswordHashInvalid
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return params, nil, nil, ErrPasswordHashInvalid
+	}
+
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(key) == 0 {
+		return params, nil, nil, ErrPasswordHashInvalid
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -1,18 +1,67 @@
 package helpers
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
 
-func Hash(password string) string {
-	salt := 8
-	pass := []byte(password)
-	hash, _ := bcrypt.GenerateFromPassword(pass, salt)
+	"golang.org/x/crypto/bcrypt"
+)
 
-	return string(hash)
+// BcryptCost is read from BCRYPT_COST and defaults to bcrypt.DefaultCost.
+func BcryptCost() int {
+	cost, err := strconv.Atoi(os.Getenv("BCRYPT_COST"))
+
+	if err != nil || cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return bcrypt.DefaultCost
+	}
+
+	return cost
+}
+
+// bcryptHasher keeps bcrypt's own "$2a$<cost>$..." encoding, which already names
+// the algorithm and its cost, so hashes stored before PasswordHasher existed
+// still verify.
+type bcryptHasher struct {
+	cost int
+}
+
+func NewBcryptHasher(cost int) *bcryptHasher {
+	return &bcryptHasher{cost}
+}
+
+func (bcryptHasher *bcryptHasher) Hash(password string) (string, error) {
+	// bcrypt ignores everything past 72 bytes, refuse instead of truncating.
+	if len(password) > PasswordMaxBytes {
+		return "", ErrPasswordTooLong
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptHasher.cost)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+func (bcryptHasher *bcryptHasher) Verify(encoded string, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (bcryptHasher *bcryptHasher) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
 }
 
-func Compare(hashedPassword, password []byte) bool {
-	hash, pass := []byte(hashedPassword), []byte(password)
-	err := bcrypt.CompareHashAndPassword(hash, pass)
+func (bcryptHasher *bcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
 
-	return err == nil
+	return err != nil || cost < bcryptHasher.cost
 }
@@ -0,0 +1,146 @@
+package helpers
+
+import (
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PasswordResetTTL is read from PASSWORD_RESET_TTL (e.g. "1h") and defaults to one hour.
+func PasswordResetTTL() time.Duration {
+	return durationFromEnv("PASSWORD_RESET_TTL", time.Hour)
+}
+
+// PasswordResetURL builds the link mailed to users from PASSWORD_RESET_URL, the
+// front-end page that collects the new password.
+func PasswordResetURL(token string) string {
+	return withToken(stringFromEnv("PASSWORD_RESET_URL", "http://localhost:8080/reset-password"), token)
+}
+
+// EmailVerificationTTL is read from EMAIL_VERIFICATION_TTL (e.g. "24h") and defaults to one day.
+func EmailVerificationTTL() time.Duration {
+	return durationFromEnv("EMAIL_VERIFICATION_TTL", 24*time.Hour)
+}
+
+// EmailVerificationURL builds the link mailed to users from EMAIL_VERIFICATION_URL,
+// the front-end page that posts the token back to /users/verify-email.
+func EmailVerificationURL(token string) string {
+	return withToken(stringFromEnv("EMAIL_VERIFICATION_URL", "http://localhost:8080/verify-email"), token)
+}
+
+// MFAPendingTTL is read from MFA_PENDING_TTL and defaults to five minutes, the time
+// a user has to enter their second factor after the password was accepted.
+func MFAPendingTTL() time.Duration {
+	return durationFromEnv("MFA_PENDING_TTL", 5*time.Minute)
+}
+
+// OIDCLoginTTL is read from OIDC_LOGIN_TTL and defaults to ten minutes, the time
+// a user has to sign in at the provider once the login was started.
+func OIDCLoginTTL() time.Duration {
+	return durationFromEnv("OIDC_LOGIN_TTL", 10*time.Minute)
+}
+
+// DevelopmentMode reports whether APP_ENV is "development". Shortcuts that must
+// never reach production, like signing access tokens with a throwaway key, are
+// only taken then.
+func DevelopmentMode() bool {
+	return os.Getenv("APP_ENV") == "development"
+}
+
+// RequireVerifiedEmail reports whether REQUIRE_VERIFIED_EMAIL is "true", in which
+// case users have to verify their email before posting photos or comments.
+func RequireVerifiedEmail() bool {
+	return os.Getenv("REQUIRE_VERIFIED_EMAIL") == "true"
+}
+
+// PhotoVariantWidths is read from PHOTO_VARIANT_WIDTHS, a comma separated list
+// of pixel widths, and defaults to 320, 640 and 1080. Entries that are not
+// positive numbers are skipped.
+func PhotoVariantWidths() []int {
+	widths := []int{}
+
+	for _, field := range strings.Split(os.Getenv("PHOTO_VARIANT_WIDTHS"), ",") {
+		if width, err := strconv.Atoi(strings.TrimSpace(field)); err == nil && width > 0 {
+			widths = append(widths, width)
+		}
+	}
+
+	if len(widths) == 0 {
+		return []int{320, 640, 1080}
+	}
+
+	sort.Ints(widths)
+
+	unique := widths[:1]
+
+	for _, width := range widths[1:] {
+		if width != unique[len(unique)-1] {
+			unique = append(unique, width)
+		}
+	}
+
+	return unique
+}
+
+// PhotoVariantWorkers is read from PHOTO_VARIANT_WORKERS and defaults to two, the
+// number of photos whose variants are rendered at the same time.
+func PhotoVariantWorkers() int {
+	return int(uintFromEnv("PHOTO_VARIANT_WORKERS", 2, 8))
+}
+
+// PasswordResetWorkers is read from PASSWORD_RESET_WORKERS and defaults to two,
+// the number of password reset emails sent at the same time.
+func PasswordResetWorkers() int {
+	return int(uintFromEnv("PASSWORD_RESET_WORKERS", 2, 8))
+}
+
+func withToken(base string, token string) string {
+	link, err := url.Parse(base)
+
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String()
+}
+
+func stringFromEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+
+	return duration
+}
+
+func uintFromEnv(key string, fallback uint64, bitSize int) uint64 {
+	value, err := strconv.ParseUint(os.Getenv(key), 10, bitSize)
+
+	if err != nil || value == 0 {
+		return fallback
+	}
+
+	return value
+}
@@ -2,58 +2,160 @@ package helpers
 
 import (
 	"errors"
-	"log"
-	"os"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
+	gonanoid "github.com/matoous/go-nanoid/v2"
 )
 
-func GenerateToken(id string, email string) string {
-	claims := jwt.MapClaims{
-		"id":    id,
-		"email": email,
+var (
+	ErrTokenExpired = errors.New("your token has expired, please refresh it")
+	ErrTokenRevoked = errors.New("your token has been revoked, sign in to proceed")
+)
+
+// AccessTokenTTL is read from ACCESS_TOKEN_TTL (e.g. "15m") and defaults to 15 minutes.
+func AccessTokenTTL() time.Duration {
+	return durationFromEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+}
+
+// RefreshTokenTTL is read from REFRESH_TOKEN_TTL (e.g. "720h") and defaults to 30 days.
+func RefreshTokenTTL() time.Duration {
+	return durationFromEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+}
+
+// TokenIssuer is read from JWT_ISSUER and names MyGram in the iss claim.
+func TokenIssuer() string {
+	return stringFromEnv("JWT_ISSUER", "mygram-api")
+}
+
+// TokenAudience is read from JWT_AUDIENCE and is what access tokens are meant
+// for in the aud claim.
+func TokenAudience() string {
+	return stringFromEnv("JWT_AUDIENCE", "mygram-api")
+}
+
+// AccessClaims are the application claims embedded in every access token.
+type AccessClaims struct {
+	ID            string
+	Email         string
+	EmailVerified bool
+	Role          string
+	SessionID     string
+}
+
+func GenerateToken(accessClaims AccessClaims) (string, error) {
+	jti, err := gonanoid.New(21)
+
+	if err != nil {
+		return "", err
 	}
 
-	parseToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	keyRing, err := currentKeyRing()
+
+	if err != nil {
+		return "", err
+	}
 
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file: ", err)
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"id":             accessClaims.ID,
+		"email":          accessClaims.Email,
+		"email_verified": accessClaims.EmailVerified,
+		"role":           accessClaims.Role,
+		"sid":            accessClaims.SessionID,
+		"jti":            jti,
+		// Millisecond precision keeps a token minted right after a "log out everywhere"
+		// from being caught by the cutoff that was just written.
+		"iat": float64(now.UnixMilli()) / 1000,
+		"nbf": now.Unix(),
+		"exp": now.Add(AccessTokenTTL()).Unix(),
+		"iss": TokenIssuer(),
+		"aud": TokenAudience(),
 	}
 
-	signedToken, _ := parseToken.SignedString([]byte(os.Getenv("TOKEN_KEY")))
+	parseToken := jwt.NewWithClaims(keyRing.signing.Method, claims)
+	parseToken.Header["kid"] = keyRing.signing.ID
 
-	return signedToken
+	return parseToken.SignedString(keyRing.signing.privateKey)
 }
 
 func VerifyToken(ctx *gin.Context) (interface{}, error) {
 	errResponse := errors.New("sign in to proceed")
 	headerToken := ctx.Request.Header.Get("Authorization")
-	bearer := strings.HasPrefix(headerToken, "Bearer")
+	bearer := strings.HasPrefix(headerToken, "Bearer ")
 
 	if !bearer {
 		return nil, errResponse
 	}
 
-	stringToken := strings.Split(headerToken, " ")[1]
+	stringToken := strings.TrimPrefix(headerToken, "Bearer ")
+
+	keyRing, err := currentKeyRing()
 
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file: ", err)
+	if err != nil {
+		return nil, errResponse
 	}
 
-	token, _ := jwt.Parse(stringToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+	// The algorithm is taken from the key, never from the token, so a token
+	// can't talk its way into being checked as HS256 against a public key.
+	token, err := jwt.Parse(stringToken, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keyRing.VerificationKey(kid)
+
+		if !ok || token.Method.Alg() != key.Method.Alg() {
 			return nil, errResponse
 		}
 
-		return []byte(os.Getenv("TOKEN_KEY")), nil
+		return key.PublicKey, nil
 	})
 
-	if _, ok := token.Claims.(jwt.MapClaims); !ok && !token.Valid {
+	if err != nil {
+		var validationErr *jwt.ValidationError
+
+		if errors.As(err, &validationErr) && validationErr.Errors&jwt.ValidationErrorExpired != 0 {
+			return nil, ErrTokenExpired
+		}
+
+		return nil, errResponse
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
 		return nil, errResponse
 	}
 
-	return token.Claims.(jwt.MapClaims), nil
+	// jwt-go only checks exp, iat and nbf when they are present, so tokens
+	// missing any of them, or iss and aud, are refused outright.
+	for _, name := range []string{"exp", "iat", "nbf"} {
+		if _, ok := claims[name]; !ok {
+			return nil, errResponse
+		}
+	}
+
+	if !claims.VerifyIssuer(TokenIssuer(), true) || !claims.VerifyAudience(TokenAudience(), true) {
+		return nil, errResponse
+	}
+
+	if revocationChecker != nil {
+		jti, _ := claims["jti"].(string)
+		sid, _ := claims["sid"].(string)
+		userID, _ := claims["id"].(string)
+		iat, _ := claims["iat"].(float64)
+
+		revoked, err := revocationChecker.IsRevoked(ctx.Request.Context(), jti, sid, userID, time.UnixMilli(int64(math.Round(iat*1000))))
+
+		if err != nil {
+			return nil, errResponse
+		}
+
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	return claims, nil
 }
@@ -0,0 +1,209 @@
+package helpers_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"mygram-api/helpers"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKey(t *testing.T, key interface{}, public bool) string {
+	var (
+		block *pem.Block
+		der   []byte
+		err   error
+	)
+
+	if public {
+		der, err = x509.MarshalPKIXPublicKey(key)
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	} else {
+		der, err = x509.MarshalPKCS8PrivateKey(key)
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	}
+
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0o600))
+
+	return path
+}
+
+func verify(token string) (interface{}, error) {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "/", nil)
+	ctx.Request.Header.Set("Authorization", "Bearer "+token)
+
+	return helpers.VerifyToken(ctx)
+}
+
+func useKeys(t *testing.T, privateKeyFile string, verificationKeyFiles string) *helpers.KeyRing {
+	t.Setenv("JWT_PRIVATE_KEY_FILE", privateKeyFile)
+	t.Setenv("JWT_VERIFICATION_KEY_FILES", verificationKeyFiles)
+
+	keyRing, err := helpers.KeyRingFromEnv()
+	require.NoError(t, err)
+
+	helpers.UseKeyRing(keyRing)
+
+	return keyRing
+}
+
+func TestAccessToken(t *testing.T) {
+	publicEd25519, privateEd25519, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateRSA, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	claims := helpers.AccessClaims{ID: "user-123", Email: "johndoe@example.com", Role: "user"}
+
+	t.Run("access token signed with EdDSA", func(t *testing.T) {
+		useKeys(t, writeKey(t, privateEd25519, false), "")
+
+		token, err := helpers.GenerateToken(claims)
+		require.NoError(t, err)
+
+		parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, "EdDSA", parsed.Header["alg"])
+		assert.NotEmpty(t, parsed.Header["kid"])
+
+		verified, err := verify(token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", verified.(jwt.MapClaims)["id"])
+		assert.Equal(t, "mygram-api", verified.(jwt.MapClaims)["iss"])
+	})
+
+	t.Run("access token signed with RS256", func(t *testing.T) {
+		useKeys(t, writeKey(t, privateRSA, false), "")
+
+		token, err := helpers.GenerateToken(claims)
+		require.NoError(t, err)
+
+		_, err = verify(token)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("access token signed before a key rotation", func(t *testing.T) {
+		useKeys(t, writeKey(t, privateEd25519, false), "")
+
+		token, err := helpers.GenerateToken(claims)
+		require.NoError(t, err)
+
+		keyRing := useKeys(t, writeKey(t, privateRSA, false), writeKey(t, publicEd25519, true))
+
+		_, err = verify(token)
+
+		assert.NoError(t, err)
+		assert.Len(t, keyRing.JWKS().Keys, 2)
+	})
+
+	t.Run("access token signed with a retired key", func(t *testing.T) {
+		useKeys(t, writeKey(t, privateEd25519, false), "")
+
+		token, err := helpers.GenerateToken(claims)
+		require.NoError(t, err)
+
+		useKeys(t, writeKey(t, privateRSA, false), "")
+
+		_, err = verify(token)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("access token for another audience", func(t *testing.T) {
+		useKeys(t, writeKey(t, privateEd25519, false), "")
+		t.Setenv("JWT_AUDIENCE", "another-service")
+
+		token, err := helpers.GenerateToken(claims)
+		require.NoError(t, err)
+
+		t.Setenv("JWT_AUDIENCE", "")
+
+		_, err = verify(token)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("access token signed with HS256 against the public key", func(t *testing.T) {
+		keyRing := useKeys(t, writeKey(t, privateRSA, false), "")
+		jwk := keyRing.JWKS().Keys[0]
+
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"id": "user-123"})
+		forged.Header["kid"] = jwk.Kid
+
+		token, err := forged.SignedString([]byte(jwk.N))
+		require.NoError(t, err)
+
+		_, err = verify(token)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestKeyRingFromEnv(t *testing.T) {
+	t.Setenv("JWT_PRIVATE_KEY_FILE", "")
+	t.Setenv("JWT_VERIFICATION_KEY_FILES", "")
+
+	t.Run("key ring without a private key", func(t *testing.T) {
+		t.Setenv("APP_ENV", "")
+
+		_, err := helpers.KeyRingFromEnv()
+
+		assert.Error(t, err)
+	})
+
+	t.Run("key ring without a private key in development", func(t *testing.T) {
+		t.Setenv("APP_ENV", "development")
+
+		keyRing, err := helpers.KeyRingFromEnv()
+
+		assert.NoError(t, err)
+		assert.Len(t, keyRing.JWKS().Keys, 1)
+	})
+}
+
+func TestSign(t *testing.T) {
+	t.Run("sign and verify", func(t *testing.T) {
+		t.Setenv("SIGNING_KEY", "secret")
+
+		token, err := helpers.Sign("test", "data", time.Minute)
+		require.NoError(t, err)
+
+		data := ""
+
+		assert.NoError(t, helpers.Verify("test", token, &data))
+		assert.Equal(t, "data", data)
+		assert.ErrorIs(t, helpers.Verify("another", token, &data), helpers.ErrSignatureInvalid)
+	})
+
+	t.Run("sign and verify without a signing key", func(t *testing.T) {
+		t.Setenv("SIGNING_KEY", "secret")
+
+		token, err := helpers.Sign("test", "data", time.Minute)
+		require.NoError(t, err)
+
+		t.Setenv("SIGNING_KEY", "")
+
+		_, err = helpers.Sign("test", "data", time.Minute)
+
+		assert.ErrorIs(t, err, helpers.ErrSigningKeyMissing)
+		assert.ErrorIs(t, helpers.Verify("test", token, new(string)), helpers.ErrSignatureInvalid)
+	})
+}
@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// PasswordMaxBytes is the longest password accepted. It is bcrypt's limit, and
+// holds for every hasher so switching algorithms never strands a password.
+const PasswordMaxBytes = 72
+
+var ErrPasswordTooLong = errors.New("the password must be at most 72 bytes long")
+
+// PasswordHasher produces and checks self-describing password hashes. The
+// algorithm and its parameters are encoded in the hash itself, so a hasher can
+// tell whether it made a hash and whether it was made with weaker settings.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(encoded string, password string) (bool, error)
+	Identifies(encoded string) bool
+	NeedsRehash(encoded string) bool
+}
+
+// PasswordHasherFromEnv returns the hasher used for new hashes, chosen by
+// PASSWORD_HASH_ALGORITHM ("argon2id", the default, or "bcrypt").
+func PasswordHasherFromEnv() PasswordHasher {
+	switch strings.ToLower(os.Getenv("PASSWORD_HASH_ALGORITHM")) {
+	case "bcrypt":
+		return NewBcryptHasher(BcryptCost())
+	default:
+		return NewArgon2idHasher(Argon2idParamsFromEnv())
+	}
+}
+
+// passwordHashers are every algorithm a stored hash may have been made with.
+func passwordHashers() []PasswordHasher {
+	return []PasswordHasher{
+		NewArgon2idHasher(Argon2idParamsFromEnv()),
+		NewBcryptHasher(BcryptCost()),
+	}
+}
+
+func Hash(password string) (string, error) {
+	return PasswordHasherFromEnv().Hash(password)
+}
+
+func Compare(hashedPassword, password []byte) bool {
+	for _, hasher := range passwordHashers() {
+		if hasher.Identifies(string(hashedPassword)) {
+			isValid, err := hasher.Verify(string(hashedPassword), string(password))
+
+			return err == nil && isValid
+		}
+	}
+
+	return false
+}
+
+// NeedsRehash reports whether hashedPassword was made with another algorithm or
+// with weaker parameters than the ones currently configured.
+func NeedsRehash(hashedPassword []byte) bool {
+	hasher := PasswordHasherFromEnv()
+
+	if !hasher.Identifies(string(hashedPassword)) {
+		return true
+	}
+
+	return hasher.NeedsRehash(string(hashedPassword))
+}
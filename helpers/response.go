@@ -9,3 +9,11 @@ type ResponseMessage struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
 }
+
+// ResponsePage is ResponseData for one page of a list. NextCursor is left out
+// on the last page.
+type ResponsePage struct {
+	Status     string      `json:"status"`
+	Data       interface{} `json:"data"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
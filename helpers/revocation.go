@@ -0,0 +1,20 @@
+package helpers
+
+import (
+	"context"
+	"time"
+)
+
+// RevocationChecker reports whether an access token that is otherwise valid has
+// been revoked, on its own (by jti), with its session, or together with every
+// token of its user.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, jti string, sessionID string, userID string, issuedAt time.Time) (bool, error)
+}
+
+var revocationChecker RevocationChecker
+
+// UseRevocationChecker makes VerifyToken consult checker for every token it accepts.
+func UseRevocationChecker(checker RevocationChecker) {
+	revocationChecker = checker
+}
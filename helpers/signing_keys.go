@@ -0,0 +1,280 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// SigningKey is a key access tokens are signed or verified with. Its ID is the
+// RFC 7638 thumbprint of the public key and goes into the kid header.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PublicKey  crypto.PublicKey
+	privateKey crypto.PrivateKey
+}
+
+// KeyRing holds the key new tokens are signed with and every key tokens are
+// still accepted from. To rotate without logging anyone out, list the new
+// public key in JWT_VERIFICATION_KEY_FILES and deploy, swap it with the old
+// one in JWT_PRIVATE_KEY_FILE and deploy, and drop the old public key once
+// the last token signed with it has expired.
+type KeyRing struct {
+	signing      *SigningKey
+	verification map[string]*SigningKey
+}
+
+// JWK is a public key in the form /.well-known/jwks.json publishes it.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+var (
+	keyRingMu     sync.Mutex
+	activeKeyRing *KeyRing
+)
+
+// UseKeyRing makes GenerateToken and VerifyToken use keyRing.
+func UseKeyRing(keyRing *KeyRing) {
+	keyRingMu.Lock()
+	defer keyRingMu.Unlock()
+
+	activeKeyRing = keyRing
+}
+
+// currentKeyRing returns the key ring set with UseKeyRing, loading it from the
+// environment on first use when none was set.
+func currentKeyRing() (*KeyRing, error) {
+	keyRingMu.Lock()
+	defer keyRingMu.Unlock()
+
+	if activeKeyRing == nil {
+		keyRing, err := KeyRingFromEnv()
+
+		if err != nil {
+			return nil, err
+		}
+
+		activeKeyRing = keyRing
+	}
+
+	return activeKeyRing, nil
+}
+
+// KeyRingFromEnv signs with the PEM encoded RSA or Ed25519 private key in
+// JWT_PRIVATE_KEY_FILE and also accepts the public keys in the comma separated
+// JWT_VERIFICATION_KEY_FILES. Without a private key it fails, unless in
+// DevelopmentMode, where it makes up an Ed25519 key so tokens only last until
+// the next restart.
+func KeyRingFromEnv() (*KeyRing, error) {
+	var (
+		signing *SigningKey
+		err     error
+	)
+
+	if path := os.Getenv("JWT_PRIVATE_KEY_FILE"); path != "" {
+		if signing, err = signingKeyFromFile(path); err != nil {
+			return nil, err
+		}
+
+		if signing.privateKey == nil {
+			return nil, fmt.Errorf("%s holds no private key", path)
+		}
+	} else if !DevelopmentMode() {
+		return nil, errors.New("JWT_PRIVATE_KEY_FILE is not set, set APP_ENV=development to sign access tokens with a throwaway key instead")
+	} else {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if signing, err = newSigningKey(privateKey); err != nil {
+			return nil, err
+		}
+
+		log.Printf("JWT_PRIVATE_KEY_FILE is not set, access tokens are signed with a throwaway key")
+	}
+
+	keyRing := &KeyRing{signing: signing, verification: map[string]*SigningKey{signing.ID: signing}}
+
+	for _, path := range strings.FieldsFunc(os.Getenv("JWT_VERIFICATION_KEY_FILES"), func(r rune) bool { return r == ',' }) {
+		key, err := signingKeyFromFile(strings.TrimSpace(path))
+
+		if err != nil {
+			return nil, err
+		}
+
+		keyRing.verification[key.ID] = key
+	}
+
+	return keyRing, nil
+}
+
+// VerificationKey returns the key with the given id when it is one that tokens
+// are accepted from.
+func (keyRing *KeyRing) VerificationKey(id string) (*SigningKey, bool) {
+	key, ok := keyRing.verification[id]
+
+	return key, ok
+}
+
+// JWKS lists the public half of every key tokens are accepted from.
+func (keyRing *KeyRing) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+
+	for _, key := range keyRing.verification {
+		set.Keys = append(set.Keys, key.JWK())
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+
+	return set
+}
+
+func (key *SigningKey) JWK() JWK {
+	jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+
+	switch publicKey := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	}
+
+	return jwk
+}
+
+func signingKeyFromFile(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return nil, fmt.Errorf("%s is not PEM encoded", path)
+	}
+
+	var key interface{}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s holds a %s, expected a private or public key", path, block.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	signingKey, err := newSigningKey(key)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return signingKey, nil
+}
+
+func newSigningKey(key interface{}) (*SigningKey, error) {
+	signingKey := &SigningKey{}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		signingKey.privateKey = key
+		signingKey.PublicKey = &key.PublicKey
+	case *rsa.PublicKey:
+		signingKey.PublicKey = key
+	case ed25519.PrivateKey:
+		signingKey.privateKey = key
+		signingKey.PublicKey = key.Public()
+	case ed25519.PublicKey:
+		signingKey.PublicKey = key
+	default:
+		return nil, errors.New("only RSA and Ed25519 keys are supported")
+	}
+
+	switch publicKey := signingKey.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if publicKey.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys must be at least 2048 bits long")
+		}
+
+		signingKey.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		signingKey.Method = SigningMethodEdDSA
+	}
+
+	signingKey.ID = thumbprint(signingKey.JWK())
+
+	return signingKey, nil
+}
+
+// thumbprint is the RFC 7638 thumbprint of jwk: the SHA-256 of its required
+// members in lexicographic order.
+func thumbprint(jwk JWK) string {
+	var members interface{}
+
+	if jwk.Kty == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	encoded, _ := json.Marshal(members)
+	sum := sha256.Sum256(encoded)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,137 @@
+// Package imaging decodes untrusted uploads and turns them into the images
+// MyGram serves. Everything it writes is either re-encoded from decoded pixels
+// or has its metadata cut out, so nothing private of the original file survives.
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mygram-api/domain"
+
+	_ "image/gif"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels caps the decoded size of an upload, a few kilobytes of compressed
+// data can otherwise claim gigabytes of memory.
+const MaxPixels = 40_000_000
+
+// Sniff names the format of data by its magic bytes, whatever the client said it was.
+func Sniff(data []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("\xff\xd8\xff")):
+		return "jpeg", nil
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return "png", nil
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return "gif", nil
+	case len(data) >= 12 && bytes.Equal(data[:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP")):
+		return "webp", nil
+	}
+
+	return "", domain.ErrImageUnsupported
+}
+
+// Info describes an image without decoding its pixels.
+type Info struct {
+	Format string
+	Width  int
+	Height int
+}
+
+// Inspect reads the format and the dimensions of data from its header.
+func Inspect(data []byte) (info Info, err error) {
+	if info.Format, err = Sniff(data); err != nil {
+		return info, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+
+	if err != nil {
+		return info, domain.ErrImageUnsupported
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return info, domain.ErrImageTooLarge
+	}
+
+	info.Width, info.Height = config.Width, config.Height
+
+	return info, nil
+}
+
+// Decode checks the format and the dimensions of data before decoding it.
+func Decode(data []byte) (image.Image, error) {
+	if _, err := Inspect(data); err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, domain.ErrImageUnsupported
+	}
+
+	return img, nil
+}
+
+// Square crops the largest centered square out of img and scales it to size.
+func Square(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	side := bounds.Dx()
+
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+
+	crop := image.Rect(0, 0, side, side).Add(bounds.Min).Add(image.Pt((bounds.Dx()-side)/2, (bounds.Dy()-side)/2))
+	square := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	// JPEG has no transparency, so see-through parts end up on white rather than black.
+	draw.Draw(square, square.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(square, square.Bounds(), img, crop, draw.Over, nil)
+
+	return square
+}
+
+// Resize scales img to width pixels across, keeping its aspect ratio and any
+// transparency.
+func Resize(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	height := (bounds.Dy()*width + bounds.Dx()/2) / bounds.Dx()
+
+	if height < 1 {
+		height = 1
+	}
+
+	resized := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	draw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, draw.Src, nil)
+
+	return resized
+}
+
+// Opaque reports whether every pixel of img is fully opaque, which is when it
+// can be written as a JPEG without losing anything but detail.
+func Opaque(img image.Image) bool {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return opaque.Opaque()
+	}
+
+	return false
+}
+
+// EncodeJPEG writes img as a baseline JPEG without any metadata.
+func EncodeJPEG(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+}
+
+// EncodePNG writes img as a PNG without any metadata.
+func EncodePNG(w io.Writer, img image.Image) error {
+	return png.Encode(w, img)
+}
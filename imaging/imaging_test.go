@@ -0,0 +1,133 @@
+package imaging_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"mygram-api/domain"
+	"mygram-api/imaging"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniff(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	pngData, gifData, jpegData := bytes.Buffer{}, bytes.Buffer{}, bytes.Buffer{}
+
+	png.Encode(&pngData, img)
+	gif.Encode(&gifData, img, nil)
+	jpeg.Encode(&jpegData, img, nil)
+
+	for _, tc := range []struct {
+		name   string
+		data   []byte
+		format string
+		err    error
+	}{
+		{"png", pngData.Bytes(), "png", nil},
+		{"gif", gifData.Bytes(), "gif", nil},
+		{"jpeg", jpegData.Bytes(), "jpeg", nil},
+		{"webp", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), "webp", nil},
+		{"svg", []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"/>"), "", domain.ErrImageUnsupported},
+		{"empty", nil, "", domain.ErrImageUnsupported},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			format, err := imaging.Sniff(tc.data)
+
+			assert.Equal(t, tc.format, format)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("decode correctly", func(t *testing.T) {
+		data := bytes.Buffer{}
+
+		png.Encode(&data, image.NewRGBA(image.Rect(0, 0, 3, 2)))
+
+		img, err := imaging.Decode(data.Bytes())
+
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 3, 2), img.Bounds())
+	})
+
+	t.Run("decode a truncated image", func(t *testing.T) {
+		data := bytes.Buffer{}
+
+		png.Encode(&data, image.NewRGBA(image.Rect(0, 0, 30, 20)))
+
+		_, err := imaging.Decode(data.Bytes()[:40])
+
+		assert.ErrorIs(t, err, domain.ErrImageUnsupported)
+	})
+
+	t.Run("decode an image claiming too many pixels", func(t *testing.T) {
+		data := bytes.Buffer{}
+
+		png.Encode(&data, image.NewGray(image.Rect(0, 0, 1, 1)))
+
+		header := data.Bytes()
+
+		// Claim 100000 x 100000 pixels in the IHDR chunk and fix up its checksum.
+		copy(header[16:24], []byte{0, 1, 0x86, 0xa0, 0, 1, 0x86, 0xa0})
+		binary.BigEndian.PutUint32(header[29:33], crc32.ChecksumIEEE(header[12:29]))
+
+		_, err := imaging.Decode(header)
+
+		assert.ErrorIs(t, err, domain.ErrImageTooLarge)
+	})
+}
+
+func TestSquare(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 300, 100))
+
+	// Only the middle third is red, the square crop must keep just that.
+	for x := 100; x < 200; x++ {
+		for y := 0; y < 100; y++ {
+			img.Set(x, y, color.RGBA{255, 0, 0, 255})
+		}
+	}
+
+	square := imaging.Square(img, 50)
+
+	assert.Equal(t, image.Rect(0, 0, 50, 50), square.Bounds())
+
+	r, g, b, _ := square.At(2, 25).RGBA()
+
+	assert.Equal(t, uint32(0xffff), r)
+	assert.Less(t, g, uint32(0x1000))
+	assert.Less(t, b, uint32(0x1000))
+}
+
+func TestResize(t *testing.T) {
+	t.Run("resize keeping the aspect ratio", func(t *testing.T) {
+		resized := imaging.Resize(image.NewRGBA(image.Rect(0, 0, 1000, 750)), 320)
+
+		assert.Equal(t, image.Rect(0, 0, 320, 240), resized.Bounds())
+	})
+
+	t.Run("resize keeping transparency", func(t *testing.T) {
+		resized := imaging.Resize(image.NewNRGBA(image.Rect(0, 0, 40, 20)), 10)
+
+		assert.False(t, imaging.Opaque(resized))
+	})
+
+	t.Run("resize a very wide image", func(t *testing.T) {
+		resized := imaging.Resize(image.NewRGBA(image.Rect(0, 0, 5000, 1)), 100)
+
+		assert.Equal(t, image.Rect(0, 0, 100, 1), resized.Bounds())
+	})
+}
+
+func TestOpaque(t *testing.T) {
+	assert.True(t, imaging.Opaque(image.NewYCbCr(image.Rect(0, 0, 2, 2), image.YCbCrSubsampleRatio420)))
+	assert.False(t, imaging.Opaque(image.NewNRGBA(image.Rect(0, 0, 2, 2))))
+	assert.False(t, imaging.Opaque(image.NewUniform(color.Transparent)))
+}
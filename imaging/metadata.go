@@ -0,0 +1,564 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"math"
+	"mygram-api/domain"
+	"strings"
+	"time"
+
+	"github.com/rwcarlsen/goexif/exif"
+	"golang.org/x/image/draw"
+)
+
+// Metadata holds the EXIF fields MyGram keeps of an upload.
+type Metadata struct {
+	CameraMake  string
+	CameraModel string
+	LensModel   string
+	TakenAt     *time.Time
+	Orientation int
+	Latitude    *float64
+	Longitude   *float64
+}
+
+// metadataMaxLength caps the text fields of Metadata, they are free-form and
+// written by whatever produced the file.
+const metadataMaxLength = 100
+
+// Extract reads the EXIF block of a JPEG, PNG or WebP image. Images without one,
+// or with one that doesn't parse, have empty Metadata.
+func Extract(data []byte) (metadata Metadata) {
+	block := exifBlock(data)
+
+	if block == nil {
+		return metadata
+	}
+
+	x, err := exif.Decode(bytes.NewReader(block))
+
+	if x == nil || (err != nil && exif.IsCriticalError(err)) {
+		return metadata
+	}
+
+	metadata.CameraMake = exifString(x, exif.Make)
+	metadata.CameraModel = exifString(x, exif.Model)
+	metadata.LensModel = exifString(x, exif.LensModel)
+
+	if takenAt, err := x.DateTime(); err == nil {
+		metadata.TakenAt = &takenAt
+	}
+
+	if tag, err := x.Get(exif.Orientation); err == nil {
+		if orientation, err := tag.Int(0); err == nil && orientation >= 1 && orientation <= 8 {
+			metadata.Orientation = orientation
+		}
+	}
+
+	if latitude, longitude, err := x.LatLong(); err == nil && math.Abs(latitude) <= 90 && math.Abs(longitude) <= 180 {
+		metadata.Latitude, metadata.Longitude = &latitude, &longitude
+	}
+
+	return metadata
+}
+
+func exifString(x *exif.Exif, name exif.FieldName) string {
+	tag, err := x.Get(name)
+
+	if err != nil {
+		return ""
+	}
+
+	value, err := tag.StringVal()
+
+	if err != nil {
+		return ""
+	}
+
+	value = strings.TrimSpace(strings.ToValidUTF8(strings.TrimRight(value, "\x00"), ""))
+
+	if len(value) > metadataMaxLength {
+		value = strings.ToValidUTF8(value[:metadataMaxLength], "")
+	}
+
+	return value
+}
+
+// Normalize returns data as it should be stored: turned upright when orientation
+// says the camera was held sideways, and without EXIF, XMP or text metadata,
+// which is where locations and serial numbers hide. Turned images are encoded
+// again, as JPEG when data was one and as PNG otherwise.
+func Normalize(data []byte, orientation int) ([]byte, error) {
+	if orientation <= 1 {
+		return Strip(data)
+	}
+
+	format, err := Sniff(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := Decode(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := bytes.Buffer{}
+
+	if format == "jpeg" {
+		err = EncodeJPEG(&buffer, Orient(img, orientation))
+	} else {
+		err = EncodePNG(&buffer, Orient(img, orientation))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Strip cuts the metadata out of a JPEG, PNG, WebP or GIF image without touching
+// its pixels. Color profiles stay.
+func Strip(data []byte) ([]byte, error) {
+	format, err := Sniff(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case "jpeg":
+		return stripJPEG(data)
+	case "png":
+		return stripPNG(data)
+	case "webp":
+		return stripWebP(data)
+	case "gif":
+		return stripGIF(data)
+	}
+
+	return data, nil
+}
+
+// Orient turns img upright according to an EXIF orientation between 1 and 8.
+func Orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	src := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	w, h := bounds.Dx(), bounds.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+
+	// Orientations 5 to 8 swap the sides.
+	if orientation >= 5 {
+		dst = image.NewNRGBA(image.Rect(0, 0, h, w))
+	}
+
+	for y := 0; y < dst.Rect.Dy(); y++ {
+		for x := 0; x < dst.Rect.Dx(); x++ {
+			var sx, sy int
+
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+		}
+	}
+
+	return dst
+}
+
+// exifBlock finds the TIFF structure holding the EXIF data of an image.
+func exifBlock(data []byte) []byte {
+	format, err := Sniff(data)
+
+	if err != nil {
+		return nil
+	}
+
+	var block []byte
+
+	switch format {
+	case "jpeg":
+		walkJPEG(data, 2, func(marker byte, segment []byte) bool {
+			if marker == 0xe1 && bytes.HasPrefix(segment[4:], []byte("Exif\x00\x00")) {
+				block = segment[4:]
+
+				return false
+			}
+
+			return true
+		})
+	case "png":
+		walkPNG(data, func(kind string, chunk []byte) bool {
+			if kind == "eXIf" {
+				block = chunk[8 : len(chunk)-4]
+
+				return false
+			}
+
+			return true
+		})
+	case "webp":
+		walkWebP(data, func(kind string, chunk []byte) bool {
+			if kind == "EXIF" {
+				block = chunk[8:]
+
+				return false
+			}
+
+			return true
+		})
+	}
+
+	// The block of a JPEG starts with an "Exif" header, which some writers keep
+	// in PNG and WebP too. goexif understands it with or without.
+	return block
+}
+
+// strippedJPEGMarkers are APP1 (EXIF and XMP), APP13 (IPTC) and comments.
+var strippedJPEGMarkers = map[byte]bool{0xe1: true, 0xed: true, 0xfe: true}
+
+// stripJPEG drops the metadata segments before the first scan and between the
+// scans of a progressive JPEG, where writers may put them as well.
+func stripJPEG(data []byte) ([]byte, error) {
+	stripped := append([]byte{}, data[:2]...)
+
+	keep := func(marker byte, segment []byte) bool {
+		// APP2 also holds the index of further images appended to the file.
+		if !strippedJPEGMarkers[marker] && !(marker == 0xe2 && bytes.HasPrefix(segment[4:], []byte("MPF\x00"))) {
+			stripped = append(stripped, segment...)
+		}
+
+		return true
+	}
+
+	for i := 2; ; {
+		next := walkJPEG(data, i, keep)
+
+		if next < 0 {
+			return nil, domain.ErrImageUnsupported
+		}
+
+		// Anything after the end of the image, like the previews and depth maps
+		// phones append with their own EXIF, is dropped.
+		if data[next+1] == 0xd9 {
+			return append(stripped, 0xff, 0xd9), nil
+		}
+
+		end := scanEnd(data, next)
+
+		// A file cut off within the image data keeps what there is of it.
+		if end < 0 {
+			return append(stripped, data[next:]...), nil
+		}
+
+		stripped = append(stripped, data[next:end]...)
+		i = end
+	}
+}
+
+// walkJPEG calls visit with each marker segment from start up to the next start
+// of scan or end of image, markers included, until visit returns false. It
+// returns where the segments end, or -1 when they are malformed.
+func walkJPEG(data []byte, start int, visit func(marker byte, segment []byte) bool) int {
+	i := start
+
+	for i+2 <= len(data) {
+		if data[i] != 0xff {
+			return -1
+		}
+
+		marker := data[i+1]
+
+		// Markers may be preceded by any number of fill bytes.
+		if marker == 0xff {
+			i++
+
+			continue
+		}
+
+		// Start of scan, the entropy-coded data follows, or end of image.
+		if marker == 0xda || marker == 0xd9 {
+			return i
+		}
+
+		if i+4 > len(data) {
+			return -1
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+
+		if length < 2 || i+2+length > len(data) {
+			return -1
+		}
+
+		if !visit(marker, data[i:i+2+length]) {
+			return i
+		}
+
+		i += 2 + length
+	}
+
+	return -1
+}
+
+// scanEnd returns where the marker following the scan at start is, or -1 when
+// the data ends first. Within the entropy-coded data a 0xff byte is followed by
+// 0x00, a restart marker or fill, anything else is the next marker.
+func scanEnd(data []byte, start int) int {
+	if start+4 > len(data) {
+		return -1
+	}
+
+	i := start + 2 + int(binary.BigEndian.Uint16(data[start+2:start+4]))
+
+	for ; i+1 < len(data); i++ {
+		if data[i] != 0xff {
+			continue
+		}
+
+		marker := data[i+1]
+
+		if marker != 0x00 && marker != 0xff && (marker < 0xd0 || marker > 0xd7) {
+			return i
+		}
+
+		if marker == 0x00 {
+			i++
+		}
+	}
+
+	return -1
+}
+
+// strippedPNGChunks hold EXIF, text and the time the file was last modified.
+var strippedPNGChunks = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+
+func stripPNG(data []byte) ([]byte, error) {
+	stripped := append([]byte{}, data[:8]...)
+
+	if ok := walkPNG(data, func(kind string, chunk []byte) bool {
+		if !strippedPNGChunks[kind] {
+			stripped = append(stripped, chunk...)
+		}
+
+		return true
+	}); !ok {
+		return nil, domain.ErrImageUnsupported
+	}
+
+	return stripped, nil
+}
+
+// walkPNG calls visit with each chunk, length and checksum included, until visit
+// returns false. It reports whether the chunks were well-formed.
+func walkPNG(data []byte, visit func(kind string, chunk []byte) bool) bool {
+	i := 8
+
+	for i < len(data) {
+		if i+12 > len(data) {
+			return false
+		}
+
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+
+		if length > len(data)-i-12 {
+			return false
+		}
+
+		if !visit(string(data[i+4:i+8]), data[i:i+12+length]) {
+			return true
+		}
+
+		i += 12 + length
+	}
+
+	return true
+}
+
+func stripWebP(data []byte) ([]byte, error) {
+	stripped := append([]byte{}, data[:12]...)
+
+	if ok := walkWebP(data, func(kind string, chunk []byte) bool {
+		switch kind {
+		case "EXIF", "XMP ":
+			return true
+		case "VP8X":
+			chunk = append([]byte{}, chunk...)
+
+			// Clear the flags announcing EXIF and XMP chunks.
+			if len(chunk) > 8 {
+				chunk[8] &^= 0x08 | 0x04
+			}
+		}
+
+		stripped = append(stripped, chunk...)
+
+		return true
+	}); !ok {
+		return nil, domain.ErrImageUnsupported
+	}
+
+	binary.LittleEndian.PutUint32(stripped[4:8], uint32(len(stripped)-8))
+
+	return stripped, nil
+}
+
+// walkWebP calls visit with each chunk of a RIFF WebP file, header and padding
+// included, until visit returns false. It reports whether the chunks were
+// well-formed.
+func walkWebP(data []byte, visit func(kind string, chunk []byte) bool) bool {
+	i := 12
+
+	for i < len(data) {
+		if i+8 > len(data) {
+			return false
+		}
+
+		size := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+
+		if size > len(data)-i-8 {
+			return false
+		}
+
+		end := i + 8 + size
+
+		// Chunks are padded to an even size, except at times the last one.
+		if size%2 == 1 && end < len(data) {
+			end++
+		}
+
+		if !visit(string(data[i:i+4]), data[i:end]) {
+			return true
+		}
+
+		i = end
+	}
+
+	return true
+}
+
+// keptGIFApplications are the application extensions a GIF still needs: the
+// loop count of animations and the color profile. The others, XMP among them,
+// are dropped along with comments.
+var keptGIFApplications = map[string]bool{"NETSCAPE2.0": true, "ANIMEXTS1.0": true, "ICCRGBG1012": true}
+
+func stripGIF(data []byte) ([]byte, error) {
+	// The header and the logical screen descriptor, followed by the global
+	// color table when its flag is set.
+	i := 13
+
+	if len(data) < i {
+		return nil, domain.ErrImageUnsupported
+	}
+
+	if data[10]&0x80 != 0 {
+		i += 3 << (data[10]&0x07 + 1)
+	}
+
+	if i > len(data) {
+		return nil, domain.ErrImageUnsupported
+	}
+
+	stripped := append([]byte{}, data[:i]...)
+
+	for i < len(data) {
+		start := i
+
+		switch data[i] {
+		case 0x3b:
+			// The trailer, anything after it is dropped.
+			return append(stripped, 0x3b), nil
+		case 0x21:
+			if i+2 > len(data) {
+				return nil, domain.ErrImageUnsupported
+			}
+
+			label := data[i+1]
+			end := gifSubBlocksEnd(data, i+2)
+
+			if end < 0 {
+				return nil, domain.ErrImageUnsupported
+			}
+
+			comment := label == 0xfe
+			application := label == 0xff && (i+14 > end || !keptGIFApplications[string(data[i+3:i+14])])
+
+			if !comment && !application {
+				stripped = append(stripped, data[start:end]...)
+			}
+
+			i = end
+		case 0x2c:
+			// The image descriptor, its local color table and the LZW code size.
+			if i+10 > len(data) {
+				return nil, domain.ErrImageUnsupported
+			}
+
+			i += 10
+
+			if data[i-1]&0x80 != 0 {
+				i += 3 << (data[i-1]&0x07 + 1)
+			}
+
+			end := gifSubBlocksEnd(data, i+1)
+
+			if end < 0 {
+				return nil, domain.ErrImageUnsupported
+			}
+
+			stripped = append(stripped, data[start:end]...)
+			i = end
+		default:
+			return nil, domain.ErrImageUnsupported
+		}
+	}
+
+	// A file cut off before its trailer keeps what there is of it.
+	return stripped, nil
+}
+
+// gifSubBlocksEnd returns where the data sub-blocks starting at start end, their
+// terminator included, or -1 when the data ends first.
+func gifSubBlocksEnd(data []byte, start int) int {
+	i := start
+
+	for i < len(data) {
+		size := int(data[i])
+
+		i++
+
+		if size == 0 {
+			return i
+		}
+
+		i += size
+	}
+
+	return -1
+}
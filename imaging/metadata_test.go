@@ -0,0 +1,297 @@
+package imaging_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"mygram-api/imaging"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tiffEntry struct {
+	tag   uint16
+	kind  uint16
+	count uint32
+	value []byte
+}
+
+// appendIFD writes entries as a little endian TIFF directory at the end of block,
+// followed by the values that don't fit in an entry, and tells where it starts.
+func appendIFD(block []byte, entries []tiffEntry) ([]byte, uint32) {
+	offset := uint32(len(block))
+	overflow := offset + 2 + uint32(len(entries))*12 + 4
+	extra := []byte{}
+
+	block = binary.LittleEndian.AppendUint16(block, uint16(len(entries)))
+
+	for _, entry := range entries {
+		block = binary.LittleEndian.AppendUint16(block, entry.tag)
+		block = binary.LittleEndian.AppendUint16(block, entry.kind)
+		block = binary.LittleEndian.AppendUint32(block, entry.count)
+
+		if len(entry.value) <= 4 {
+			block = append(block, append(entry.value, make([]byte, 4-len(entry.value))...)...)
+		} else {
+			block = binary.LittleEndian.AppendUint32(block, overflow+uint32(len(extra)))
+			extra = append(extra, entry.value...)
+		}
+	}
+
+	block = binary.LittleEndian.AppendUint32(block, 0)
+
+	return append(block, extra...), offset
+}
+
+func ascii(value string) tiffEntry {
+	return tiffEntry{kind: 2, count: uint32(len(value) + 1), value: append([]byte(value), 0)}
+}
+
+func rationals(values ...uint32) tiffEntry {
+	value := []byte{}
+
+	for _, v := range values {
+		value = binary.LittleEndian.AppendUint32(value, v)
+		value = binary.LittleEndian.AppendUint32(value, 1)
+	}
+
+	return tiffEntry{kind: 5, count: uint32(len(values)), value: value}
+}
+
+func long(value uint32) tiffEntry {
+	return tiffEntry{kind: 4, count: 1, value: binary.LittleEndian.AppendUint32(nil, value)}
+}
+
+func tagged(tag uint16, entry tiffEntry) tiffEntry {
+	entry.tag = tag
+
+	return entry
+}
+
+// exifJPEG encodes img as a JPEG carrying the EXIF a phone would write,
+// location included.
+func exifJPEG(img image.Image, orientation uint16) []byte {
+	block := []byte("II*\x00\x00\x00\x00\x00")
+
+	block, exifIFD := appendIFD(block, []tiffEntry{
+		tagged(0x9003, ascii("2022:10:01 12:30:00")),
+		tagged(0xa434, ascii("iPhone 13 back camera 5.1mm f/1.6")),
+	})
+	block, gpsIFD := appendIFD(block, []tiffEntry{
+		tagged(0x0001, ascii("N")),
+		tagged(0x0002, rationals(52, 30, 0)),
+		tagged(0x0003, ascii("W")),
+		tagged(0x0004, rationals(13, 24, 0)),
+	})
+	block, ifd0 := appendIFD(block, []tiffEntry{
+		tagged(0x010f, ascii("Apple")),
+		tagged(0x0110, ascii("iPhone 13")),
+		{tag: 0x0112, kind: 3, count: 1, value: binary.LittleEndian.AppendUint16(nil, orientation)},
+		tagged(0x8769, long(exifIFD)),
+		tagged(0x8825, long(gpsIFD)),
+	})
+
+	binary.LittleEndian.PutUint32(block[4:8], ifd0)
+
+	encoded := bytes.Buffer{}
+
+	jpeg.Encode(&encoded, img, nil)
+
+	app1 := append([]byte{0xff, 0xe1, 0, 0}, "Exif\x00\x00"...)
+	app1 = append(app1, block...)
+
+	binary.BigEndian.PutUint16(app1[2:4], uint16(len(app1)-2))
+
+	data := append([]byte{}, encoded.Bytes()[:2]...)
+	data = append(data, app1...)
+
+	return append(data, encoded.Bytes()[2:]...)
+}
+
+func TestExtract(t *testing.T) {
+	t.Run("extract the metadata of a photo", func(t *testing.T) {
+		metadata := imaging.Extract(exifJPEG(image.NewGray(image.Rect(0, 0, 8, 8)), 6))
+
+		assert.Equal(t, "Apple", metadata.CameraMake)
+		assert.Equal(t, "iPhone 13", metadata.CameraModel)
+		assert.Equal(t, "iPhone 13 back camera 5.1mm f/1.6", metadata.LensModel)
+		assert.Equal(t, 6, metadata.Orientation)
+
+		if assert.NotNil(t, metadata.TakenAt) {
+			assert.Equal(t, time.Date(2022, 10, 1, 12, 30, 0, 0, time.Local), *metadata.TakenAt)
+		}
+
+		if assert.NotNil(t, metadata.Latitude) && assert.NotNil(t, metadata.Longitude) {
+			assert.InDelta(t, 52.5, *metadata.Latitude, 1e-9)
+			assert.InDelta(t, -13.4, *metadata.Longitude, 1e-9)
+		}
+	})
+
+	t.Run("extract the metadata of a photo without any", func(t *testing.T) {
+		data := bytes.Buffer{}
+
+		jpeg.Encode(&data, image.NewGray(image.Rect(0, 0, 8, 8)), nil)
+
+		assert.Equal(t, imaging.Metadata{}, imaging.Extract(data.Bytes()))
+	})
+
+	t.Run("extract the metadata of something else", func(t *testing.T) {
+		assert.Equal(t, imaging.Metadata{}, imaging.Extract([]byte("%PDF-1.7")))
+	})
+}
+
+func TestStrip(t *testing.T) {
+	t.Run("strip a jpeg", func(t *testing.T) {
+		data := exifJPEG(image.NewGray(image.Rect(0, 0, 8, 8)), 1)
+
+		// Phones append further images after the end of the first one.
+		data = append(data, exifJPEG(image.NewGray(image.Rect(0, 0, 2, 2)), 1)...)
+
+		stripped, err := imaging.Strip(data)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, string(stripped), "Exif")
+		assert.NotContains(t, string(stripped), "Apple")
+		assert.Equal(t, imaging.Metadata{}, imaging.Extract(stripped))
+
+		img, err := imaging.Decode(stripped)
+
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 8, 8), img.Bounds())
+	})
+
+	t.Run("strip a png", func(t *testing.T) {
+		encoded := bytes.Buffer{}
+
+		png.Encode(&encoded, image.NewGray(image.Rect(0, 0, 8, 8)))
+
+		text := []byte("tEXtComment\x00taken at home")
+		chunk := binary.BigEndian.AppendUint32(nil, uint32(len(text)-4))
+		chunk = append(chunk, text...)
+		chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(text))
+
+		// The text chunk goes right after the 33 bytes of signature and header.
+		data := append(append(append([]byte{}, encoded.Bytes()[:33]...), chunk...), encoded.Bytes()[33:]...)
+
+		stripped, err := imaging.Strip(data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, encoded.Bytes(), stripped)
+	})
+
+	t.Run("strip a webp", func(t *testing.T) {
+		chunk := func(kind string, payload string) []byte {
+			return append(binary.LittleEndian.AppendUint32([]byte(kind), uint32(len(payload))), payload...)
+		}
+
+		body := append([]byte("WEBP"), chunk("VP8X", "\x08\x00\x00\x00\x07\x00\x00\x07\x00\x00")...)
+		body = append(body, chunk("EXIF", "II*\x00secret")...)
+		body = append(body, chunk("VP8 ", "pixels")...)
+		data := append(binary.LittleEndian.AppendUint32([]byte("RIFF"), uint32(len(body))), body...)
+
+		stripped, err := imaging.Strip(data)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, string(stripped), "secret")
+		assert.Equal(t, uint32(len(stripped)-8), binary.LittleEndian.Uint32(stripped[4:8]))
+		assert.Equal(t, byte(0), stripped[20])
+		assert.Contains(t, string(stripped), "VP8 \x06\x00\x00\x00pixels")
+	})
+
+	t.Run("strip a jpeg with metadata after the scan", func(t *testing.T) {
+		encoded := bytes.Buffer{}
+
+		jpeg.Encode(&encoded, image.NewGray(image.Rect(0, 0, 8, 8)), nil)
+
+		comment := append([]byte{0xff, 0xfe, 0, 15}, "taken at home"...)
+		xmp := append([]byte{0xff, 0xe1, 0, 17}, "<x:xmpmeta>home"...)
+		end := encoded.Len() - 2
+		data := append(append(append([]byte{}, encoded.Bytes()[:end]...), comment...), xmp...)
+		data = append(data, 0xff, 0xd9)
+
+		stripped, err := imaging.Strip(data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, encoded.Bytes(), stripped)
+	})
+
+	t.Run("strip a gif", func(t *testing.T) {
+		palette := color.Palette{color.Black, color.White}
+		encoded := bytes.Buffer{}
+
+		gif.EncodeAll(&encoded, &gif.GIF{
+			Image: []*image.Paletted{image.NewPaletted(image.Rect(0, 0, 8, 8), palette), image.NewPaletted(image.Rect(0, 0, 8, 8), palette)},
+			Delay: []int{10, 10},
+		})
+
+		// The extensions go right after the 13 bytes of header and screen
+		// descriptor, ahead of the looping one the encoder writes.
+		comment := append(append([]byte{0x21, 0xfe, 13}, "taken at home"...), 0)
+		xmp := append(append([]byte{0x21, 0xff, 11}, "XMP DataXMP"...), append([]byte{4}, "home\x00"...)...)
+		data := append(append(append([]byte{}, encoded.Bytes()[:13]...), comment...), xmp...)
+		data = append(append(data, encoded.Bytes()[13:]...), "trailing secret"...)
+
+		stripped, err := imaging.Strip(data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, encoded.Bytes(), stripped)
+
+		decoded, err := gif.DecodeAll(bytes.NewReader(stripped))
+
+		assert.NoError(t, err)
+		assert.Len(t, decoded.Image, 2)
+	})
+
+	t.Run("strip a truncated jpeg", func(t *testing.T) {
+		_, err := imaging.Strip(exifJPEG(image.NewGray(image.Rect(0, 0, 8, 8)), 1)[:30])
+
+		assert.Error(t, err)
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	normalized, err := imaging.Normalize(exifJPEG(image.NewGray(image.Rect(0, 0, 40, 20)), 6), 6)
+
+	assert.NoError(t, err)
+	assert.Equal(t, imaging.Metadata{}, imaging.Extract(normalized))
+
+	info, err := imaging.Inspect(normalized)
+
+	assert.NoError(t, err)
+	assert.Equal(t, imaging.Info{Format: "jpeg", Width: 20, Height: 40}, info)
+}
+
+func TestOrient(t *testing.T) {
+	red, blue := color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+
+	img.Set(0, 0, red)
+	img.Set(1, 0, blue)
+
+	for _, tc := range []struct {
+		orientation int
+		bounds      image.Rectangle
+		first       color.NRGBA
+	}{
+		{1, image.Rect(0, 0, 2, 1), red},
+		{2, image.Rect(0, 0, 2, 1), blue},
+		{3, image.Rect(0, 0, 2, 1), blue},
+		{4, image.Rect(0, 0, 2, 1), red},
+		{5, image.Rect(0, 0, 1, 2), red},
+		{6, image.Rect(0, 0, 1, 2), red},
+		{7, image.Rect(0, 0, 1, 2), blue},
+		{8, image.Rect(0, 0, 1, 2), blue},
+	} {
+		oriented := imaging.Orient(img, tc.orientation)
+
+		assert.Equal(t, tc.bounds, oriented.Bounds(), "orientation %d", tc.orientation)
+		assert.Equal(t, tc.first, color.NRGBAModel.Convert(oriented.At(0, 0)), "orientation %d", tc.orientation)
+	}
+}
@@ -0,0 +1,217 @@
+package delivery
+
+import (
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"mygram-api/like/utils"
+	"mygram-api/middleware"
+	"mygram-api/pagination"
+	"mygram-api/policy"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type likeHandler struct {
+	likeUseCase domain.LikeUseCase
+}
+
+func NewLikeHandler(routers *gin.Engine, likeUseCase domain.LikeUseCase) {
+	handler := &likeHandler{likeUseCase}
+
+	routers.GET("/photos/:photoId/likes", middleware.Authentication(domain.ScopePhotosRead), handler.FetchByPhoto)
+	routers.POST("/photos/:photoId/like", middleware.Authentication(domain.ScopePhotosWrite), middleware.VerifiedEmail(), handler.LikePhoto)
+	routers.DELETE("/photos/:photoId/like", middleware.Authentication(domain.ScopePhotosWrite), handler.UnlikePhoto)
+	routers.POST("/comments/:commentId/like", middleware.Authentication(domain.ScopeCommentsWrite), middleware.VerifiedEmail(), handler.LikeComment)
+	routers.DELETE("/comments/:commentId/like", middleware.Authentication(domain.ScopeCommentsWrite), handler.UnlikeComment)
+}
+
+// FetchByPhoto godoc
+// @Summary    	Fetch the likes of a photo
+// @Description	Get a page of the users who like a photo, most recent first, along with how many likes it has in total
+// @Tags        likes
+// @Accept      json
+// @Produce     json
+// @Param       id				path			string	true	"Photo ID"
+// @Param       cursor		query			string	false	"Cursor of the page, the next_cursor of the previous one"
+// @Param       limit			query			int			false	"Items per page, 20 by default and at most 100"
+// @Param       sort			query			string	false	"newest (default) or oldest first"
+// @Success     200	{object}	utils.ResponseDataPhotoLikes
+// @Failure     400	{object}	utils.ResponseMessage
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Failure     500	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /photos/{id}/likes	[get]
+func (handler *likeHandler) FetchByPhoto(ctx *gin.Context) {
+	var likes []domain.PhotoLike
+
+	photoID := ctx.Param("photoId")
+
+	query, err := pagination.Parse(ctx.Request.URL.Query())
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	next, total, err := handler.likeUseCase.FetchByPhoto(ctx.Request.Context(), &likes, photoID, query)
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourcePhoto, photoID)
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	photoLikes := []utils.PhotoLike{}
+
+	for _, like := range likes {
+		photoLike := utils.PhotoLike{CreatedAt: like.CreatedAt}
+
+		if like.User != nil {
+			photoLike.User = &utils.Owner{
+				ID:              like.User.ID,
+				Username:        like.User.Username,
+				DisplayName:     like.User.DisplayName,
+				ProfileImageUrl: like.User.ProfileImageUrl,
+			}
+		}
+
+		photoLikes = append(photoLikes, photoLike)
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponsePage{
+		Status: "success",
+		Data: utils.PhotoLikes{
+			Total: total,
+			Likes: photoLikes,
+		},
+		NextCursor: next,
+	})
+}
+
+// LikePhoto godoc
+// @Summary    	Like a photo
+// @Description	Like a photo as the authenticated user. Liking a photo twice counts once
+// @Tags        likes
+// @Produce     json
+// @Param       id	path			string	true	"Photo ID"
+// @Success     200	{object}	utils.ResponseDataLiked
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Failure     500	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /photos/{id}/like	[post]
+func (handler *likeHandler) LikePhoto(ctx *gin.Context) {
+	handler.setLiked(ctx, domain.LikeTargetPhoto, policy.ResourcePhoto, ctx.Param("photoId"), true)
+}
+
+// UnlikePhoto godoc
+// @Summary    	Unlike a photo
+// @Description	Take back the like of the authenticated user on a photo, if there was one
+// @Tags        likes
+// @Produce     json
+// @Param       id	path			string	true	"Photo ID"
+// @Success     200	{object}	utils.ResponseDataLiked
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Failure     500	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /photos/{id}/like	[delete]
+func (handler *likeHandler) UnlikePhoto(ctx *gin.Context) {
+	handler.setLiked(ctx, domain.LikeTargetPhoto, policy.ResourcePhoto, ctx.Param("photoId"), false)
+}
+
+// LikeComment godoc
+// @Summary    	Like a comment
+// @Description	Like a comment as the authenticated user. Liking a comment twice counts once
+// @Tags        likes
+// @Produce     json
+// @Param       id	path			string	true	"Comment ID"
+// @Success     200	{object}	utils.ResponseDataLiked
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Failure     500	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /comments/{id}/like	[post]
+func (handler *likeHandler) LikeComment(ctx *gin.Context) {
+	handler.setLiked(ctx, domain.LikeTargetComment, policy.ResourceComment, ctx.Param("commentId"), true)
+}
+
+// UnlikeComment godoc
+// @Summary    	Unlike a comment
+// @Description	Take back the like of the authenticated user on a comment, if there was one
+// @Tags        likes
+// @Produce     json
+// @Param       id	path			string	true	"Comment ID"
+// @Success     200	{object}	utils.ResponseDataLiked
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Failure     500	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /comments/{id}/like	[delete]
+func (handler *likeHandler) UnlikeComment(ctx *gin.Context) {
+	handler.setLiked(ctx, domain.LikeTargetComment, policy.ResourceComment, ctx.Param("commentId"), false)
+}
+
+// setLiked likes or unlikes the target with id for the caller and answers with
+// where that left it.
+func (handler *likeHandler) setLiked(ctx *gin.Context, target domain.LikeTarget, resource policy.Resource, id string, liked bool) {
+	var (
+		count int64
+		err   error
+	)
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if liked {
+		count, err = handler.likeUseCase.Like(ctx.Request.Context(), target, id, userID)
+	} else {
+		count, err = handler.likeUseCase.Unlike(ctx.Request.Context(), target, id, userID)
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, resource, id)
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.Liked{
+			Liked:     liked,
+			LikeCount: count,
+		},
+	})
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"mygram-api/pagination"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeTable tells where the likes of a kind of target are kept: the table of
+// the targets, which holds their like_count, the table of their likes and the
+// column of that table pointing at the target.
+type likeTable struct {
+	targets string
+	likes   string
+	column  string
+}
+
+var likeTables = map[domain.LikeTarget]likeTable{
+	domain.LikeTargetPhoto:   {"photos", "photo_likes", "photo_id"},
+	domain.LikeTargetComment: {"comments", "comment_likes", "comment_id"},
+}
+
+type likeRepository struct {
+	db *gorm.DB
+}
+
+func NewLikeRepository(db *gorm.DB) *likeRepository {
+	return &likeRepository{db}
+}
+
+// Like records that the user with userID likes the target with targetID and
+// adds one to its like_count, unless the like was already there.
+func (likeRepository *likeRepository) Like(ctx context.Context, target domain.LikeTarget, targetID string, userID string) (count int64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	table := likeTables[target]
+
+	err = likeRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(table.targets).Select("like_count").Where("id = ?", targetID).Take(&count).Error; err != nil {
+			return err
+		}
+
+		ID, _ := gonanoid.New(16)
+
+		result := tx.Table(table.likes).Clauses(clause.OnConflict{DoNothing: true}).Create(map[string]interface{}{
+			"id":         fmt.Sprintf("like-%s", ID),
+			table.column: targetID,
+			"user_id":    userID,
+			"created_at": time.Now(),
+		})
+
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		return changeLikeCount(tx, table, targetID, "+", &count)
+	})
+
+	return count, err
+}
+
+// Unlike takes back the like of the user with userID on the target with
+// targetID and one off its like_count, if the like was there.
+func (likeRepository *likeRepository) Unlike(ctx context.Context, target domain.LikeTarget, targetID string, userID string) (count int64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	table := likeTables[target]
+
+	err = likeRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(table.targets).Select("like_count").Where("id = ?", targetID).Take(&count).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ? AND user_id = ?", table.likes, table.column), targetID, userID)
+
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		return changeLikeCount(tx, table, targetID, "-", &count)
+	})
+
+	return count, err
+}
+
+// changeLikeCount adds or takes one off the like_count of the target with
+// targetID and reads it back into count.
+func changeLikeCount(tx *gorm.DB, table likeTable, targetID string, operator string, count *int64) error {
+	if err := tx.Table(table.targets).Where("id = ?", targetID).UpdateColumn("like_count", gorm.Expr("like_count "+operator+" 1")).Error; err != nil {
+		return err
+	}
+
+	return tx.Table(table.targets).Select("like_count").Where("id = ?", targetID).Take(count).Error
+}
+
+// FetchByPhoto loads a page of the likes on the photo with photoID, each with a
+// summary of the user who left it.
+func (likeRepository *likeRepository) FetchByPhoto(ctx context.Context, likes *[]domain.PhotoLike, photoID string, query pagination.Query) (next string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = likeRepository.db.WithContext(ctx).Where("photo_likes.photo_id = ?", photoID).Scopes(query.Scope("photo_likes")).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "display_name", "profile_image_url")
+	}).Find(&likes).Error; err != nil {
+		return "", err
+	}
+
+	return pagination.Trim(query, likes, func(like domain.PhotoLike) pagination.Cursor {
+		return pagination.NewCursor(like.CreatedAt, like.ID)
+	}), nil
+}
+
+// Count reads the like_count of the target with targetID.
+func (likeRepository *likeRepository) Count(ctx context.Context, target domain.LikeTarget, targetID string) (count int64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = likeRepository.db.WithContext(ctx).Table(likeTables[target].targets).Select("like_count").Where("id = ?", targetID).Take(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return
+}
+
+// LikedBy tells which of the targets with targetIDs the user with userID likes.
+func (likeRepository *likeRepository) LikedBy(ctx context.Context, target domain.LikeTarget, userID string, targetIDs []string) (liked map[string]bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	var ids []string
+
+	table := likeTables[target]
+
+	if err = likeRepository.db.WithContext(ctx).Table(table.likes).Where(table.column+" IN ? AND user_id = ?", targetIDs, userID).Pluck(table.column, &ids).Error; err != nil {
+		return nil, err
+	}
+
+	liked = make(map[string]bool, len(ids))
+
+	for _, id := range ids {
+		liked[id] = true
+	}
+
+	return liked, nil
+}
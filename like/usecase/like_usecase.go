@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"mygram-api/domain"
+	"mygram-api/pagination"
+)
+
+type likeUseCase struct {
+	likeRepository domain.LikeRepository
+}
+
+func NewLikeUseCase(likeRepository domain.LikeRepository) *likeUseCase {
+	return &likeUseCase{likeRepository}
+}
+
+func (likeUseCase *likeUseCase) Like(ctx context.Context, target domain.LikeTarget, targetID string, userID string) (count int64, err error) {
+	if count, err = likeUseCase.likeRepository.Like(ctx, target, targetID, userID); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (likeUseCase *likeUseCase) Unlike(ctx context.Context, target domain.LikeTarget, targetID string, userID string) (count int64, err error) {
+	if count, err = likeUseCase.likeRepository.Unlike(ctx, target, targetID, userID); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FetchByPhoto returns a page of the likes on the photo with photoID along with
+// how many it has in total. A photo that doesn't exist is gorm.ErrRecordNotFound.
+func (likeUseCase *likeUseCase) FetchByPhoto(ctx context.Context, likes *[]domain.PhotoLike, photoID string, query pagination.Query) (next string, total int64, err error) {
+	if total, err = likeUseCase.likeRepository.Count(ctx, domain.LikeTargetPhoto, photoID); err != nil {
+		return "", 0, err
+	}
+
+	if next, err = likeUseCase.likeRepository.FetchByPhoto(ctx, likes, photoID, query); err != nil {
+		return "", 0, err
+	}
+
+	return next, total, nil
+}
+
+// LikedBy tells which of the targets with targetIDs the user with userID likes.
+// Anonymous callers and empty lists need no lookup.
+func (likeUseCase *likeUseCase) LikedBy(ctx context.Context, target domain.LikeTarget, userID string, targetIDs []string) (liked map[string]bool, err error) {
+	if userID == "" || len(targetIDs) == 0 {
+		return map[string]bool{}, nil
+	}
+
+	if liked, err = likeUseCase.likeRepository.LikedBy(ctx, target, userID, targetIDs); err != nil {
+		return nil, err
+	}
+
+	return liked, nil
+}
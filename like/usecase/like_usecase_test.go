@@ -0,0 +1,131 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/domain/mocks"
+	"mygram-api/pagination"
+	"testing"
+	"time"
+
+	likeUseCase "mygram-api/like/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestLike(t *testing.T) {
+	mockLikeRepository := new(mocks.LikeRepository)
+	likeUseCase := likeUseCase.NewLikeUseCase(mockLikeRepository)
+
+	t.Run("like a photo correctly", func(t *testing.T) {
+		mockLikeRepository.On("Like", mock.Anything, domain.LikeTargetPhoto, "photo-123", "user-123").Return(int64(1), nil).Once()
+
+		count, err := likeUseCase.Like(context.Background(), domain.LikeTargetPhoto, "photo-123", "user-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		mockLikeRepository.AssertExpectations(t)
+	})
+
+	t.Run("like a comment that doesn't exist", func(t *testing.T) {
+		mockLikeRepository.On("Like", mock.Anything, domain.LikeTargetComment, "comment-404", "user-123").Return(int64(0), gorm.ErrRecordNotFound).Once()
+
+		count, err := likeUseCase.Like(context.Background(), domain.LikeTargetComment, "comment-404", "user-123")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Zero(t, count)
+		mockLikeRepository.AssertExpectations(t)
+	})
+}
+
+func TestUnlike(t *testing.T) {
+	mockLikeRepository := new(mocks.LikeRepository)
+	likeUseCase := likeUseCase.NewLikeUseCase(mockLikeRepository)
+
+	t.Run("unlike a photo correctly", func(t *testing.T) {
+		mockLikeRepository.On("Unlike", mock.Anything, domain.LikeTargetPhoto, "photo-123", "user-123").Return(int64(0), nil).Once()
+
+		count, err := likeUseCase.Unlike(context.Background(), domain.LikeTargetPhoto, "photo-123", "user-123")
+
+		assert.NoError(t, err)
+		assert.Zero(t, count)
+		mockLikeRepository.AssertExpectations(t)
+	})
+
+	t.Run("unlike with a failing repository", func(t *testing.T) {
+		mockLikeRepository.On("Unlike", mock.Anything, domain.LikeTargetComment, "comment-123", "user-123").Return(int64(0), errors.New("connection refused")).Once()
+
+		_, err := likeUseCase.Unlike(context.Background(), domain.LikeTargetComment, "comment-123", "user-123")
+
+		assert.Error(t, err)
+		mockLikeRepository.AssertExpectations(t)
+	})
+}
+
+func TestFetchByPhoto(t *testing.T) {
+	now := time.Now()
+	mockLikes := []domain.PhotoLike{
+		{ID: "like-123", PhotoID: "photo-123", UserID: "user-123", CreatedAt: &now, User: &domain.User{ID: "user-123", Username: "johndoe"}},
+	}
+	query := pagination.Query{Limit: 1}
+
+	mockLikeRepository := new(mocks.LikeRepository)
+	likeUseCase := likeUseCase.NewLikeUseCase(mockLikeRepository)
+
+	t.Run("fetch the likes of a photo correctly", func(t *testing.T) {
+		var likes []domain.PhotoLike
+
+		mockLikeRepository.On("Count", mock.Anything, domain.LikeTargetPhoto, "photo-123").Return(int64(2), nil).Once()
+		mockLikeRepository.On("FetchByPhoto", mock.Anything, mock.AnythingOfType("*[]domain.PhotoLike"), "photo-123", query).Run(func(args mock.Arguments) {
+			*args.Get(1).(*[]domain.PhotoLike) = mockLikes
+		}).Return("next-cursor", nil).Once()
+
+		next, total, err := likeUseCase.FetchByPhoto(context.Background(), &likes, "photo-123", query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", next)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, mockLikes, likes)
+		mockLikeRepository.AssertExpectations(t)
+	})
+
+	t.Run("fetch the likes of a photo that doesn't exist", func(t *testing.T) {
+		var likes []domain.PhotoLike
+
+		mockLikeRepository.On("Count", mock.Anything, domain.LikeTargetPhoto, "photo-404").Return(int64(0), gorm.ErrRecordNotFound).Once()
+
+		_, _, err := likeUseCase.FetchByPhoto(context.Background(), &likes, "photo-404", query)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockLikeRepository.AssertExpectations(t)
+	})
+}
+
+func TestLikedBy(t *testing.T) {
+	mockLikeRepository := new(mocks.LikeRepository)
+	likeUseCase := likeUseCase.NewLikeUseCase(mockLikeRepository)
+
+	t.Run("tell which photos a user likes", func(t *testing.T) {
+		photoIDs := []string{"photo-123", "photo-234"}
+
+		mockLikeRepository.On("LikedBy", mock.Anything, domain.LikeTargetPhoto, "user-123", photoIDs).Return(map[string]bool{"photo-234": true}, nil).Once()
+
+		liked, err := likeUseCase.LikedBy(context.Background(), domain.LikeTargetPhoto, "user-123", photoIDs)
+
+		assert.NoError(t, err)
+		assert.False(t, liked["photo-123"])
+		assert.True(t, liked["photo-234"])
+		mockLikeRepository.AssertExpectations(t)
+	})
+
+	t.Run("tell which of no comments a user likes", func(t *testing.T) {
+		liked, err := likeUseCase.LikedBy(context.Background(), domain.LikeTargetComment, "user-123", []string{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, liked)
+		mockLikeRepository.AssertNotCalled(t, "LikedBy", mock.Anything, domain.LikeTargetComment, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,41 @@
+package utils
+
+import "time"
+
+type Owner struct {
+	ID              string `json:"id" example:"here is the generated user id"`
+	Username        string `json:"username" example:"johndoe"`
+	DisplayName     string `json:"display_name" example:"John Doe"`
+	ProfileImageUrl string `json:"profile_image_url" example:"https://www.example.com/image.jpg"`
+}
+
+type Liked struct {
+	Liked     bool  `json:"liked" example:"true"`
+	LikeCount int64 `json:"like_count" example:"42"`
+}
+
+type ResponseDataLiked struct {
+	Status string `json:"status" example:"success"`
+	Data   Liked  `json:"data"`
+}
+
+type PhotoLike struct {
+	User      *Owner     `json:"user"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type PhotoLikes struct {
+	Total int64       `json:"total" example:"42"`
+	Likes []PhotoLike `json:"likes"`
+}
+
+type ResponseDataPhotoLikes struct {
+	Status     string     `json:"status" example:"success"`
+	Data       PhotoLikes `json:"data"`
+	NextCursor string     `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6Imxpa2UtMTIzIn0"`
+}
+
+type ResponseMessage struct {
+	Status string `json:"status" example:"fail"`
+	Data   string `json:"data" example:"the error explained here"`
+}
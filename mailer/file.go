@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"os"
+	"path/filepath"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer writes every mail as an .eml file into dir instead of sending it,
+// which is handy for local development without an SMTP server.
+func NewFileMailer(dir string, from string) *fileMailer {
+	return &fileMailer{dir, from}
+}
+
+func (fileMailer *fileMailer) Send(ctx context.Context, mail domain.Mail) (err error) {
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(fileMailer.dir, 0o755); err != nil {
+		return err
+	}
+
+	ID, _ := gonanoid.New(8)
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405"), ID)
+
+	return os.WriteFile(filepath.Join(fileMailer.dir, name), format(fileMailer.from, mail), 0o600)
+}
@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"log"
+	"mygram-api/domain"
+	"net"
+	"os"
+)
+
+// NewFromEnv sends through SMTP_HOST when it is set and otherwise drops every
+// mail into MAIL_DIR (default "mail") so nothing is lost during development.
+func NewFromEnv() domain.Mailer {
+	from := os.Getenv("MAIL_FROM")
+
+	if from == "" {
+		from = "MyGram <no-reply@mygram.local>"
+	}
+
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := os.Getenv("SMTP_PORT")
+
+		if port == "" {
+			port = "587"
+		}
+
+		return NewSMTPMailer(net.JoinHostPort(host, port), os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+	}
+
+	dir := os.Getenv("MAIL_DIR")
+
+	if dir == "" {
+		dir = "mail"
+	}
+
+	log.Printf("SMTP_HOST is not set, outgoing mail is written to %s", dir)
+
+	return NewFileMailer(dir, from)
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"context"
+	"mygram-api/domain"
+	"sync"
+)
+
+// MemoryMailer keeps every mail it is asked to send, for use in tests.
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []domain.Mail
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (memoryMailer *MemoryMailer) Send(ctx context.Context, mail domain.Mail) (err error) {
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	memoryMailer.mu.Lock()
+	defer memoryMailer.mu.Unlock()
+
+	memoryMailer.sent = append(memoryMailer.sent, mail)
+
+	return
+}
+
+func (memoryMailer *MemoryMailer) Sent() []domain.Mail {
+	memoryMailer.mu.Lock()
+	defer memoryMailer.mu.Unlock()
+
+	return append([]domain.Mail(nil), memoryMailer.sent...)
+}
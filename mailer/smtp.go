@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer sends plain-text mail through addr ("host:port"), authenticating
+// with PLAIN auth when username is not empty.
+func NewSMTPMailer(addr string, username string, password string, from string) *smtpMailer {
+	var auth smtp.Auth
+
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &smtpMailer{addr, auth, from}
+}
+
+func (smtpMailer *smtpMailer) Send(ctx context.Context, mail domain.Mail) (err error) {
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	return smtp.SendMail(smtpMailer.addr, smtpMailer.auth, smtpMailer.from, mail.To, format(smtpMailer.from, mail))
+}
+
+func format(from string, mail domain.Mail) []byte {
+	var message strings.Builder
+
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(mail.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mail.Subject)
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+
+	return []byte(message.String())
+}
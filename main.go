@@ -1,21 +1,37 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	commentDelivery "mygram-api/comment/delivery/http"
 	commentRepository "mygram-api/comment/repository/postgres"
 	commentUseCase "mygram-api/comment/usecase"
 	"mygram-api/config/database"
+	"mygram-api/helpers"
+	likeDelivery "mygram-api/like/delivery/http"
+	likeRepository "mygram-api/like/repository/postgres"
+	likeUseCase "mygram-api/like/usecase"
+	"mygram-api/mailer"
+	"mygram-api/middleware"
+	"mygram-api/oidc"
 	photoDelivery "mygram-api/photo/delivery/http"
 	photoRepository "mygram-api/photo/repository/postgres"
 	photoUseCase "mygram-api/photo/usecase"
 	socialMediaDelivery "mygram-api/socialmedia/delivery/http"
 	socialMediaRepository "mygram-api/socialmedia/repository/postgres"
 	socialMediaUseCase "mygram-api/socialmedia/usecase"
+	"mygram-api/storage"
 	userDelivery "mygram-api/user/delivery/http"
+	userMemoryRepository "mygram-api/user/repository/memory"
 	userRepository "mygram-api/user/repository/postgres"
 	userUseCase "mygram-api/user/usecase"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	_ "mygram-api/docs"
 
@@ -40,6 +56,11 @@ import (
 // @in                          header
 // @name                        Authorization
 // @description					        Description for what is this security definition being used
+
+// @securityDefinitions.apikey  ApiKey
+// @in                          header
+// @name                        X-API-Key
+// @description					        A personal API key created at /users/api-keys, limited to its scopes
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Fatal("Error loading .env file: ", err)
@@ -49,12 +70,18 @@ func main() {
 
 	routers := gin.Default()
 
+	// Login throttling counts failures per ClientIP, so only the proxies listed in
+	// TRUSTED_PROXIES are believed when they forward the client address.
+	if err := routers.SetTrustedProxies(strings.FieldsFunc(os.Getenv("TRUSTED_PROXIES"), func(r rune) bool { return r == ',' })); err != nil {
+		log.Fatal("Error setting trusted proxies: ", err)
+	}
+
 	routers.Use(func(ctx *gin.Context) {
 		ctx.Writer.Header().Set("Content-Type", "application/json")
 		ctx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		ctx.Writer.Header().Set("Access-Control-Max-Age", "86400")
-		ctx.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, PUT, DELETE, UPDATE")
-		ctx.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Max")
+		ctx.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, PUT, PATCH, DELETE, UPDATE")
+		ctx.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, X-Max")
 		ctx.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 
 		if ctx.Request.Method == "OPTIONS" {
@@ -64,20 +91,87 @@ func main() {
 		}
 	})
 
-	userRepository := userRepository.NewUserRepository(db)
-	userUseCase := userUseCase.NewUserUseCase(userRepository)
+	// SIGNING_KEY signs the tokens in verification links and login challenges,
+	// which anybody could forge with an empty key.
+	if os.Getenv("SIGNING_KEY") == "" {
+		log.Fatal("Error loading the signing key: SIGNING_KEY is not set")
+	}
+
+	mailer := mailer.NewFromEnv()
+
+	keyRing, err := helpers.KeyRingFromEnv()
+
+	if err != nil {
+		log.Fatal("Error loading the token signing keys: ", err)
+	}
+
+	helpers.UseKeyRing(keyRing)
+
+	blobStore := storage.NewFromEnv()
+
+	if mountable, ok := blobStore.(storage.Mountable); ok {
+		mountable.Mount(routers)
+	}
 
-	userDelivery.NewUserHandler(routers, userUseCase)
+	oidcProviders, err := oidc.ProvidersFromEnv()
 
+	if err != nil {
+		log.Fatal("Error configuring OpenID Connect providers: ", err)
+	}
+
+	refreshTokenRepository := userRepository.NewRefreshTokenRepository(db)
+	revocationRepository := userRepository.NewRevocationRepository(db, 30*time.Second)
+	passwordResetRepository := userRepository.NewPasswordResetRepository(db)
+	mfaRepository := userRepository.NewMFARepository(db)
+	lockoutEventRepository := userRepository.NewLockoutEventRepository(db)
+	loginAttemptStore := userMemoryRepository.NewLoginAttemptStore()
+	apiKeyRepository := userRepository.NewAPIKeyRepository(db)
+	externalIdentityRepository := userRepository.NewExternalIdentityRepository(db)
+	sessionRepository := userRepository.NewSessionRepository(db)
+	userRepository := userRepository.NewUserRepository(db)
+	tokenUseCase := userUseCase.NewTokenUseCase(refreshTokenRepository, revocationRepository, sessionRepository, userUseCase.NewMailLoginNotifier(mailer))
+	passwordResetUseCase := userUseCase.NewPasswordResetUseCase(userRepository, passwordResetRepository, tokenUseCase, mailer)
+	passwordResetQueue := userUseCase.NewPasswordResetQueue(passwordResetUseCase, helpers.PasswordResetWorkers())
+	emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(userRepository, mailer)
+	mfaUseCase := userUseCase.NewMFAUseCase(userRepository, mfaRepository, revocationRepository)
+	loginAttemptUseCase := userUseCase.NewLoginAttemptUseCase(loginAttemptStore, lockoutEventRepository, userRepository, mailer)
+	apiKeyUseCase := userUseCase.NewAPIKeyUseCase(apiKeyRepository)
+	oidcUseCase := userUseCase.NewOIDCUseCase(oidcProviders, userRepository, externalIdentityRepository)
+	sessionUseCase := userUseCase.NewSessionUseCase(sessionRepository)
+	avatarUseCase := userUseCase.NewAvatarUseCase(userRepository, blobStore)
+
+	helpers.UseRevocationChecker(revocationRepository)
+	middleware.UseAPIKeyAuthenticator(apiKeyUseCase)
+	userUseCase := userUseCase.NewUserUseCase(userRepository, tokenUseCase, avatarUseCase)
+
+	userDelivery.NewUserHandler(routers, userUseCase, tokenUseCase, emailVerificationUseCase, mfaUseCase, loginAttemptUseCase)
+	userDelivery.NewMFAHandler(routers, mfaUseCase, loginAttemptUseCase, tokenUseCase)
+	userDelivery.NewTokenHandler(routers, tokenUseCase)
+	userDelivery.NewPasswordHandler(routers, passwordResetUseCase, passwordResetQueue, userUseCase, tokenUseCase)
+	userDelivery.NewEmailVerificationHandler(routers, emailVerificationUseCase, userUseCase)
+	userDelivery.NewAdminHandler(routers, userUseCase, tokenUseCase)
+	userDelivery.NewAPIKeyHandler(routers, apiKeyUseCase)
+	userDelivery.NewOIDCHandler(routers, oidcUseCase, mfaUseCase, tokenUseCase)
+	userDelivery.NewJWKSHandler(routers, keyRing)
+	userDelivery.NewSessionHandler(routers, sessionUseCase, tokenUseCase)
+	userDelivery.NewAvatarHandler(routers, avatarUseCase)
+
+	likeRepository := likeRepository.NewLikeRepository(db)
+	likeUseCase := likeUseCase.NewLikeUseCase(likeRepository)
+
+	likeDelivery.NewLikeHandler(routers, likeUseCase)
+
+	photoVariantRepository := photoRepository.NewPhotoVariantRepository(db)
 	photoRepository := photoRepository.NewPhotoRepository(db)
-	photoUseCase := photoUseCase.NewPhotoUseCase(photoRepository)
+	variantGenerator := photoUseCase.NewVariantGenerator(photoRepository, photoVariantRepository, blobStore, helpers.PhotoVariantWidths(), helpers.PhotoVariantWorkers())
+	photoUseCase := photoUseCase.NewPhotoUseCase(photoRepository, photoVariantRepository, blobStore, variantGenerator)
 
-	photoDelivery.NewPhotoHandler(routers, photoUseCase)
+	photoDelivery.NewPhotoHandler(routers, photoUseCase, likeUseCase)
 
 	commentRepository := commentRepository.NewCommentRepository(db)
 	commentUseCase := commentUseCase.NewCommentUseCase(commentRepository)
 
-	commentDelivery.NewCommentHandler(routers, commentUseCase, photoUseCase)
+	commentDelivery.NewCommentHandler(routers, commentUseCase, photoUseCase, likeUseCase)
 
 	socialMediaRepository := socialMediaRepository.NewSocialMediaRepository(db)
 	socialMediaUseCase := socialMediaUseCase.NewSocialMediaUseCase(socialMediaRepository)
@@ -104,5 +198,33 @@ func main() {
 		port = "8080"
 	}
 
-	routers.Run(":" + port)
+	server := &http.Server{
+		Addr:    ":" + port,
+		Handler: routers,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Error starting the server: ", err)
+		}
+	}()
+
+	signals := make(chan os.Signal, 1)
+
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	<-signals
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("shutting down the server failed: %s", err)
+	}
+
+	// No request can enqueue anything anymore, so the photos still queued get
+	// their variants and the password resets their emails before the process exits.
+	variantGenerator.Close()
+	passwordResetQueue.Close()
 }
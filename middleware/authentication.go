@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"net/http"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader carries an API key in place of a bearer token.
+const APIKeyHeader = "X-API-Key"
+
+const principalKey = "principal"
+
+// APIKeyAuthenticator resolves an API key to the principal it acts as.
+type APIKeyAuthenticator interface {
+	Authenticate(context.Context, string) (domain.Principal, error)
+}
+
+var apiKeyAuthenticator APIKeyAuthenticator
+
+// UseAPIKeyAuthenticator lets Authentication accept API keys checked by authenticator.
+func UseAPIKeyAuthenticator(authenticator APIKeyAuthenticator) {
+	apiKeyAuthenticator = authenticator
+}
+
+// Authentication accepts a bearer access token or an API key and stores who
+// the request comes from as a domain.Principal under "principal". API keys
+// are only accepted when they hold every one of scopes, so a route that names
+// no scope is reserved for the account holder signed in with a token.
+func Authentication(scopes ...domain.Scope) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if key := ctx.GetHeader(APIKeyHeader); key != "" {
+			authenticateAPIKey(ctx, key, scopes)
+
+			return
+		}
+
+		verifyToken, err := helpers.VerifyToken(ctx)
+
+		if errors.Is(err, helpers.ErrTokenExpired) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "token_expired",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.Set(principalKey, principalFromClaims(verifyToken.(jwt.MapClaims)))
+		ctx.Next()
+	}
+}
+
+func authenticateAPIKey(ctx *gin.Context, key string, scopes []domain.Scope) {
+	if apiKeyAuthenticator == nil || len(scopes) == 0 {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+			Status:  "unauthenticated",
+			Message: "API keys aren't accepted here, sign in to proceed",
+		})
+
+		return
+	}
+
+	principal, err := apiKeyAuthenticator.Authenticate(ctx.Request.Context(), key)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+			Status:  "unauthenticated",
+			Message: domain.ErrAPIKeyInvalid.Error(),
+		})
+
+		return
+	}
+
+	for _, scope := range scopes {
+		if !principal.HasScope(scope) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+				Status:  "forbidden",
+				Message: fmt.Sprintf("this API key lacks the %s scope", scope),
+			})
+
+			return
+		}
+	}
+
+	ctx.Set(principalKey, principal)
+	ctx.Next()
+}
+
+// principalFromClaims reads the principal out of verified access token claims.
+// Tokens minted before roles existed carry none and are treated as RoleUser.
+func principalFromClaims(claims jwt.MapClaims) domain.Principal {
+	id, _ := claims["id"].(string)
+	email, _ := claims["email"].(string)
+	verified, _ := claims["email_verified"].(bool)
+	role, _ := claims["role"].(string)
+	jti, _ := claims["jti"].(string)
+	sid, _ := claims["sid"].(string)
+	exp, _ := claims["exp"].(float64)
+
+	if !domain.Role(role).IsValid() {
+		role = string(domain.RoleUser)
+	}
+
+	return domain.Principal{
+		UserID:        id,
+		Email:         email,
+		EmailVerified: verified,
+		Role:          domain.Role(role),
+		TokenID:       jti,
+		SessionID:     sid,
+		ExpiresAt:     time.Unix(int64(exp), 0),
+	}
+}
+
+// CurrentPrincipal returns the principal Authentication stored for this request.
+func CurrentPrincipal(ctx *gin.Context) domain.Principal {
+	return ctx.MustGet(principalKey).(domain.Principal)
+}
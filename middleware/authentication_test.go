@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"mygram-api/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type apiKeys map[string]domain.Principal
+
+func (apiKeys apiKeys) Authenticate(ctx context.Context, key string) (domain.Principal, error) {
+	principal, ok := apiKeys[key]
+
+	if !ok {
+		return principal, domain.ErrAPIKeyInvalid
+	}
+
+	return principal, nil
+}
+
+func authenticate(key string, scopes ...domain.Scope) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	recorder := httptest.NewRecorder()
+	router := gin.New()
+
+	router.GET("/", Authentication(scopes...), func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, CurrentPrincipal(ctx).UserID)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(APIKeyHeader, key)
+	router.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestAuthentication(t *testing.T) {
+	UseAPIKeyAuthenticator(apiKeys{
+		"key-read": {UserID: "user-123", Role: domain.RoleUser, APIKeyID: "apikey-123", Scopes: []domain.Scope{domain.ScopePhotosRead}},
+	})
+
+	t.Cleanup(func() {
+		UseAPIKeyAuthenticator(nil)
+	})
+
+	t.Run("authenticate an api key with the scope", func(t *testing.T) {
+		recorder := authenticate("key-read", domain.ScopePhotosRead)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "user-123", recorder.Body.String())
+	})
+
+	t.Run("authenticate an api key without the scope", func(t *testing.T) {
+		recorder := authenticate("key-read", domain.ScopePhotosWrite)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+
+	t.Run("authenticate an api key on a route that names no scope", func(t *testing.T) {
+		recorder := authenticate("key-read")
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "API keys aren't accepted here")
+	})
+
+	t.Run("authenticate an unknown api key", func(t *testing.T) {
+		recorder := authenticate("key-unknown", domain.ScopePhotosRead)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+}
+
+func TestPrincipalFromClaims(t *testing.T) {
+	t.Run("principal from claims with a role", func(t *testing.T) {
+		principal := principalFromClaims(jwt.MapClaims{
+			"id":             "user-123",
+			"email":          "johndoe@example.com",
+			"email_verified": true,
+			"role":           "moderator",
+			"jti":            "token-123",
+			"sid":            "session-123",
+			"exp":            float64(1664627400),
+		})
+
+		assert.Equal(t, domain.Principal{
+			UserID:        "user-123",
+			Email:         "johndoe@example.com",
+			EmailVerified: true,
+			Role:          domain.RoleModerator,
+			TokenID:       "token-123",
+			SessionID:     "session-123",
+			ExpiresAt:     time.Unix(1664627400, 0),
+		}, principal)
+	})
+
+	t.Run("principal from claims without a role", func(t *testing.T) {
+		principal := principalFromClaims(jwt.MapClaims{"id": "user-123"})
+
+		assert.Equal(t, "user-123", principal.UserID)
+		assert.Equal(t, domain.RoleUser, principal.Role)
+	})
+
+	t.Run("principal from claims with an unknown role", func(t *testing.T) {
+		principal := principalFromClaims(jwt.MapClaims{"id": "user-123", "role": "root"})
+
+		assert.Equal(t, domain.RoleUser, principal.Role)
+	})
+}
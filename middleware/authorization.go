@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mygram-api/helpers"
+	"mygram-api/policy"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const resourceKey = "resource"
+
+// OwnedResourceLoader loads a resource by its id. The GetByID method of
+// PhotoUseCase, CommentUseCase and SocialMediaUseCase satisfies it.
+type OwnedResourceLoader[T Owned] interface {
+	GetByID(context.Context, *T, string) error
+}
+
+// Owned is a resource that knows which user it belongs to.
+type Owned interface {
+	OwnerID() string
+}
+
+// Authorization loads the resource whose id is in the path parameter param and
+// lets the request through when the policy allows the caller to perform action
+// on it. The loaded resource is kept for the handler, see Loaded.
+func Authorization[T Owned](loader OwnedResourceLoader[T], param string, resource policy.Resource, action policy.Action) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var loaded T
+
+		id := ctx.Param(param)
+		subject := policy.SubjectFromPrincipal(CurrentPrincipal(ctx))
+
+		if err := loader.GetByID(ctx.Request.Context(), &loaded, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				AbortNotFound(ctx, resource, id)
+
+				return
+			}
+
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+				Status:  "error",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		if !policy.Can(subject, action, resource, loaded.OwnerID()) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+				Status:  "forbidden",
+				Message: fmt.Sprintf("you don't have permission to %s this %s", action, resource.Label()),
+			})
+
+			return
+		}
+
+		ctx.Set(resourceKey, loaded)
+		ctx.Next()
+	}
+}
+
+// AbortNotFound answers that the resource with id doesn't exist. Handlers use it
+// too, so a missing photo, comment or social media reads the same whether it was
+// found missing here or further down.
+func AbortNotFound(ctx *gin.Context, resource policy.Resource, id string) {
+	ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+		Status:  "fail",
+		Message: fmt.Sprintf("%s with id %s doesn't exist", resource.Label(), id),
+	})
+}
+
+// Loaded returns the resource Authorization loaded for this request.
+func Loaded[T Owned](ctx *gin.Context) T {
+	return ctx.MustGet(resourceKey).(T)
+}
+
+// Authorize lets the request through when the role of the caller allows action
+// on every resource of the given kind, e.g. admins managing users.
+func Authorize(action policy.Action, resource policy.Resource) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		subject := policy.SubjectFromPrincipal(CurrentPrincipal(ctx))
+
+		if !policy.Can(subject, action, resource, "") {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+				Status:  "forbidden",
+				Message: fmt.Sprintf("you don't have permission to %s %ss", action, resource.Label()),
+			})
+
+			return
+		}
+
+		ctx.Next()
+	}
+}
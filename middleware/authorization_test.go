@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/policy"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type photoLoader struct {
+	photo domain.Photo
+	err   error
+}
+
+func (photoLoader photoLoader) GetByID(ctx context.Context, photo *domain.Photo, id string) error {
+	*photo = photoLoader.photo
+
+	return photoLoader.err
+}
+
+func authorize(loader photoLoader, principal domain.Principal) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	recorder := httptest.NewRecorder()
+	router := gin.New()
+
+	router.PUT("/photos/:photoId", func(ctx *gin.Context) {
+		ctx.Set(principalKey, principal)
+	}, Authorization[domain.Photo](loader, "photoId", policy.ResourcePhoto, policy.ActionUpdate), func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/photos/photo-123", nil))
+
+	return recorder
+}
+
+func TestAuthorization(t *testing.T) {
+	owner := domain.Principal{UserID: "user-123", Role: domain.RoleUser}
+
+	t.Run("authorize the owner", func(t *testing.T) {
+		recorder := authorize(photoLoader{photo: domain.Photo{ID: "photo-123", UserID: owner.UserID}}, owner)
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+	})
+
+	t.Run("authorize someone else", func(t *testing.T) {
+		recorder := authorize(photoLoader{photo: domain.Photo{ID: "photo-123", UserID: "user-234"}}, owner)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+
+	t.Run("authorize on a missing photo", func(t *testing.T) {
+		recorder := authorize(photoLoader{err: gorm.ErrRecordNotFound}, owner)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "photo with id photo-123 doesn't exist")
+	})
+
+	t.Run("authorize while the database is down", func(t *testing.T) {
+		recorder := authorize(photoLoader{err: errors.New("connection refused")}, owner)
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.NotContains(t, recorder.Body.String(), "doesn't exist")
+	})
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"mygram-api/helpers"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerifiedEmail turns away users who haven't verified their email yet, but only
+// when the operator opted in through REQUIRE_VERIFIED_EMAIL.
+func VerifiedEmail() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !helpers.RequireVerifiedEmail() {
+			return
+		}
+
+		if !CurrentPrincipal(ctx).EmailVerified {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+				Status:  "unverified",
+				Message: "verify your email address to proceed",
+			})
+
+			return
+		}
+	}
+}
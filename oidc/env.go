@@ -0,0 +1,44 @@
+package oidc
+
+import (
+	"fmt"
+	"mygram-api/domain"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ProvidersFromEnv sets up every provider named in OIDC_PROVIDERS (e.g.
+// "google,gitlab"). A provider called google is configured through
+// OIDC_GOOGLE_ISSUER, OIDC_GOOGLE_CLIENT_ID, OIDC_GOOGLE_CLIENT_SECRET (left
+// empty for public clients), OIDC_GOOGLE_REDIRECT_URL and optionally
+// OIDC_GOOGLE_SCOPES, which defaults to "openid email profile".
+func ProvidersFromEnv() (map[string]domain.OIDCProvider, error) {
+	providers := map[string]domain.OIDCProvider{}
+	client := &http.Client{Timeout: clientTimeout}
+
+	for _, name := range strings.FieldsFunc(os.Getenv("OIDC_PROVIDERS"), func(r rune) bool { return r == ',' }) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+
+		config := Config{
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+
+		if config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+			return nil, fmt.Errorf("%sISSUER, %sCLIENT_ID and %sREDIRECT_URL are required", prefix, prefix, prefix)
+		}
+
+		if len(config.Scopes) == 0 {
+			config.Scopes = []string{"openid", "email", "profile"}
+		}
+
+		providers[name] = NewProvider(config, client)
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,276 @@
+// Package oidctest runs a stand-in OpenID Connect provider on httptest for
+// tests of the login flow. It signs every user in without asking and checks
+// the PKCE verifier, nonce and redirect URL the way a real provider would.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const keyID = "oidctest"
+
+type authorization struct {
+	challenge   string
+	nonce       string
+	redirectURL string
+}
+
+// Server is the stand-in provider. Its Issuer is the httptest URL.
+type Server struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	key *rsa.PrivateKey
+
+	mu          sync.Mutex
+	claims      map[string]interface{}
+	codes       map[string]authorization
+	keyID       string
+	jwksFetches int
+}
+
+// NewServer starts a provider that knows a single client. Leave clientSecret
+// empty to have it act for a public client.
+func NewServer(clientID string, clientSecret string) *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		panic(err)
+	}
+
+	server := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		key:          key,
+		claims:       map[string]interface{}{},
+		codes:        map[string]authorization{},
+		keyID:        keyID,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", server.discovery)
+	mux.HandleFunc("/jwks", server.jwks)
+	mux.HandleFunc("/authorize", server.authorize)
+	mux.HandleFunc("/token", server.token)
+
+	server.Server = httptest.NewServer(mux)
+
+	return server
+}
+
+// SetClaims decides who signs in next, e.g. {"sub": "123", "email": ...,
+// "email_verified": true}. The issuer, audience, nonce and times are added.
+func (server *Server) SetClaims(claims map[string]interface{}) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.claims = claims
+}
+
+// SetKeyID decides the kid the next ID tokens carry. The key set keeps being
+// published under the original one, so anything else is a kid nobody knows.
+func (server *Server) SetKeyID(kid string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.keyID = kid
+}
+
+// JWKSFetches tells how many times the key set has been fetched.
+func (server *Server) JWKSFetches() int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return server.jwksFetches
+}
+
+// Authorize plays the user's browser at authURL and returns the code and
+// state the provider sends back to the redirect URL.
+func (server *Server) Authorize(authURL string) (code string, state string, err error) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	response, err := client.Get(authURL)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusFound {
+		return "", "", errors.New("the stand-in provider refused the authorization request")
+	}
+
+	location, err := url.Parse(response.Header.Get("Location"))
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return location.Query().Get("code"), location.Query().Get("state"), nil
+}
+
+func (server *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 server.URL,
+		"authorization_endpoint": server.URL + "/authorize",
+		"token_endpoint":         server.URL + "/token",
+		"jwks_uri":               server.URL + "/jwks",
+	})
+}
+
+func (server *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	server.mu.Lock()
+	server.jwksFetches++
+	server.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(server.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(server.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (server *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if query.Get("client_id") != server.ClientID || query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+
+		return
+	}
+
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+
+	if err != nil || redirect.Host == "" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+
+		return
+	}
+
+	code := randomString()
+
+	server.mu.Lock()
+	server.codes[code] = authorization{query.Get("code_challenge"), query.Get("nonce"), query.Get("redirect_uri")}
+	server.mu.Unlock()
+
+	callback := redirect.Query()
+	callback.Set("code", code)
+	callback.Set("state", query.Get("state"))
+	redirect.RawQuery = callback.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (server *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+
+		return
+	}
+
+	if !server.authenticateClient(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+
+		return
+	}
+
+	server.mu.Lock()
+	grant, ok := server.codes[r.PostForm.Get("code")]
+	delete(server.codes, r.PostForm.Get("code"))
+	claims := jwt.MapClaims{}
+
+	for name, value := range server.claims {
+		claims[name] = value
+	}
+
+	kid := server.keyID
+	server.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+
+	if !ok || grant.redirectURL != r.PostForm.Get("redirect_uri") || grant.challenge != base64.RawURLEncoding.EncodeToString(verifier[:]) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+
+		return
+	}
+
+	now := time.Now()
+	claims["iss"] = server.URL
+	claims["aud"] = server.ClientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(time.Hour).Unix()
+	claims["nonce"] = grant.nonce
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	idToken, err := token.SignedString(server.key)
+
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (server *Server) authenticateClient(r *http.Request) bool {
+	if server.ClientSecret == "" {
+		return r.PostForm.Get("client_id") == server.ClientID
+	}
+
+	id, secret, ok := r.BasicAuth()
+
+	if !ok {
+		return false
+	}
+
+	id, _ = url.QueryUnescape(id)
+	secret, _ = url.QueryUnescape(secret)
+
+	return id == server.ClientID && secret == server.ClientSecret
+}
+
+func randomString() string {
+	buf := make([]byte, 16)
+
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,329 @@
+// Package oidc signs users in with an OpenID Connect provider through the
+// authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"mygram-api/domain"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// clientTimeout bounds every request to a provider when no client is given.
+const clientTimeout = 10 * time.Second
+
+// keysRefetchInterval is how long an unknown kid is refused before the key set
+// is fetched again, so tokens with made-up kids can't hammer the provider.
+const keysRefetchInterval = time.Minute
+
+// Config describes a provider as it was registered with it.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type provider struct {
+	config Config
+	client *http.Client
+
+	mu            sync.Mutex
+	discovery     *discovery
+	keys          map[string]*rsa.PublicKey
+	keysFetchedAt time.Time
+}
+
+// NewProvider talks to the provider at config.Issuer through client. The
+// discovery document and signing keys are fetched on first use.
+func NewProvider(config Config, client *http.Client) *provider {
+	if client == nil {
+		client = &http.Client{Timeout: clientTimeout}
+	}
+
+	return &provider{config: config, client: client}
+}
+
+// AuthCodeURL is where the user is sent to sign in. Only the S256 challenge of
+// codeVerifier leaves this server; the verifier itself goes with Exchange.
+func (provider *provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	discovery, err := provider.discover(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {provider.config.ClientID},
+		"redirect_uri":          {provider.config.RedirectURL},
+		"scope":                 {strings.Join(provider.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems code for an ID token and returns its claims once the token
+// is verified to be signed by the provider, meant for this client, and bound
+// to nonce.
+func (provider *provider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (claims domain.OIDCClaims, err error) {
+	discovery, err := provider.discover(ctx)
+
+	if err != nil {
+		return claims, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {provider.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+
+	if provider.config.ClientSecret == "" {
+		form.Set("client_id", provider.config.ClientID)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+
+	if err != nil {
+		return claims, err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	if provider.config.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(provider.config.ClientID), url.QueryEscape(provider.config.ClientSecret))
+	}
+
+	response, err := provider.client.Do(request)
+
+	if err != nil {
+		return claims, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return claims, domain.ErrOIDCCodeExchangeFailed
+	}
+
+	token := struct {
+		IDToken string `json:"id_token"`
+	}{}
+
+	if err = json.NewDecoder(response.Body).Decode(&token); err != nil || token.IDToken == "" {
+		return claims, domain.ErrOIDCIDTokenInvalid
+	}
+
+	return provider.verify(ctx, discovery, token.IDToken, nonce)
+}
+
+func (provider *provider) verify(ctx context.Context, discovery *discovery, idToken string, nonce string) (claims domain.OIDCClaims, err error) {
+	parsed, err := jwt.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodRS256 {
+			return nil, domain.ErrOIDCIDTokenInvalid
+		}
+
+		kid, _ := token.Header["kid"].(string)
+
+		return provider.key(ctx, discovery, kid)
+	})
+
+	if err != nil || !parsed.Valid {
+		return claims, domain.ErrOIDCIDTokenInvalid
+	}
+
+	mapClaims, ok := parsed.Claims.(jwt.MapClaims)
+
+	if !ok || !mapClaims.VerifyIssuer(discovery.Issuer, true) || !hasAudience(mapClaims["aud"], provider.config.ClientID) {
+		return claims, domain.ErrOIDCIDTokenInvalid
+	}
+
+	// Without an expiry the token would be good forever, jwt-go only checks it when present.
+	if _, ok := mapClaims["exp"]; !ok {
+		return claims, domain.ErrOIDCIDTokenInvalid
+	}
+
+	if given, _ := mapClaims["nonce"].(string); given == "" || given != nonce {
+		return claims, domain.ErrOIDCIDTokenInvalid
+	}
+
+	claims.Subject, _ = mapClaims["sub"].(string)
+	claims.Email, _ = mapClaims["email"].(string)
+	claims.PreferredUsername, _ = mapClaims["preferred_username"].(string)
+
+	// Some providers send email_verified as a string.
+	switch verified := mapClaims["email_verified"].(type) {
+	case bool:
+		claims.EmailVerified = verified
+	case string:
+		claims.EmailVerified = verified == "true"
+	}
+
+	if claims.Subject == "" {
+		return domain.OIDCClaims{}, domain.ErrOIDCIDTokenInvalid
+	}
+
+	return claims, nil
+}
+
+func (provider *provider) discover(ctx context.Context) (*discovery, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	if provider.discovery != nil {
+		return provider.discovery, nil
+	}
+
+	document := &discovery{}
+
+	if err := provider.getJSON(ctx, strings.TrimSuffix(provider.config.Issuer, "/")+"/.well-known/openid-configuration", document); err != nil {
+		return nil, err
+	}
+
+	if document.Issuer != provider.config.Issuer {
+		return nil, fmt.Errorf("the provider claims to be %q instead of %q", document.Issuer, provider.config.Issuer)
+	}
+
+	if document.AuthorizationEndpoint == "" || document.TokenEndpoint == "" || document.JWKSURI == "" {
+		return nil, errors.New("the provider's discovery document is incomplete")
+	}
+
+	provider.discovery = document
+
+	return document, nil
+}
+
+// key returns the signing key kid, fetching the key set again when kid is
+// unknown so keys the provider rotated in are picked up. The set is fetched at
+// most once every keysRefetchInterval.
+func (provider *provider) key(ctx context.Context, discovery *discovery, kid string) (*rsa.PublicKey, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	if key, ok := provider.keys[kid]; ok {
+		return key, nil
+	}
+
+	if time.Since(provider.keysFetchedAt) < keysRefetchInterval {
+		return nil, domain.ErrOIDCIDTokenInvalid
+	}
+
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+
+	if err := provider.getJSON(ctx, discovery.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+
+		if err != nil {
+			continue
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+
+		if err != nil {
+			continue
+		}
+
+		keys[key.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+
+	provider.keys = keys
+	provider.keysFetchedAt = time.Now()
+
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, domain.ErrOIDCIDTokenInvalid
+}
+
+func (provider *provider) getJSON(ctx context.Context, url string, v interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Accept", "application/json")
+
+	response, err := provider.client.Do(request)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching %s failed with status %d", url, response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(v)
+}
+
+func hasAudience(aud interface{}, clientID string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, audience := range aud {
+			if audience == clientID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
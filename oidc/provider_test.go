@@ -0,0 +1,130 @@
+package oidc_test
+
+import (
+	"context"
+	"mygram-api/domain"
+	"mygram-api/oidc"
+	"mygram-api/oidc/oidctest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider(t *testing.T) {
+	server := oidctest.NewServer("mygram", "secret")
+	defer server.Close()
+
+	server.SetClaims(map[string]interface{}{
+		"sub":            "subject-123",
+		"email":          "johndoe@example.com",
+		"email_verified": true,
+	})
+
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       server.URL,
+		ClientID:     "mygram",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8080/auth/test/callback",
+		Scopes:       []string{"openid", "email"},
+	}, nil)
+
+	t.Run("sign in correctly", func(t *testing.T) {
+		authURL, err := provider.AuthCodeURL(context.Background(), "the-state", "the-nonce", "the-code-verifier-that-is-long-enough-0123")
+		require.NoError(t, err)
+
+		parsed, err := url.Parse(authURL)
+		require.NoError(t, err)
+		assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+		assert.NotContains(t, authURL, "the-code-verifier")
+
+		code, state, err := server.Authorize(authURL)
+		require.NoError(t, err)
+		assert.Equal(t, "the-state", state)
+
+		claims, err := provider.Exchange(context.Background(), code, "the-code-verifier-that-is-long-enough-0123", "the-nonce")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OIDCClaims{Subject: "subject-123", Email: "johndoe@example.com", EmailVerified: true}, claims)
+	})
+
+	t.Run("sign in with a wrong code verifier", func(t *testing.T) {
+		authURL, err := provider.AuthCodeURL(context.Background(), "the-state", "the-nonce", "the-code-verifier-that-is-long-enough-0123")
+		require.NoError(t, err)
+
+		code, _, err := server.Authorize(authURL)
+		require.NoError(t, err)
+
+		_, err = provider.Exchange(context.Background(), code, "another-code-verifier-that-is-long-enough-0", "the-nonce")
+
+		assert.ErrorIs(t, err, domain.ErrOIDCCodeExchangeFailed)
+	})
+
+	t.Run("sign in with a wrong nonce", func(t *testing.T) {
+		authURL, err := provider.AuthCodeURL(context.Background(), "the-state", "the-nonce", "the-code-verifier-that-is-long-enough-0123")
+		require.NoError(t, err)
+
+		code, _, err := server.Authorize(authURL)
+		require.NoError(t, err)
+
+		_, err = provider.Exchange(context.Background(), code, "the-code-verifier-that-is-long-enough-0123", "another-nonce")
+
+		assert.ErrorIs(t, err, domain.ErrOIDCIDTokenInvalid)
+	})
+
+	t.Run("sign in with a code used twice", func(t *testing.T) {
+		authURL, err := provider.AuthCodeURL(context.Background(), "the-state", "the-nonce", "the-code-verifier-that-is-long-enough-0123")
+		require.NoError(t, err)
+
+		code, _, err := server.Authorize(authURL)
+		require.NoError(t, err)
+
+		_, err = provider.Exchange(context.Background(), code, "the-code-verifier-that-is-long-enough-0123", "the-nonce")
+		require.NoError(t, err)
+
+		_, err = provider.Exchange(context.Background(), code, "the-code-verifier-that-is-long-enough-0123", "the-nonce")
+
+		assert.ErrorIs(t, err, domain.ErrOIDCCodeExchangeFailed)
+	})
+
+	t.Run("redeem a code as another client", func(t *testing.T) {
+		other := oidc.NewProvider(oidc.Config{
+			Issuer:       server.URL,
+			ClientID:     "another-client",
+			ClientSecret: "secret",
+			RedirectURL:  "http://localhost:8080/auth/test/callback",
+		}, nil)
+
+		authURL, err := provider.AuthCodeURL(context.Background(), "the-state", "the-nonce", "the-code-verifier-that-is-long-enough-0123")
+		require.NoError(t, err)
+
+		code, _, err := server.Authorize(authURL)
+		require.NoError(t, err)
+
+		_, err = other.Exchange(context.Background(), code, "the-code-verifier-that-is-long-enough-0123", "the-nonce")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("sign in with unknown kids doesn't refetch the keys every time", func(t *testing.T) {
+		server.SetKeyID("unknown")
+		defer server.SetKeyID("oidctest")
+
+		fetches := server.JWKSFetches()
+
+		for i := 0; i < 3; i++ {
+			authURL, err := provider.AuthCodeURL(context.Background(), "the-state", "the-nonce", "the-code-verifier-that-is-long-enough-0123")
+			require.NoError(t, err)
+
+			code, _, err := server.Authorize(authURL)
+			require.NoError(t, err)
+
+			_, err = provider.Exchange(context.Background(), code, "the-code-verifier-that-is-long-enough-0123", "the-nonce")
+
+			assert.ErrorIs(t, err, domain.ErrOIDCIDTokenInvalid)
+		}
+
+		assert.Equal(t, fetches, server.JWKSFetches())
+	})
+}
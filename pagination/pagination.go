@@ -0,0 +1,205 @@
+// Package pagination pages through lists with opaque cursors. A cursor holds
+// the created_at and id of the last item a page returned, so the next page
+// picks up right after it however many rows were added in the meantime.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCursorInvalid = errors.New("the cursor is invalid")
+	ErrLimitInvalid  = errors.New("limit must be a positive number")
+	ErrSortInvalid   = errors.New("sort must be newest or oldest")
+	ErrTimeInvalid   = errors.New("created_after and created_before must be RFC 3339 times")
+)
+
+const (
+	// DefaultLimit is the length of a page when the client doesn't ask for one.
+	DefaultLimit = 20
+
+	// MaxLimit is the longest page served, larger limits are cut down to it.
+	MaxLimit = 100
+)
+
+type Sort string
+
+const (
+	SortNewest Sort = "newest"
+	SortOldest Sort = "oldest"
+)
+
+// Cursor points at the last item of a page.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// NewCursor points at the item with the given created_at and id.
+func NewCursor(createdAt *time.Time, id string) Cursor {
+	cursor := Cursor{ID: id}
+
+	if createdAt != nil {
+		cursor.CreatedAt = *createdAt
+	}
+
+	return cursor
+}
+
+// Query asks for one page of a list.
+type Query struct {
+	After         *Cursor
+	Limit         int
+	Sort          Sort
+	UserID        string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// Parse reads a Query from the cursor, limit, sort, user_id, created_after and
+// created_before parameters of a request.
+func Parse(values url.Values) (query Query, err error) {
+	query = Query{Limit: DefaultLimit, Sort: SortNewest, UserID: values.Get("user_id")}
+
+	if value := values.Get("cursor"); value != "" {
+		cursor, err := DecodeCursor(value)
+
+		if err != nil {
+			return query, err
+		}
+
+		query.After = &cursor
+	}
+
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+
+		if err != nil || limit < 1 {
+			return query, ErrLimitInvalid
+		}
+
+		query.Limit = limit
+	}
+
+	if query.Limit > MaxLimit {
+		query.Limit = MaxLimit
+	}
+
+	if value := values.Get("sort"); value != "" {
+		query.Sort = Sort(value)
+
+		if query.Sort != SortNewest && query.Sort != SortOldest {
+			return query, ErrSortInvalid
+		}
+	}
+
+	if query.CreatedAfter, err = parseTime(values.Get("created_after")); err != nil {
+		return query, err
+	}
+
+	if query.CreatedBefore, err = parseTime(values.Get("created_before")); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
+
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+
+	if err != nil {
+		return nil, ErrTimeInvalid
+	}
+
+	return &parsed, nil
+}
+
+// Encode turns cursor into the string handed to clients as next_cursor.
+func (cursor Cursor) Encode() string {
+	data, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reads a cursor produced by Encode.
+func DecodeCursor(value string) (cursor Cursor, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+
+	if err != nil {
+		return cursor, ErrCursorInvalid
+	}
+
+	if err = json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" || cursor.CreatedAt.IsZero() {
+		return cursor, ErrCursorInvalid
+	}
+
+	return cursor, nil
+}
+
+// Scope filters, orders and limits a query on table, whose rows need created_at
+// and id columns. One row more than the page holds is fetched so Trim can tell
+// whether another page follows.
+func (query Query) Scope(table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		createdAt, id := table+".created_at", table+".id"
+
+		if query.UserID != "" {
+			db = db.Where(table+".user_id = ?", query.UserID)
+		}
+
+		if query.CreatedAfter != nil {
+			db = db.Where(createdAt+" > ?", *query.CreatedAfter)
+		}
+
+		if query.CreatedBefore != nil {
+			db = db.Where(createdAt+" < ?", *query.CreatedBefore)
+		}
+
+		comparison, direction := "<", "DESC"
+
+		if query.Sort == SortOldest {
+			comparison, direction = ">", "ASC"
+		}
+
+		if query.After != nil {
+			db = db.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", createdAt, id, comparison), query.After.CreatedAt, query.After.ID)
+		}
+
+		return db.Order(fmt.Sprintf("%s %s, %s %s", createdAt, direction, id, direction)).Limit(query.limit() + 1)
+	}
+}
+
+// Trim cuts items, fetched with Scope, down to the page and returns the cursor
+// of the next one, or "" when this was the last.
+func Trim[T any](query Query, items *[]T, cursor func(T) Cursor) string {
+	if len(*items) <= query.limit() {
+		return ""
+	}
+
+	*items = (*items)[:query.limit()]
+
+	return cursor((*items)[len(*items)-1]).Encode()
+}
+
+func (query Query) limit() int {
+	switch {
+	case query.Limit < 1:
+		return DefaultLimit
+	case query.Limit > MaxLimit:
+		return MaxLimit
+	}
+
+	return query.Limit
+}
@@ -0,0 +1,131 @@
+package pagination_test
+
+import (
+	"mygram-api/pagination"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type item struct {
+	ID        string
+	CreatedAt time.Time
+}
+
+func TestParse(t *testing.T) {
+	cursor := pagination.Cursor{CreatedAt: time.Date(2022, 10, 1, 12, 30, 0, 123456000, time.UTC), ID: "photo-123"}
+
+	t.Run("parse the defaults", func(t *testing.T) {
+		query, err := pagination.Parse(url.Values{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, pagination.Query{Limit: pagination.DefaultLimit, Sort: pagination.SortNewest}, query)
+	})
+
+	t.Run("parse every parameter", func(t *testing.T) {
+		query, err := pagination.Parse(url.Values{
+			"cursor":         {cursor.Encode()},
+			"limit":          {"5"},
+			"sort":           {"oldest"},
+			"user_id":        {"user-123"},
+			"created_after":  {"2022-01-01T00:00:00Z"},
+			"created_before": {"2023-01-01T00:00:00+07:00"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &cursor, query.After)
+		assert.Equal(t, 5, query.Limit)
+		assert.Equal(t, pagination.SortOldest, query.Sort)
+		assert.Equal(t, "user-123", query.UserID)
+		assert.True(t, query.CreatedAfter.Equal(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, query.CreatedBefore.Equal(time.Date(2022, 12, 31, 17, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("parse a limit above the maximum", func(t *testing.T) {
+		query, err := pagination.Parse(url.Values{"limit": {"100000"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, pagination.MaxLimit, query.Limit)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		values url.Values
+		err    error
+	}{
+		{"parse a negative limit", url.Values{"limit": {"-1"}}, pagination.ErrLimitInvalid},
+		{"parse a limit that is not a number", url.Values{"limit": {"ten"}}, pagination.ErrLimitInvalid},
+		{"parse an unknown sort", url.Values{"sort": {"random"}}, pagination.ErrSortInvalid},
+		{"parse a cursor that isn't one", url.Values{"cursor": {"photo-123"}}, pagination.ErrCursorInvalid},
+		{"parse a date that isn't one", url.Values{"created_after": {"yesterday"}}, pagination.ErrTimeInvalid},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := pagination.Parse(tc.values)
+
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	cursor := pagination.Cursor{CreatedAt: time.Date(2022, 10, 1, 12, 30, 0, 123456000, time.UTC), ID: "photo-123"}
+
+	decoded, err := pagination.DecodeCursor(cursor.Encode())
+
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	_, err = pagination.DecodeCursor("e30")
+
+	assert.ErrorIs(t, err, pagination.ErrCursorInvalid)
+}
+
+func TestTrim(t *testing.T) {
+	now := time.Now().UTC()
+	cursor := func(i item) pagination.Cursor { return pagination.Cursor{CreatedAt: i.CreatedAt, ID: i.ID} }
+
+	t.Run("trim the last page", func(t *testing.T) {
+		items := []item{{"a", now}, {"b", now}}
+
+		next := pagination.Trim(pagination.Query{Limit: 2}, &items, cursor)
+
+		assert.Empty(t, next)
+		assert.Len(t, items, 2)
+	})
+
+	t.Run("trim a page with more to come", func(t *testing.T) {
+		items := []item{{"a", now}, {"b", now}, {"c", now}}
+
+		next := pagination.Trim(pagination.Query{Limit: 2}, &items, cursor)
+
+		assert.Len(t, items, 2)
+		assert.Equal(t, pagination.Cursor{CreatedAt: now, ID: "b"}.Encode(), next)
+	})
+}
+
+func TestScope(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+
+	assert.NoError(t, err)
+
+	after := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	cursor := pagination.Cursor{CreatedAt: time.Date(2022, 10, 1, 12, 30, 0, 0, time.UTC), ID: "photo-123"}
+
+	t.Run("scope the first page", func(t *testing.T) {
+		statement := db.Table("photos").Scopes(pagination.Query{Limit: 10, Sort: pagination.SortNewest}.Scope("photos")).Find(&[]item{}).Statement
+
+		assert.Equal(t, "SELECT * FROM \"photos\" ORDER BY photos.created_at DESC, photos.id DESC LIMIT 11", statement.SQL.String())
+	})
+
+	t.Run("scope a following page", func(t *testing.T) {
+		query := pagination.Query{After: &cursor, Limit: 500, Sort: pagination.SortOldest, UserID: "user-123", CreatedAfter: &after}
+		statement := db.Table("photos").Scopes(query.Scope("photos")).Find(&[]item{}).Statement
+
+		assert.Equal(t, "SELECT * FROM \"photos\" WHERE photos.user_id = $1 AND photos.created_at > $2 AND (photos.created_at, photos.id) > ($3, $4) ORDER BY photos.created_at ASC, photos.id ASC LIMIT 101", statement.SQL.String())
+		assert.Equal(t, []interface{}{"user-123", after, cursor.CreatedAt, cursor.ID}, statement.Vars)
+	})
+}
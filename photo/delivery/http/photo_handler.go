@@ -1,51 +1,68 @@
 package delivery
 
 import (
+	"errors"
+	commentUtils "mygram-api/comment/utils"
 	"mygram-api/domain"
 	"mygram-api/helpers"
-	"mygram-api/photo/delivery/http/middleware"
+	"mygram-api/middleware"
+	"mygram-api/pagination"
 	"mygram-api/photo/utils"
+	"mygram-api/policy"
 	"net/http"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+// photoBodyMaxBytes caps the whole upload request: the 10 MB photo the usecase
+// accepts plus room for the title, the caption and the multipart framing.
+const photoBodyMaxBytes = 11 << 20
+
 type photoHandler struct {
 	photoUseCase domain.PhotoUseCase
+	likeUseCase  domain.LikeUseCase
 }
 
-func NewPhotoHandler(routers *gin.Engine, photoUseCase domain.PhotoUseCase) {
-	handler := &photoHandler{photoUseCase}
+func NewPhotoHandler(routers *gin.Engine, photoUseCase domain.PhotoUseCase, likeUseCase domain.LikeUseCase) {
+	handler := &photoHandler{photoUseCase, likeUseCase}
 
 	router := routers.Group("/photos")
 	{
-		router.Use(middleware.Authentication())
-		router.GET("", handler.Fetch)
-		router.POST("", handler.Store)
-		router.PUT("/:photoId", middleware.Authorization(handler.photoUseCase), handler.Update)
-		router.DELETE("/:photoId", middleware.Authorization(handler.photoUseCase), handler.Delete)
+		router.GET("", middleware.Authentication(domain.ScopePhotosRead), handler.Fetch)
+		router.POST("", middleware.Authentication(domain.ScopePhotosWrite), middleware.VerifiedEmail(), handler.Store)
+		router.POST("/upload", middleware.Authentication(domain.ScopePhotosWrite), middleware.VerifiedEmail(), handler.Upload)
+		router.GET("/:photoId", middleware.Authentication(domain.ScopePhotosRead), handler.GetByID)
+		router.PUT("/:photoId", middleware.Authentication(domain.ScopePhotosWrite), middleware.Authorization[domain.Photo](handler.photoUseCase, "photoId", policy.ResourcePhoto, policy.ActionUpdate), handler.Update)
+		router.PUT("/:photoId/location", middleware.Authentication(domain.ScopePhotosWrite), middleware.Authorization[domain.Photo](handler.photoUseCase, "photoId", policy.ResourcePhoto, policy.ActionUpdate), handler.PublishLocation)
+		router.DELETE("/:photoId", middleware.Authentication(domain.ScopePhotosWrite), middleware.Authorization[domain.Photo](handler.photoUseCase, "photoId", policy.ResourcePhoto, policy.ActionDelete), handler.Delete)
 	}
 }
 
 // Fetch godoc
 // @Summary    	Fetch all photos
-// @Description	Get all photos with authentication user
+// @Description	Get a page of photos, newest first, with authentication user. Uploaded photos list smaller variants, narrowest first, once they have been rendered
 // @Tags        photos
 // @Accept      json
 // @Produce     json
+// @Param       user_id					query			string	false	"Only photos of this user"
+// @Param       cursor					query			string	false	"Cursor of the page, the next_cursor of the previous one"
+// @Param       limit						query			int			false	"Items per page, 20 by default and at most 100"
+// @Param       sort						query			string	false	"newest (default) or oldest first"
+// @Param       created_after		query			string	false	"Only items created after this RFC 3339 time"
+// @Param       created_before	query			string	false	"Only items created before this RFC 3339 time"
 // @Success     200			{object}	utils.ResponseDataFetchedPhoto
 // @Failure     400			{object}	utils.ResponseMessage
 // @Failure     401			{object}	utils.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /photos	[get]
 func (handler *photoHandler) Fetch(ctx *gin.Context) {
-	var (
-		photos []domain.Photo
-		err    error
-	)
+	var photos []domain.Photo
 
-	if err = handler.photoUseCase.Fetch(ctx.Request.Context(), &photos); err != nil {
+	query, err := pagination.Parse(ctx.Request.URL.Query())
+
+	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -54,6 +71,35 @@ func (handler *photoHandler) Fetch(ctx *gin.Context) {
 		return
 	}
 
+	next, err := handler.photoUseCase.Fetch(ctx.Request.Context(), &photos, query)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+	photoIDs := []string{}
+
+	for _, photo := range photos {
+		photoIDs = append(photoIDs, photo.ID)
+	}
+
+	liked, err := handler.likeUseCase.LikedBy(ctx.Request.Context(), domain.LikeTargetPhoto, userID, photoIDs)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
 	fetchedPhotos := []*utils.FetchedPhoto{}
 
 	for _, photo := range photos {
@@ -69,12 +115,106 @@ func (handler *photoHandler) Fetch(ctx *gin.Context) {
 				Email:    photo.User.Email,
 				Username: photo.User.Username,
 			},
+			Variants:  photoVariants(photo),
+			Metadata:  photoMetadata(photo, userID),
+			LikeCount: photo.LikeCount,
+			LikedByMe: liked[photo.ID],
+		})
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponsePage{
+		Status:     "success",
+		Data:       fetchedPhotos,
+		NextCursor: next,
+	})
+}
+
+// GetByID godoc
+// @Summary    	Fetch a photo
+// @Description	Get a photo by id with its owner, variants, how many comments it has and the first 20 top-level ones, oldest first, each with its reply count. The rest follow from GET /photos/{id}/comments with next_comments_cursor as the cursor
+// @Tags        photos
+// @Accept      json
+// @Produce     json
+// @Param       id		path			string	true	"Photo ID"
+// @Success     200		{object}	utils.ResponseDataPhotoDetail
+// @Failure     401		{object}	utils.ResponseMessage
+// @Failure     404		{object}	utils.ResponseMessage
+// @Failure     500		{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /photos/{id}	[get]
+func (handler *photoHandler) GetByID(ctx *gin.Context) {
+	var detail domain.PhotoDetail
+
+	photoID := ctx.Param("photoId")
+
+	err := handler.photoUseCase.GetDetailByID(ctx.Request.Context(), &detail, photoID)
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourcePhoto, photoID)
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+	photo := detail.Photo
+
+	likedPhotos, err := handler.likeUseCase.LikedBy(ctx.Request.Context(), domain.LikeTargetPhoto, userID, []string{photo.ID})
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
 		})
+
+		return
+	}
+
+	commentIDs := []string{}
+
+	for _, comment := range detail.Comments {
+		commentIDs = append(commentIDs, comment.ID)
+	}
+
+	likedComments, err := handler.likeUseCase.LikedBy(ctx.Request.Context(), domain.LikeTargetComment, userID, commentIDs)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
 	}
 
 	ctx.JSON(http.StatusOK, helpers.ResponseData{
 		Status: "success",
-		Data:   fetchedPhotos,
+		Data: utils.PhotoDetail{
+			ID:                 photo.ID,
+			Title:              photo.Title,
+			Caption:            photo.Caption,
+			PhotoUrl:           photo.PhotoUrl,
+			UserID:             photo.UserID,
+			CreatedAt:          photo.CreatedAt,
+			UpdatedAt:          photo.UpdatedAt,
+			User:               owner(photo.User),
+			Variants:           photoVariants(photo),
+			Metadata:           photoMetadata(photo, userID),
+			LikeCount:          photo.LikeCount,
+			LikedByMe:          likedPhotos[photo.ID],
+			CommentCount:       detail.CommentCount,
+			Comments:           commentUtils.NewPhotoComments(detail.Comments, likedComments),
+			NextCommentsCursor: detail.NextCursor,
+		},
 	})
 }
 
@@ -88,7 +228,9 @@ func (handler *photoHandler) Fetch(ctx *gin.Context) {
 // @Success     201			{object}  utils.ResponseDataAddedPhoto
 // @Failure     400			{object}	utils.ResponseMessage
 // @Failure     401			{object}	utils.ResponseMessage
+// @Failure     403			{object}	utils.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /photos	[post]
 func (handler *photoHandler) Store(ctx *gin.Context) {
 	var (
@@ -96,8 +238,7 @@ func (handler *photoHandler) Store(ctx *gin.Context) {
 		err   error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	userID := middleware.CurrentPrincipal(ctx).UserID
 
 	if err = ctx.ShouldBindJSON(&photo); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -132,6 +273,114 @@ func (handler *photoHandler) Store(ctx *gin.Context) {
 	})
 }
 
+// Upload godoc
+// @Summary    	Upload a photo
+// @Description	Upload a JPEG, PNG, GIF or WebP image of at most 10 MB and store it as a photo of the authenticated user. The image is turned upright and stored without its EXIF data, camera details and where it was taken are kept with the photo. The location stays private until published
+// @Tags        photos
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       photo		formData	file		true	"Image"
+// @Param       title		formData	string	true	"Title"
+// @Param       caption	formData	string	false	"Caption"
+// @Success     201			{object}  utils.ResponseDataAddedPhoto
+// @Failure     400			{object}	utils.ResponseMessage
+// @Failure     401			{object}	utils.ResponseMessage
+// @Failure     403			{object}	utils.ResponseMessage
+// @Failure     413			{object}	utils.ResponseMessage
+// @Failure     415			{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /photos/upload	[post]
+func (handler *photoHandler) Upload(ctx *gin.Context) {
+	var (
+		uploadPhoto utils.UploadPhoto
+		err         error
+	)
+
+	// Without a cap ShouldBind would spool a body of any size to disk before the
+	// usecase ever gets to look at it.
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, photoBodyMaxBytes)
+
+	if err = ctx.ShouldBind(&uploadPhoto); err != nil {
+		var maxBytesError *http.MaxBytesError
+
+		if errors.As(err, &maxBytesError) {
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: domain.ErrImageTooLarge.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	file, err := uploadPhoto.Photo.Open()
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	defer file.Close()
+
+	photo := domain.Photo{
+		Title:   uploadPhoto.Title,
+		Caption: uploadPhoto.Caption,
+		UserID:  middleware.CurrentPrincipal(ctx).UserID,
+	}
+
+	if err = handler.photoUseCase.Upload(ctx.Request.Context(), &photo, file); err != nil {
+		if errors.Is(err, domain.ErrImageTooLarge) {
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		if errors.Is(err, domain.ErrImageUnsupported) {
+			ctx.AbortWithStatusJSON(http.StatusUnsupportedMediaType, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.ResponseData{
+		Status: "success",
+		Data: utils.AddedPhoto{
+			ID:        photo.ID,
+			Title:     photo.Title,
+			Caption:   photo.Caption,
+			PhotoUrl:  photo.PhotoUrl,
+			UserID:    photo.UserID,
+			CreatedAt: photo.CreatedAt,
+			Metadata:  photoMetadata(photo, photo.UserID),
+		},
+	})
+}
+
 // Update godoc
 // @Summary     Update a photo
 // @Description	Update a photo by id with authentication user
@@ -143,8 +392,10 @@ func (handler *photoHandler) Store(ctx *gin.Context) {
 // @Success     200		{object}  utils.ResponseDataUpdatedPhoto
 // @Failure     400		{object}	utils.ResponseMessage
 // @Failure     401		{object}	utils.ResponseMessage
+// @Failure     403		{object}	utils.ResponseMessage
 // @Failure     404		{object}	utils.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /photos/{id}		[put]
 func (handler *photoHandler) Update(ctx *gin.Context) {
 	var (
@@ -167,9 +418,13 @@ func (handler *photoHandler) Update(ctx *gin.Context) {
 		PhotoUrl: photo.PhotoUrl,
 	}
 
-	photoID := ctx.Param("photoId")
+	if photo, err = handler.photoUseCase.Update(ctx.Request.Context(), middleware.Loaded[domain.Photo](ctx), updatedPhoto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourcePhoto, ctx.Param("photoId"))
+
+			return
+		}
 
-	if photo, err = handler.photoUseCase.Update(ctx.Request.Context(), updatedPhoto, photoID); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -191,9 +446,68 @@ func (handler *photoHandler) Update(ctx *gin.Context) {
 	})
 }
 
+// PublishLocation godoc
+// @Summary     Publish the location of a photo
+// @Description	Show or hide where an uploaded photo was taken, as read from its EXIF data. Locations are hidden until their owner publishes them
+// @Tags        photos
+// @Accept      json
+// @Produce     json
+// @Param       id		path      string									true	"Photo ID"
+// @Param       json	body			utils.PublishLocation		true	"Publish Location"
+// @Success     200		{object}  utils.ResponseDataPublishedLocation
+// @Failure     400		{object}	utils.ResponseMessage
+// @Failure     401		{object}	utils.ResponseMessage
+// @Failure     403		{object}	utils.ResponseMessage
+// @Failure     404		{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /photos/{id}/location		[put]
+func (handler *photoHandler) PublishLocation(ctx *gin.Context) {
+	var (
+		publishLocation utils.PublishLocation
+		err             error
+	)
+
+	if err = ctx.ShouldBindJSON(&publishLocation); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	photo := middleware.Loaded[domain.Photo](ctx)
+
+	if err = handler.photoUseCase.PublishLocation(ctx.Request.Context(), photo.ID, *publishLocation.Public); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourcePhoto, photo.ID)
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	photo.LocationPublic = *publishLocation.Public
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.PublishedLocation{
+			Public:   photo.LocationPublic,
+			Location: photoLocation(photo, ""),
+		},
+	})
+}
+
 // Delete godoc
 // @Summary     Delete a photo
-// @Description	Delete a photo by id with authentication user
+// @Description	Delete a photo by id with authentication user. Moderators and admins can delete any photo
 // @Tags        photos
 // @Accept      json
 // @Produce     json
@@ -201,13 +515,21 @@ func (handler *photoHandler) Update(ctx *gin.Context) {
 // @Success     200	{object}	utils.ResponseMessageDeletedPhoto
 // @Failure     400	{object}	utils.ResponseMessage
 // @Failure     401	{object}	utils.ResponseMessage
+// @Failure     403	{object}	utils.ResponseMessage
 // @Failure     404	{object}	utils.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /photos/{id}	[delete]
 func (handler *photoHandler) Delete(ctx *gin.Context) {
 	photoID := ctx.Param("photoId")
 
 	if err := handler.photoUseCase.Delete(ctx.Request.Context(), photoID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourcePhoto, photoID)
+
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -220,3 +542,63 @@ func (handler *photoHandler) Delete(ctx *gin.Context) {
 		"message": "your photo has been successfully deleted",
 	})
 }
+
+// owner summarizes the user a photo or comment belongs to.
+func owner(user *domain.User) *utils.Owner {
+	if user == nil {
+		return nil
+	}
+
+	return &utils.Owner{
+		ID:              user.ID,
+		Username:        user.Username,
+		DisplayName:     user.DisplayName,
+		ProfileImageUrl: user.ProfileImageUrl,
+	}
+}
+
+// photoVariants lists the rendered variants of photo, narrowest first.
+func photoVariants(photo domain.Photo) []utils.PhotoVariant {
+	variants := []utils.PhotoVariant{}
+
+	for _, photoVariant := range photo.Variants {
+		variants = append(variants, utils.PhotoVariant{
+			Width:  photoVariant.Width,
+			Height: photoVariant.Height,
+			Format: photoVariant.Format,
+			Url:    photoVariant.Url,
+		})
+	}
+
+	return variants
+}
+
+// photoMetadata describes what the EXIF data of an upload said about it. Where
+// it was taken is only told to viewerID when it is the owner or the location
+// was published.
+func photoMetadata(photo domain.Photo, viewerID string) *utils.PhotoMetadata {
+	if photo.StorageKey == "" {
+		return nil
+	}
+
+	return &utils.PhotoMetadata{
+		Width:       photo.Width,
+		Height:      photo.Height,
+		CameraMake:  photo.CameraMake,
+		CameraModel: photo.CameraModel,
+		LensModel:   photo.LensModel,
+		TakenAt:     photo.TakenAt,
+		Location:    photoLocation(photo, viewerID),
+	}
+}
+
+func photoLocation(photo domain.Photo, viewerID string) *utils.PhotoLocation {
+	if photo.Latitude == nil || photo.Longitude == nil || (!photo.LocationPublic && photo.UserID != viewerID) {
+		return nil
+	}
+
+	return &utils.PhotoLocation{
+		Latitude:  *photo.Latitude,
+		Longitude: *photo.Longitude,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"mygram-api/domain"
+	"mygram-api/pagination"
 	"time"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
@@ -18,18 +19,22 @@ func NewPhotoRepository(db *gorm.DB) *photoRepository {
 	return &photoRepository{db}
 }
 
-func (photoRepository *photoRepository) Fetch(ctx context.Context, photos *[]domain.Photo) (err error) {
+func (photoRepository *photoRepository) Fetch(ctx context.Context, photos *[]domain.Photo, query pagination.Query) (next string, err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	if err = photoRepository.db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
+	if err = photoRepository.db.WithContext(ctx).Scopes(query.Scope("photos")).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "username", "email")
+	}).Preload("Variants", func(db *gorm.DB) *gorm.DB {
+		return db.Order("width")
 	}).Find(&photos).Error; err != nil {
-		return err
+		return "", err
 	}
 
-	return
+	return pagination.Trim(query, photos, func(photo domain.Photo) pagination.Cursor {
+		return pagination.NewCursor(photo.CreatedAt, photo.ID)
+	}), nil
 }
 
 func (photoRepository *photoRepository) Store(ctx context.Context, photo *domain.Photo) (err error) {
@@ -60,18 +65,51 @@ func (photoRepository *photoRepository) GetByID(ctx context.Context, photo *doma
 	return
 }
 
-func (photoRepository *photoRepository) Update(ctx context.Context, photo domain.Photo, id string) (p domain.Photo, err error) {
+// GetDetailByID loads the photo with id along with its owner, variants, comment
+// count and first page of comments.
+func (photoRepository *photoRepository) GetDetailByID(ctx context.Context, detail *domain.PhotoDetail, id string) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	p = domain.Photo{}
+	owner := func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "display_name", "profile_image_url")
+	}
 
-	if err = photoRepository.db.WithContext(ctx).First(&p, &id).Error; err != nil {
-		return p, err
+	if err = photoRepository.db.WithContext(ctx).Preload("User", owner).Preload("Variants", func(db *gorm.DB) *gorm.DB {
+		return db.Order("width")
+	}).First(&detail.Photo, "id = ?", id).Error; err != nil {
+		return err
 	}
 
-	if err = photoRepository.db.WithContext(ctx).Model(&p).Updates(photo).Error; err != nil {
+	query := pagination.Query{Limit: pagination.DefaultLimit, Sort: pagination.SortOldest}
+
+	// Only top-level comments, replies are counted and fetched separately.
+	if err = photoRepository.db.WithContext(ctx).Model(&domain.Comment{}).Where("photo_id = ? AND parent_id IS NULL", id).Count(&detail.CommentCount).Error; err != nil {
+		return err
+	}
+
+	if err = photoRepository.db.WithContext(ctx).Scopes(domain.WithReplyCount).Where("comments.photo_id = ? AND comments.parent_id IS NULL", id).Scopes(query.Scope("comments")).Preload("User", owner).Find(&detail.Comments).Error; err != nil {
+		return err
+	}
+
+	detail.NextCursor = pagination.Trim(query, &detail.Comments, func(comment domain.Comment) pagination.Cursor {
+		return pagination.NewCursor(comment.CreatedAt, comment.ID)
+	})
+
+	return
+}
+
+// Update applies the non-zero fields of updatedPhoto to photo, which callers
+// have already loaded, and returns the result.
+func (photoRepository *photoRepository) Update(ctx context.Context, photo domain.Photo, updatedPhoto domain.Photo) (p domain.Photo, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	p = photo
+
+	if err = photoRepository.db.WithContext(ctx).Model(&p).Updates(updatedPhoto).Error; err != nil {
 		return p, err
 	}
 
@@ -93,3 +131,34 @@ func (photoRepository *photoRepository) Delete(ctx context.Context, id string) (
 
 	return
 }
+
+// CountByStorageKey tells how many photos share the upload stored under key.
+func (photoRepository *photoRepository) CountByStorageKey(ctx context.Context, key string) (count int64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = photoRepository.db.WithContext(ctx).Model(&domain.Photo{}).Where("storage_key = ?", key).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return
+}
+
+func (photoRepository *photoRepository) UpdateLocationPublic(ctx context.Context, id string, public bool) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := photoRepository.db.WithContext(ctx).Model(&domain.Photo{ID: id}).UpdateColumn("location_public", public)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+type photoVariantRepository struct {
+	db *gorm.DB
+}
+
+func NewPhotoVariantRepository(db *gorm.DB) *photoVariantRepository {
+	return &photoVariantRepository{db}
+}
+
+func (photoVariantRepository *photoVariantRepository) Store(ctx context.Context, photoVariant *domain.PhotoVariant) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	photoVariant.ID = fmt.Sprintf("variant-%s", ID)
+
+	if err = photoVariantRepository.db.WithContext(ctx).Create(photoVariant).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (photoVariantRepository *photoVariantRepository) FetchByPhotoID(ctx context.Context, photoVariants *[]domain.PhotoVariant, photoID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = photoVariantRepository.db.WithContext(ctx).Where("photo_id = ?", photoID).Order("width").Find(photoVariants).Error; err != nil {
+		return err
+	}
+
+	return
+}
@@ -1,28 +1,118 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
 	"mygram-api/domain"
+	"mygram-api/imaging"
+	"mygram-api/pagination"
 )
 
+// photoMaxBytes is the largest photo upload accepted.
+const photoMaxBytes = 10 << 20
+
+var photoExtensions = map[string]string{
+	"jpeg": "jpg",
+	"png":  "png",
+	"gif":  "gif",
+	"webp": "webp",
+}
+
 type photoUseCase struct {
-	photoRepository domain.PhotoRepository
+	photoRepository        domain.PhotoRepository
+	photoVariantRepository domain.PhotoVariantRepository
+	blobStore              domain.BlobStore
+	variantGenerator       domain.PhotoVariantGenerator
 }
 
-func NewPhotoUseCase(photoRepository domain.PhotoRepository) *photoUseCase {
-	return &photoUseCase{photoRepository}
+func NewPhotoUseCase(photoRepository domain.PhotoRepository, photoVariantRepository domain.PhotoVariantRepository, blobStore domain.BlobStore, variantGenerator domain.PhotoVariantGenerator) *photoUseCase {
+	return &photoUseCase{photoRepository, photoVariantRepository, blobStore, variantGenerator}
 }
 
-func (photoUseCase *photoUseCase) Fetch(ctx context.Context, photos *[]domain.Photo) (err error) {
-	if err = photoUseCase.photoRepository.Fetch(ctx, photos); err != nil {
+func (photoUseCase *photoUseCase) Fetch(ctx context.Context, photos *[]domain.Photo, query pagination.Query) (next string, err error) {
+	if next, err = photoUseCase.photoRepository.Fetch(ctx, photos, query); err != nil {
+		return "", err
+	}
+
+	return next, nil
+}
+
+func (photoUseCase *photoUseCase) Store(ctx context.Context, photo *domain.Photo) (err error) {
+	if err = photoUseCase.photoRepository.Store(ctx, photo); err != nil {
 		return err
 	}
 
 	return
 }
 
-func (photoUseCase *photoUseCase) Store(ctx context.Context, photo *domain.Photo) (err error) {
+// Upload stores the image read from body and then photo, pointing at it. Images
+// are kept under the hash of their content, so the same file uploaded twice is
+// stored once. Its variants are rendered after Upload returns.
+func (photoUseCase *photoUseCase) Upload(ctx context.Context, photo *domain.Photo, body io.Reader) (err error) {
+	data, err := io.ReadAll(io.LimitReader(body, photoMaxBytes+1))
+
+	if err != nil {
+		return err
+	}
+
+	if len(data) > photoMaxBytes {
+		return domain.ErrImageTooLarge
+	}
+
+	if _, err = imaging.Inspect(data); err != nil {
+		return err
+	}
+
+	metadata := imaging.Extract(data)
+
+	// What is stored is upright and has no metadata left, GPS included, so the
+	// location is only ever published through the photo's own fields.
+	if data, err = imaging.Normalize(data, metadata.Orientation); err != nil {
+		return err
+	}
+
+	info, err := imaging.Inspect(data)
+
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	key := fmt.Sprintf("photos/%s.%s", hex.EncodeToString(sum[:]), photoExtensions[info.Format])
+
+	if err = photoUseCase.blobStore.Put(ctx, key, "image/"+info.Format, bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	photo.PhotoUrl = photoUseCase.blobStore.URL(key)
+	photo.StorageKey = key
+	photo.Width, photo.Height = info.Width, info.Height
+	photo.CameraMake = metadata.CameraMake
+	photo.CameraModel = metadata.CameraModel
+	photo.LensModel = metadata.LensModel
+	photo.TakenAt = metadata.TakenAt
+	photo.Latitude, photo.Longitude = metadata.Latitude, metadata.Longitude
+
 	if err = photoUseCase.photoRepository.Store(ctx, photo); err != nil {
+		photoUseCase.release(ctx, key, nil)
+
+		return err
+	}
+
+	photoUseCase.variantGenerator.Enqueue(*photo, data)
+
+	return
+}
+
+// PublishLocation shows or hides where the photo was taken, which is hidden
+// until its owner decides otherwise.
+func (photoUseCase *photoUseCase) PublishLocation(ctx context.Context, id string, public bool) (err error) {
+	if err = photoUseCase.photoRepository.UpdateLocationPublic(ctx, id, public); err != nil {
 		return err
 	}
 
@@ -37,8 +127,16 @@ func (photoUseCase *photoUseCase) GetByID(ctx context.Context, photo *domain.Pho
 	return
 }
 
-func (photoUseCase *photoUseCase) Update(ctx context.Context, photo domain.Photo, id string) (p domain.Photo, err error) {
-	if p, err = photoUseCase.photoRepository.Update(ctx, photo, id); err != nil {
+func (photoUseCase *photoUseCase) GetDetailByID(ctx context.Context, detail *domain.PhotoDetail, id string) (err error) {
+	if err = photoUseCase.photoRepository.GetDetailByID(ctx, detail, id); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (photoUseCase *photoUseCase) Update(ctx context.Context, photo domain.Photo, updatedPhoto domain.Photo) (p domain.Photo, err error) {
+	if p, err = photoUseCase.photoRepository.Update(ctx, photo, updatedPhoto); err != nil {
 		return p, err
 	}
 
@@ -46,9 +144,56 @@ func (photoUseCase *photoUseCase) Update(ctx context.Context, photo domain.Photo
 }
 
 func (photoUseCase *photoUseCase) Delete(ctx context.Context, id string) (err error) {
+	photo := domain.Photo{}
+
+	if err = photoUseCase.photoRepository.GetByID(ctx, &photo, id); err != nil {
+		return err
+	}
+
+	photoVariants := []domain.PhotoVariant{}
+
+	if photo.StorageKey != "" {
+		if err = photoUseCase.photoVariantRepository.FetchByPhotoID(ctx, &photoVariants, id); err != nil {
+			return err
+		}
+	}
+
 	if err = photoUseCase.photoRepository.Delete(ctx, id); err != nil {
 		return err
 	}
 
+	if photo.StorageKey != "" {
+		photoUseCase.release(ctx, photo.StorageKey, photoVariants)
+	}
+
 	return
 }
+
+// release removes the upload stored under key and its variants unless another
+// photo still shows it. A blob left behind only costs space, so failures are
+// logged.
+func (photoUseCase *photoUseCase) release(ctx context.Context, key string, photoVariants []domain.PhotoVariant) {
+	count, err := photoUseCase.photoRepository.CountByStorageKey(ctx, key)
+
+	if err != nil {
+		log.Printf("removing photo file %s failed: %s", key, err)
+
+		return
+	}
+
+	if count > 0 {
+		return
+	}
+
+	keys := []string{key}
+
+	for _, photoVariant := range photoVariants {
+		keys = append(keys, photoVariant.StorageKey)
+	}
+
+	for _, key := range keys {
+		if err = photoUseCase.blobStore.Delete(ctx, key); err != nil {
+			log.Printf("removing photo file %s failed: %s", key, err)
+		}
+	}
+}
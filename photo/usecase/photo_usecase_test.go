@@ -1,10 +1,19 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
 	"mygram-api/domain"
 	"mygram-api/domain/mocks"
+	"mygram-api/pagination"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +22,7 @@ import (
 	"github.com/asaskevich/govalidator"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func TestFetch(t *testing.T) {
@@ -29,14 +39,27 @@ func TestFetch(t *testing.T) {
 	mockPhotos = append(mockPhotos, mockPhoto)
 
 	mockPhotoRepository := new(mocks.PhotoRepository)
-	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocks.PhotoVariantRepository), new(mocks.BlobStore), new(mocks.PhotoVariantGenerator))
 
 	t.Run("fetch all photos correctly", func(t *testing.T) {
-		mockPhotoRepository.On("Fetch", mock.Anything, mock.AnythingOfType("*[]domain.Photo")).Return(nil).Once()
+		mockPhotoRepository.On("Fetch", mock.Anything, mock.AnythingOfType("*[]domain.Photo"), mock.AnythingOfType("pagination.Query")).Return("", nil).Once()
 
-		err := photoUseCase.Fetch(context.Background(), &mockPhotos)
+		next, err := photoUseCase.Fetch(context.Background(), &mockPhotos, pagination.Query{Limit: 20})
 
 		assert.NoError(t, err)
+		assert.Empty(t, next)
+	})
+
+	t.Run("fetch photos of a user", func(t *testing.T) {
+		query := pagination.Query{Limit: 20, Sort: pagination.SortOldest, UserID: "user-123"}
+
+		mockPhotoRepository.On("Fetch", mock.Anything, mock.AnythingOfType("*[]domain.Photo"), query).Return("next-cursor", nil).Once()
+
+		next, err := photoUseCase.Fetch(context.Background(), &mockPhotos, query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "next-cursor", next)
+		mockPhotoRepository.AssertExpectations(t)
 	})
 }
 
@@ -52,7 +75,7 @@ func TestStore(t *testing.T) {
 	}
 
 	mockPhotoRepository := new(mocks.PhotoRepository)
-	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocks.PhotoVariantRepository), new(mocks.BlobStore), new(mocks.PhotoVariantGenerator))
 
 	t.Run("add photo correctly", func(t *testing.T) {
 		tempMockAddPhoto := domain.Photo{
@@ -169,7 +192,7 @@ func TestGetBy(t *testing.T) {
 	}
 
 	mockPhotoRepository := new(mocks.PhotoRepository)
-	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocks.PhotoVariantRepository), new(mocks.BlobStore), new(mocks.PhotoVariantGenerator))
 
 	t.Run("get by id correctly", func(t *testing.T) {
 		mockPhotoID := "photo-123"
@@ -196,6 +219,53 @@ func TestGetBy(t *testing.T) {
 	})
 }
 
+func TestGetDetailByID(t *testing.T) {
+	now := time.Now()
+
+	mockPhotoDetail := domain.PhotoDetail{
+		Photo: domain.Photo{
+			ID:        "photo-123",
+			Title:     "A Title",
+			PhotoUrl:  "https://www.example.com/image.jpg",
+			UserID:    "user-123",
+			CreatedAt: &now,
+			User:      &domain.User{ID: "user-123", Username: "johndoe"},
+		},
+		CommentCount: 1,
+		Comments: []domain.Comment{
+			{ID: "comment-123", UserID: "user-234", PhotoID: "photo-123", Message: "A message", CreatedAt: &now},
+		},
+	}
+
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocks.PhotoVariantRepository), new(mocks.BlobStore), new(mocks.PhotoVariantGenerator))
+
+	t.Run("get detail by id correctly", func(t *testing.T) {
+		var detail domain.PhotoDetail
+
+		mockPhotoRepository.On("GetDetailByID", mock.Anything, mock.AnythingOfType("*domain.PhotoDetail"), "photo-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.PhotoDetail) = mockPhotoDetail
+		}).Return(nil).Once()
+
+		err := photoUseCase.GetDetailByID(context.Background(), &detail, "photo-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockPhotoDetail, detail)
+		mockPhotoRepository.AssertExpectations(t)
+	})
+
+	t.Run("get detail by id with not found photo", func(t *testing.T) {
+		var detail domain.PhotoDetail
+
+		mockPhotoRepository.On("GetDetailByID", mock.Anything, mock.AnythingOfType("*domain.PhotoDetail"), "photo-234").Return(gorm.ErrRecordNotFound).Once()
+
+		err := photoUseCase.GetDetailByID(context.Background(), &detail, "photo-234")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockPhotoRepository.AssertExpectations(t)
+	})
+}
+
 func TestUpdate(t *testing.T) {
 	now := time.Now()
 	mockUpdatedPhoto := domain.Photo{
@@ -208,24 +278,24 @@ func TestUpdate(t *testing.T) {
 	}
 
 	mockPhotoRepository := new(mocks.PhotoRepository)
-	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocks.PhotoVariantRepository), new(mocks.BlobStore), new(mocks.PhotoVariantGenerator))
 
 	t.Run("update photo correctly", func(t *testing.T) {
-		tempMockPhotoID := "photo-123"
+		tempMockPhoto := domain.Photo{ID: "photo-123", UserID: "user-123"}
 		tempMockUpdatePhoto := domain.Photo{
 			Title:    "A New Title",
 			Caption:  "A new caption",
 			PhotoUrl: "https://www.example.com/new-image.jpg",
 		}
 
-		mockPhotoRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Photo"), mock.AnythingOfType("string")).Return(mockUpdatedPhoto, nil).Once()
+		mockPhotoRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Photo"), mock.AnythingOfType("domain.Photo")).Return(mockUpdatedPhoto, nil).Once()
 
-		photo, err := photoUseCase.Update(context.Background(), tempMockUpdatePhoto, tempMockPhotoID)
+		photo, err := photoUseCase.Update(context.Background(), tempMockPhoto, tempMockUpdatePhoto)
 
 		assert.NoError(t, err)
 
 		tempMockUpdatedPhoto := domain.Photo{
-			ID:        tempMockPhotoID,
+			ID:        tempMockPhoto.ID,
 			Title:     tempMockUpdatePhoto.Title,
 			Caption:   tempMockUpdatePhoto.Caption,
 			PhotoUrl:  tempMockUpdatePhoto.PhotoUrl,
@@ -244,21 +314,21 @@ func TestUpdate(t *testing.T) {
 	})
 
 	t.Run("update photo with empty title", func(t *testing.T) {
-		tempMockPhotoID := "photo-123"
+		tempMockPhoto := domain.Photo{ID: "photo-123", UserID: "user-123"}
 		tempMockUpdatePhoto := domain.Photo{
 			Title:    "",
 			Caption:  "A new caption",
 			PhotoUrl: "https://www.example.com/new-image.jpg",
 		}
 
-		mockPhotoRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Photo"), mock.AnythingOfType("string")).Return(mockUpdatedPhoto, nil).Once()
+		mockPhotoRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Photo"), mock.AnythingOfType("domain.Photo")).Return(mockUpdatedPhoto, nil).Once()
 
-		photo, err := photoUseCase.Update(context.Background(), tempMockUpdatePhoto, tempMockPhotoID)
+		photo, err := photoUseCase.Update(context.Background(), tempMockPhoto, tempMockUpdatePhoto)
 
 		assert.NoError(t, err)
 
 		tempMockUpdatedPhoto := domain.Photo{
-			ID:        tempMockPhotoID,
+			ID:        tempMockPhoto.ID,
 			Title:     tempMockUpdatePhoto.Title,
 			Caption:   tempMockUpdatePhoto.Caption,
 			PhotoUrl:  tempMockUpdatePhoto.PhotoUrl,
@@ -277,21 +347,21 @@ func TestUpdate(t *testing.T) {
 	})
 
 	t.Run("update photo with empty photo url", func(t *testing.T) {
-		tempMockPhotoID := "photo-123"
+		tempMockPhoto := domain.Photo{ID: "photo-123", UserID: "user-123"}
 		tempMockUpdatePhoto := domain.Photo{
 			Title:    "A New Title",
 			Caption:  "A new caption",
 			PhotoUrl: "",
 		}
 
-		mockPhotoRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Photo"), mock.AnythingOfType("string")).Return(mockUpdatedPhoto, nil).Once()
+		mockPhotoRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Photo"), mock.AnythingOfType("domain.Photo")).Return(mockUpdatedPhoto, nil).Once()
 
-		photo, err := photoUseCase.Update(context.Background(), tempMockUpdatePhoto, tempMockPhotoID)
+		photo, err := photoUseCase.Update(context.Background(), tempMockPhoto, tempMockUpdatePhoto)
 
 		assert.NoError(t, err)
 
 		tempMockUpdatedPhoto := domain.Photo{
-			ID:        tempMockPhotoID,
+			ID:        tempMockPhoto.ID,
 			Title:     tempMockUpdatePhoto.Title,
 			Caption:   tempMockUpdatePhoto.Caption,
 			PhotoUrl:  tempMockUpdatePhoto.PhotoUrl,
@@ -310,21 +380,21 @@ func TestUpdate(t *testing.T) {
 	})
 
 	t.Run("update photo with empty title and photo url", func(t *testing.T) {
-		tempMockPhotoID := "photo-123"
+		tempMockPhoto := domain.Photo{ID: "photo-123", UserID: "user-123"}
 		tempMockUpdatePhoto := domain.Photo{
 			Title:    "",
 			Caption:  "A new caption",
 			PhotoUrl: "",
 		}
 
-		mockPhotoRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Photo"), mock.AnythingOfType("string")).Return(mockUpdatedPhoto, nil).Once()
+		mockPhotoRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Photo"), mock.AnythingOfType("domain.Photo")).Return(mockUpdatedPhoto, nil).Once()
 
-		photo, err := photoUseCase.Update(context.Background(), tempMockUpdatePhoto, tempMockPhotoID)
+		photo, err := photoUseCase.Update(context.Background(), tempMockPhoto, tempMockUpdatePhoto)
 
 		assert.NoError(t, err)
 
 		tempMockUpdatedPhoto := domain.Photo{
-			ID:        tempMockPhotoID,
+			ID:        tempMockPhoto.ID,
 			Title:     tempMockUpdatePhoto.Title,
 			Caption:   tempMockUpdatePhoto.Caption,
 			PhotoUrl:  tempMockUpdatePhoto.PhotoUrl,
@@ -343,6 +413,128 @@ func TestUpdate(t *testing.T) {
 	})
 }
 
+func TestUpload(t *testing.T) {
+	data := bytes.Buffer{}
+
+	png.Encode(&data, image.NewRGBA(image.Rect(0, 0, 8, 8)))
+
+	sum := sha256.Sum256(data.Bytes())
+	key := "photos/" + hex.EncodeToString(sum[:]) + ".png"
+
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	mockBlobStore := new(mocks.BlobStore)
+	mockVariantGenerator := new(mocks.PhotoVariantGenerator)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocks.PhotoVariantRepository), mockBlobStore, mockVariantGenerator)
+
+	mockBlobStore.On("URL", key).Return("/media/" + key)
+
+	t.Run("upload photo correctly", func(t *testing.T) {
+		photo := domain.Photo{Title: "A Title", UserID: "user-123"}
+
+		mockBlobStore.On("Put", mock.Anything, key, "image/png", mock.Anything).Return(nil).Once()
+		mockPhotoRepository.On("Store", mock.Anything, &photo).Return(nil).Once()
+		mockVariantGenerator.On("Enqueue", mock.MatchedBy(func(photo domain.Photo) bool { return photo.StorageKey == key }), data.Bytes()).Once()
+
+		err := photoUseCase.Upload(context.Background(), &photo, bytes.NewReader(data.Bytes()))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/media/"+key, photo.PhotoUrl)
+		assert.Equal(t, key, photo.StorageKey)
+		mockBlobStore.AssertExpectations(t)
+		mockPhotoRepository.AssertExpectations(t)
+		mockVariantGenerator.AssertExpectations(t)
+	})
+
+	t.Run("upload photo with metadata stores it without", func(t *testing.T) {
+		encoded := bytes.Buffer{}
+
+		jpeg.Encode(&encoded, image.NewGray(image.Rect(0, 0, 8, 4)), nil)
+
+		clean := encoded.Bytes()
+		withComment := append(append([]byte{}, clean[:2]...), "\xff\xfe\x00\x08secret"...)
+		withComment = append(withComment, clean[2:]...)
+		sum := sha256.Sum256(clean)
+		jpegKey := "photos/" + hex.EncodeToString(sum[:]) + ".jpg"
+		photo := domain.Photo{Title: "A Title", UserID: "user-123"}
+
+		mockBlobStore.On("Put", mock.Anything, jpegKey, "image/jpeg", mock.MatchedBy(func(body io.Reader) bool {
+			stored, _ := io.ReadAll(body)
+
+			return bytes.Equal(clean, stored)
+		})).Return(nil).Once()
+		mockBlobStore.On("URL", jpegKey).Return("/media/" + jpegKey).Once()
+		mockPhotoRepository.On("Store", mock.Anything, &photo).Return(nil).Once()
+		mockVariantGenerator.On("Enqueue", mock.AnythingOfType("domain.Photo"), clean).Once()
+
+		err := photoUseCase.Upload(context.Background(), &photo, bytes.NewReader(withComment))
+
+		assert.NoError(t, err)
+		assert.Equal(t, jpegKey, photo.StorageKey)
+		assert.Equal(t, 8, photo.Width)
+		assert.Equal(t, 4, photo.Height)
+		assert.Nil(t, photo.Latitude)
+		mockBlobStore.AssertExpectations(t)
+		mockPhotoRepository.AssertExpectations(t)
+		mockVariantGenerator.AssertExpectations(t)
+	})
+
+	t.Run("upload photo that is not an image", func(t *testing.T) {
+		photo := domain.Photo{Title: "A Title", UserID: "user-123"}
+
+		err := photoUseCase.Upload(context.Background(), &photo, strings.NewReader("%PDF-1.7"))
+
+		assert.ErrorIs(t, err, domain.ErrImageUnsupported)
+		assert.Empty(t, photo.PhotoUrl)
+	})
+
+	t.Run("upload photo that is too large", func(t *testing.T) {
+		photo := domain.Photo{Title: "A Title", UserID: "user-123"}
+
+		err := photoUseCase.Upload(context.Background(), &photo, io.MultiReader(bytes.NewReader(data.Bytes()), bytes.NewReader(make([]byte, 10<<20))))
+
+		assert.ErrorIs(t, err, domain.ErrImageTooLarge)
+	})
+
+	t.Run("upload photo with failing repository releases the file", func(t *testing.T) {
+		photo := domain.Photo{Title: "A Title", UserID: "user-123"}
+
+		mockBlobStore.On("Put", mock.Anything, key, "image/png", mock.Anything).Return(nil).Once()
+		mockPhotoRepository.On("Store", mock.Anything, &photo).Return(errors.New("fail")).Once()
+		mockPhotoRepository.On("CountByStorageKey", mock.Anything, key).Return(int64(0), nil).Once()
+		mockBlobStore.On("Delete", mock.Anything, key).Return(nil).Once()
+
+		err := photoUseCase.Upload(context.Background(), &photo, bytes.NewReader(data.Bytes()))
+
+		assert.Error(t, err)
+		mockBlobStore.AssertExpectations(t)
+		mockPhotoRepository.AssertExpectations(t)
+		mockVariantGenerator.AssertNumberOfCalls(t, "Enqueue", 2)
+	})
+}
+
+func TestPublishLocation(t *testing.T) {
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocks.PhotoVariantRepository), new(mocks.BlobStore), new(mocks.PhotoVariantGenerator))
+
+	t.Run("publish location correctly", func(t *testing.T) {
+		mockPhotoRepository.On("UpdateLocationPublic", mock.Anything, "photo-123", true).Return(nil).Once()
+
+		err := photoUseCase.PublishLocation(context.Background(), "photo-123", true)
+
+		assert.NoError(t, err)
+		mockPhotoRepository.AssertExpectations(t)
+	})
+
+	t.Run("publish location of a photo that doesn't exist", func(t *testing.T) {
+		mockPhotoRepository.On("UpdateLocationPublic", mock.Anything, "photo-234", false).Return(errors.New("fail")).Once()
+
+		err := photoUseCase.PublishLocation(context.Background(), "photo-234", false)
+
+		assert.Error(t, err)
+		mockPhotoRepository.AssertExpectations(t)
+	})
+}
+
 func TestDelete(t *testing.T) {
 	mockPhoto := domain.Photo{
 		ID:       "photo-123",
@@ -353,9 +545,12 @@ func TestDelete(t *testing.T) {
 	}
 
 	mockPhotoRepository := new(mocks.PhotoRepository)
-	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository)
+	mockPhotoVariantRepository := new(mocks.PhotoVariantRepository)
+	mockBlobStore := new(mocks.BlobStore)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, mockPhotoVariantRepository, mockBlobStore, new(mocks.PhotoVariantGenerator))
 
 	t.Run("delete photo correctly", func(t *testing.T) {
+		mockPhotoRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Photo"), mockPhoto.ID).Return(nil).Once()
 		mockPhotoRepository.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
 
 		err := photoUseCase.Delete(context.Background(), mockPhoto.ID)
@@ -365,11 +560,35 @@ func TestDelete(t *testing.T) {
 	})
 
 	t.Run("delete photo with not found photo", func(t *testing.T) {
-		mockPhotoRepository.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(errors.New("fail")).Once()
+		mockPhotoRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-234").Return(errors.New("fail")).Once()
 
 		err := photoUseCase.Delete(context.Background(), "photo-234")
 
 		assert.Error(t, err)
 		mockPhotoRepository.AssertExpectations(t)
 	})
+
+	t.Run("delete uploaded photo removes its files once unused", func(t *testing.T) {
+		mockBlobStore.On("Delete", mock.Anything, "photos/abc.jpg").Return(nil).Once()
+		mockBlobStore.On("Delete", mock.Anything, "photos/abc-320w.jpg").Return(nil).Once()
+
+		for _, count := range []int64{1, 0} {
+			mockPhotoRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Photo"), mockPhoto.ID).Run(func(args mock.Arguments) {
+				*args.Get(1).(*domain.Photo) = domain.Photo{ID: mockPhoto.ID, StorageKey: "photos/abc.jpg"}
+			}).Return(nil).Once()
+			mockPhotoVariantRepository.On("FetchByPhotoID", mock.Anything, mock.AnythingOfType("*[]domain.PhotoVariant"), mockPhoto.ID).Run(func(args mock.Arguments) {
+				*args.Get(1).(*[]domain.PhotoVariant) = []domain.PhotoVariant{{PhotoID: mockPhoto.ID, Width: 320, StorageKey: "photos/abc-320w.jpg"}}
+			}).Return(nil).Once()
+			mockPhotoRepository.On("Delete", mock.Anything, mockPhoto.ID).Return(nil).Once()
+			mockPhotoRepository.On("CountByStorageKey", mock.Anything, "photos/abc.jpg").Return(count, nil).Once()
+
+			err := photoUseCase.Delete(context.Background(), mockPhoto.ID)
+
+			assert.NoError(t, err)
+		}
+
+		mockPhotoRepository.AssertExpectations(t)
+		mockPhotoVariantRepository.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"mygram-api/domain"
+	"mygram-api/imaging"
+	"path"
+	"strings"
+	"sync"
+)
+
+// variantQueueSize bounds the uploads waiting for their variants. Each one holds
+// its original image in memory, so the queue is kept short.
+const variantQueueSize = 32
+
+type variantJob struct {
+	photo domain.Photo
+	data  []byte
+}
+
+type variantGenerator struct {
+	photoRepository        domain.PhotoRepository
+	photoVariantRepository domain.PhotoVariantRepository
+	blobStore              domain.BlobStore
+	widths                 []int
+	jobs                   chan variantJob
+	waitGroup              sync.WaitGroup
+}
+
+// NewVariantGenerator starts workers goroutines rendering the variants of the
+// photos handed to Enqueue, one for each of widths narrower than the photo.
+func NewVariantGenerator(photoRepository domain.PhotoRepository, photoVariantRepository domain.PhotoVariantRepository, blobStore domain.BlobStore, widths []int, workers int) *variantGenerator {
+	variantGenerator := &variantGenerator{
+		photoRepository:        photoRepository,
+		photoVariantRepository: photoVariantRepository,
+		blobStore:              blobStore,
+		widths:                 widths,
+		jobs:                   make(chan variantJob, variantQueueSize),
+	}
+
+	for i := 0; i < workers; i++ {
+		variantGenerator.waitGroup.Add(1)
+
+		go variantGenerator.work()
+	}
+
+	return variantGenerator
+}
+
+// Enqueue never blocks the upload. When the workers are that far behind the
+// photo goes without variants and clients fall back to its PhotoUrl.
+func (variantGenerator *variantGenerator) Enqueue(photo domain.Photo, data []byte) {
+	select {
+	case variantGenerator.jobs <- variantJob{photo, data}:
+	default:
+		log.Printf("the variant queue is full, skipping photo %s", photo.ID)
+	}
+}
+
+// Close waits for the queued photos to be done and stops the workers.
+func (variantGenerator *variantGenerator) Close() {
+	close(variantGenerator.jobs)
+	variantGenerator.waitGroup.Wait()
+}
+
+func (variantGenerator *variantGenerator) work() {
+	defer variantGenerator.waitGroup.Done()
+
+	for job := range variantGenerator.jobs {
+		if err := variantGenerator.generate(context.Background(), job.photo, job.data); err != nil {
+			log.Printf("generating the variants of photo %s failed: %s", job.photo.ID, err)
+		}
+	}
+}
+
+func (variantGenerator *variantGenerator) generate(ctx context.Context, photo domain.Photo, data []byte) (err error) {
+	img, err := imaging.Decode(data)
+
+	if err != nil {
+		return err
+	}
+
+	// Variants are named after the original, which is stored under the hash of
+	// its content, so photos sharing an upload share its variants as well.
+	base := strings.TrimSuffix(photo.StorageKey, path.Ext(photo.StorageKey))
+	format, extension, encode := "png", "png", imaging.EncodePNG
+
+	if imaging.Opaque(img) {
+		format, extension, encode = "jpeg", "jpg", imaging.EncodeJPEG
+	}
+
+	for _, width := range variantGenerator.widths {
+		if width >= img.Bounds().Dx() {
+			continue
+		}
+
+		resized := imaging.Resize(img, width)
+		buffer := bytes.Buffer{}
+
+		if err = encode(&buffer, resized); err != nil {
+			return err
+		}
+
+		key := fmt.Sprintf("%s-%dw.%s", base, width, extension)
+
+		if err = variantGenerator.blobStore.Put(ctx, key, "image/"+format, &buffer); err != nil {
+			return err
+		}
+
+		photoVariant := domain.PhotoVariant{
+			PhotoID:    photo.ID,
+			Width:      width,
+			Height:     resized.Bounds().Dy(),
+			Format:     format,
+			Url:        variantGenerator.blobStore.URL(key),
+			StorageKey: key,
+		}
+
+		if err = variantGenerator.photoVariantRepository.Store(ctx, &photoVariant); err != nil {
+			variantGenerator.release(ctx, photo.StorageKey, key)
+
+			return err
+		}
+	}
+
+	return
+}
+
+// release removes the variant stored under key when the photo was deleted while
+// it was rendered and no other photo shows the same upload.
+func (variantGenerator *variantGenerator) release(ctx context.Context, storageKey string, key string) {
+	count, err := variantGenerator.photoRepository.CountByStorageKey(ctx, storageKey)
+
+	if err == nil && count == 0 {
+		err = variantGenerator.blobStore.Delete(ctx, key)
+	}
+
+	if err != nil {
+		log.Printf("removing photo variant %s failed: %s", key, err)
+	}
+}
@@ -0,0 +1,104 @@
+package usecase_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"mygram-api/domain"
+	"mygram-api/domain/mocks"
+	"testing"
+
+	photoUseCase "mygram-api/photo/usecase"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVariantGenerator(t *testing.T) {
+	photo := domain.Photo{ID: "photo-123", StorageKey: "photos/abc.jpg"}
+
+	jpegData := bytes.Buffer{}
+
+	jpeg.Encode(&jpegData, image.NewGray(image.Rect(0, 0, 1000, 500)), nil)
+
+	pngData := bytes.Buffer{}
+
+	png.Encode(&pngData, image.NewNRGBA(image.Rect(0, 0, 1000, 500)))
+
+	t.Run("generate variants narrower than the photo", func(t *testing.T) {
+		mockPhotoVariantRepository := new(mocks.PhotoVariantRepository)
+		mockBlobStore := new(mocks.BlobStore)
+		variantGenerator := photoUseCase.NewVariantGenerator(new(mocks.PhotoRepository), mockPhotoVariantRepository, mockBlobStore, []int{320, 640, 2000}, 2)
+
+		for _, width := range []int{320, 640} {
+			key := fmt.Sprintf("photos/abc-%dw.jpg", width)
+
+			mockBlobStore.On("Put", mock.Anything, key, "image/jpeg", mock.Anything).Return(nil).Once()
+			mockBlobStore.On("URL", key).Return("/media/" + key).Once()
+			mockPhotoVariantRepository.On("Store", mock.Anything, &domain.PhotoVariant{
+				PhotoID:    photo.ID,
+				Width:      width,
+				Height:     width / 2,
+				Format:     "jpeg",
+				Url:        "/media/" + key,
+				StorageKey: key,
+			}).Return(nil).Once()
+		}
+
+		variantGenerator.Enqueue(photo, jpegData.Bytes())
+		variantGenerator.Close()
+
+		mockBlobStore.AssertExpectations(t)
+		mockPhotoVariantRepository.AssertExpectations(t)
+	})
+
+	t.Run("generate variants of a transparent photo as png", func(t *testing.T) {
+		mockPhotoVariantRepository := new(mocks.PhotoVariantRepository)
+		mockBlobStore := new(mocks.BlobStore)
+		variantGenerator := photoUseCase.NewVariantGenerator(new(mocks.PhotoRepository), mockPhotoVariantRepository, mockBlobStore, []int{320}, 1)
+
+		mockBlobStore.On("Put", mock.Anything, "photos/abc-320w.png", "image/png", mock.Anything).Return(nil).Once()
+		mockBlobStore.On("URL", "photos/abc-320w.png").Return("/media/photos/abc-320w.png").Once()
+		mockPhotoVariantRepository.On("Store", mock.Anything, mock.MatchedBy(func(photoVariant *domain.PhotoVariant) bool {
+			return photoVariant.Format == "png"
+		})).Return(nil).Once()
+
+		variantGenerator.Enqueue(photo, pngData.Bytes())
+		variantGenerator.Close()
+
+		mockBlobStore.AssertExpectations(t)
+		mockPhotoVariantRepository.AssertExpectations(t)
+	})
+
+	t.Run("generate variants of a photo deleted meanwhile", func(t *testing.T) {
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		mockPhotoVariantRepository := new(mocks.PhotoVariantRepository)
+		mockBlobStore := new(mocks.BlobStore)
+		variantGenerator := photoUseCase.NewVariantGenerator(mockPhotoRepository, mockPhotoVariantRepository, mockBlobStore, []int{320, 640}, 1)
+
+		mockBlobStore.On("Put", mock.Anything, "photos/abc-320w.jpg", "image/jpeg", mock.Anything).Return(nil).Once()
+		mockBlobStore.On("URL", "photos/abc-320w.jpg").Return("/media/photos/abc-320w.jpg").Once()
+		mockPhotoVariantRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.PhotoVariant")).Return(errors.New("fail")).Once()
+		mockPhotoRepository.On("CountByStorageKey", mock.Anything, photo.StorageKey).Return(int64(0), nil).Once()
+		mockBlobStore.On("Delete", mock.Anything, "photos/abc-320w.jpg").Return(nil).Once()
+
+		variantGenerator.Enqueue(photo, jpegData.Bytes())
+		variantGenerator.Close()
+
+		mockBlobStore.AssertExpectations(t)
+		mockPhotoRepository.AssertExpectations(t)
+		mockPhotoVariantRepository.AssertExpectations(t)
+	})
+
+	t.Run("generate variants of an image that is not one", func(t *testing.T) {
+		mockBlobStore := new(mocks.BlobStore)
+		variantGenerator := photoUseCase.NewVariantGenerator(new(mocks.PhotoRepository), new(mocks.PhotoVariantRepository), mockBlobStore, []int{320}, 1)
+
+		variantGenerator.Enqueue(photo, []byte("%PDF-1.7"))
+		variantGenerator.Close()
+
+		mockBlobStore.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
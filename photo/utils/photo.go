@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"mime/multipart"
+	commentUtils "mygram-api/comment/utils"
 	"time"
 )
 
@@ -9,20 +11,77 @@ type User struct {
 	Username string `json:"username"`
 }
 
+type Owner struct {
+	ID              string `json:"id" example:"here is the generated user id"`
+	Username        string `json:"username" example:"johndoe"`
+	DisplayName     string `json:"display_name" example:"John Doe"`
+	ProfileImageUrl string `json:"profile_image_url" example:"https://www.example.com/image.jpg"`
+}
+
+type PhotoVariant struct {
+	Width  int    `json:"width" example:"320"`
+	Height int    `json:"height" example:"240"`
+	Format string `json:"format" example:"jpeg"`
+	Url    string `json:"url" example:"https://www.example.com/image-320w.jpg"`
+}
+
+type PhotoLocation struct {
+	Latitude  float64 `json:"latitude" example:"52.5163"`
+	Longitude float64 `json:"longitude" example:"13.3777"`
+}
+
+type PhotoMetadata struct {
+	Width       int            `json:"width,omitempty" example:"4032"`
+	Height      int            `json:"height,omitempty" example:"3024"`
+	CameraMake  string         `json:"camera_make,omitempty" example:"Apple"`
+	CameraModel string         `json:"camera_model,omitempty" example:"iPhone 13"`
+	LensModel   string         `json:"lens_model,omitempty" example:"iPhone 13 back camera 5.1mm f/1.6"`
+	TakenAt     *time.Time     `json:"taken_at,omitempty"`
+	Location    *PhotoLocation `json:"location,omitempty"`
+}
+
 type FetchedPhoto struct {
-	ID        string     `json:"id"`
-	Title     string     `json:"title,"`
-	Caption   string     `json:"caption"`
-	PhotoUrl  string     `json:"photo_url"`
-	UserID    string     `json:"user_id"`
-	CreatedAt *time.Time `json:"created_at"`
-	UpdatedAt *time.Time `json:"updated_at"`
-	User      *User      `json:"user"`
+	ID        string         `json:"id"`
+	Title     string         `json:"title,"`
+	Caption   string         `json:"caption"`
+	PhotoUrl  string         `json:"photo_url"`
+	UserID    string         `json:"user_id"`
+	CreatedAt *time.Time     `json:"created_at"`
+	UpdatedAt *time.Time     `json:"updated_at"`
+	User      *User          `json:"user"`
+	Variants  []PhotoVariant `json:"variants"`
+	Metadata  *PhotoMetadata `json:"metadata"`
+	LikeCount int64          `json:"like_count" example:"42"`
+	LikedByMe bool           `json:"liked_by_me" example:"false"`
 }
 
 type ResponseDataFetchedPhoto struct {
-	Status string         `json:"status" example:"success"`
-	Data   []FetchedPhoto `json:"data"`
+	Status     string         `json:"status" example:"success"`
+	Data       []FetchedPhoto `json:"data"`
+	NextCursor string         `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6InBob3RvLTEyMyJ9"`
+}
+
+type PhotoDetail struct {
+	ID                 string                      `json:"id"`
+	Title              string                      `json:"title"`
+	Caption            string                      `json:"caption"`
+	PhotoUrl           string                      `json:"photo_url"`
+	UserID             string                      `json:"user_id"`
+	CreatedAt          *time.Time                  `json:"created_at"`
+	UpdatedAt          *time.Time                  `json:"updated_at"`
+	User               *Owner                      `json:"user"`
+	Variants           []PhotoVariant              `json:"variants"`
+	Metadata           *PhotoMetadata              `json:"metadata"`
+	LikeCount          int64                       `json:"like_count" example:"42"`
+	LikedByMe          bool                        `json:"liked_by_me" example:"false"`
+	CommentCount       int64                       `json:"comment_count" example:"42"`
+	Comments           []commentUtils.PhotoComment `json:"comments"`
+	NextCommentsCursor string                      `json:"next_comments_cursor,omitempty" example:"eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6ImNvbW1lbnQtMTIzIn0"`
+}
+
+type ResponseDataPhotoDetail struct {
+	Status string      `json:"status" example:"success"`
+	Data   PhotoDetail `json:"data"`
 }
 
 type AddPhoto struct {
@@ -31,13 +90,20 @@ type AddPhoto struct {
 	PhotoUrl string `json:"photo_url" example:"https://www.example.com/image.jpg"`
 }
 
+type UploadPhoto struct {
+	Photo   *multipart.FileHeader `form:"photo" binding:"required"`
+	Title   string                `form:"title" binding:"required,max=50"`
+	Caption string                `form:"caption"`
+}
+
 type AddedPhoto struct {
-	ID        string     `json:"id"`
-	Title     string     `json:"title"`
-	Caption   string     `json:"caption"`
-	PhotoUrl  string     `json:"photo_url"`
-	UserID    string     `json:"user_id"`
-	CreatedAt *time.Time `json:"created_at"`
+	ID        string         `json:"id"`
+	Title     string         `json:"title"`
+	Caption   string         `json:"caption"`
+	PhotoUrl  string         `json:"photo_url"`
+	UserID    string         `json:"user_id"`
+	CreatedAt *time.Time     `json:"created_at"`
+	Metadata  *PhotoMetadata `json:"metadata,omitempty"`
 }
 
 type ResponseDataAddedPhoto struct {
@@ -65,6 +131,20 @@ type ResponseDataUpdatedPhoto struct {
 	Data   UpdatedPhoto `json:"data"`
 }
 
+type PublishLocation struct {
+	Public *bool `json:"public" binding:"required" example:"true"`
+}
+
+type PublishedLocation struct {
+	Public   bool           `json:"public" example:"true"`
+	Location *PhotoLocation `json:"location"`
+}
+
+type ResponseDataPublishedLocation struct {
+	Status string            `json:"status" example:"success"`
+	Data   PublishedLocation `json:"data"`
+}
+
 type ResponseMessageDeletedPhoto struct {
 	Status  string `json:"status" example:"success"`
 	Message string `json:"message" example:"your photo has been successfully deleted"`
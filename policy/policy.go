@@ -0,0 +1,106 @@
+// Package policy decides who may do what. Owners may change and remove what
+// they created, and roles grant the same on resources owned by anyone else.
+// Roles build on each other: an admin can do everything a moderator can.
+package policy
+
+import "mygram-api/domain"
+
+type Action string
+
+const (
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionManage Action = "manage"
+)
+
+type Resource string
+
+const (
+	ResourcePhoto       Resource = "photo"
+	ResourceComment     Resource = "comment"
+	ResourceSocialMedia Resource = "socialmedia"
+	ResourceUser        Resource = "user"
+)
+
+// Label is the name of the resource as it reads in messages.
+func (resource Resource) Label() string {
+	if resource == ResourceSocialMedia {
+		return "social media"
+	}
+
+	return string(resource)
+}
+
+// Subject is whoever is making the request.
+type Subject struct {
+	ID   string
+	Role domain.Role
+}
+
+type permission struct {
+	action   Action
+	resource Resource
+}
+
+var (
+	// ownerPermissions apply to a resource the subject owns, whatever their role.
+	ownerPermissions = []permission{
+		{ActionUpdate, ResourcePhoto},
+		{ActionDelete, ResourcePhoto},
+		{ActionUpdate, ResourceComment},
+		{ActionDelete, ResourceComment},
+		{ActionUpdate, ResourceSocialMedia},
+		{ActionDelete, ResourceSocialMedia},
+	}
+
+	// rolePermissions apply to every resource of a kind. Each role also inherits
+	// the permissions of the roles listed in inherits.
+	rolePermissions = map[domain.Role][]permission{
+		domain.RoleModerator: {
+			{ActionDelete, ResourcePhoto},
+			{ActionDelete, ResourceComment},
+		},
+		domain.RoleAdmin: {
+			{ActionManage, ResourceUser},
+		},
+	}
+
+	inherits = map[domain.Role]domain.Role{
+		domain.RoleAdmin:     domain.RoleModerator,
+		domain.RoleModerator: domain.RoleUser,
+	}
+)
+
+// SubjectFromPrincipal is the subject behind an authenticated principal.
+func SubjectFromPrincipal(principal domain.Principal) Subject {
+	return Subject{ID: principal.UserID, Role: principal.Role}
+}
+
+// Can reports whether subject may perform action on a resource owned by
+// ownerID. Pass an empty ownerID for actions that aren't about a single owned
+// resource, such as managing users.
+func Can(subject Subject, action Action, resource Resource, ownerID string) bool {
+	wanted := permission{action, resource}
+
+	if ownerID != "" && ownerID == subject.ID && contains(ownerPermissions, wanted) {
+		return true
+	}
+
+	for role := subject.Role; role != ""; role = inherits[role] {
+		if contains(rolePermissions[role], wanted) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(permissions []permission, wanted permission) bool {
+	for _, permission := range permissions {
+		if permission == wanted {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package policy_test
+
+import (
+	"mygram-api/domain"
+	"mygram-api/policy"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCan(t *testing.T) {
+	user := policy.Subject{ID: "user-123", Role: domain.RoleUser}
+	moderator := policy.Subject{ID: "user-234", Role: domain.RoleModerator}
+	admin := policy.Subject{ID: "user-345", Role: domain.RoleAdmin}
+
+	tests := []struct {
+		name     string
+		subject  policy.Subject
+		action   policy.Action
+		resource policy.Resource
+		ownerID  string
+		allowed  bool
+	}{
+		{"owner updates own photo", user, policy.ActionUpdate, policy.ResourcePhoto, "user-123", true},
+		{"owner deletes own social media", user, policy.ActionDelete, policy.ResourceSocialMedia, "user-123", true},
+		{"user updates someone else's photo", user, policy.ActionUpdate, policy.ResourcePhoto, "user-999", false},
+		{"user deletes someone else's comment", user, policy.ActionDelete, policy.ResourceComment, "user-999", false},
+		{"moderator deletes any photo", moderator, policy.ActionDelete, policy.ResourcePhoto, "user-999", true},
+		{"moderator deletes any comment", moderator, policy.ActionDelete, policy.ResourceComment, "user-999", true},
+		{"moderator updates someone else's comment", moderator, policy.ActionUpdate, policy.ResourceComment, "user-999", false},
+		{"moderator deletes someone else's social media", moderator, policy.ActionDelete, policy.ResourceSocialMedia, "user-999", false},
+		{"moderator manages users", moderator, policy.ActionManage, policy.ResourceUser, "", false},
+		{"admin deletes any comment", admin, policy.ActionDelete, policy.ResourceComment, "user-999", true},
+		{"admin manages users", admin, policy.ActionManage, policy.ResourceUser, "", true},
+		{"user manages users", user, policy.ActionManage, policy.ResourceUser, "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.allowed, policy.Can(test.subject, test.action, test.resource, test.ownerID))
+		})
+	}
+}
+
+func TestSubjectFromPrincipal(t *testing.T) {
+	t.Run("subject from principal", func(t *testing.T) {
+		subject := policy.SubjectFromPrincipal(domain.Principal{UserID: "user-123", Role: domain.RoleModerator, APIKeyID: "apikey-123"})
+
+		assert.Equal(t, policy.Subject{ID: "user-123", Role: domain.RoleModerator}, subject)
+	})
+}
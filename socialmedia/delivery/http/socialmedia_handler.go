@@ -1,14 +1,17 @@
 package delivery
 
 import (
+	"errors"
 	"mygram-api/domain"
 	"mygram-api/helpers"
-	"mygram-api/socialmedia/delivery/http/middleware"
+	"mygram-api/middleware"
+	"mygram-api/pagination"
+	"mygram-api/policy"
 	"mygram-api/socialmedia/utils"
 	"net/http"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type socialMediaHandler struct {
@@ -20,35 +23,39 @@ func NewSocialMediaHandler(routers *gin.Engine, socialMediaUseCase domain.Social
 
 	router := routers.Group("/socialmedias")
 	{
-		router.Use(middleware.Authentication())
-		router.GET("", handler.Fetch)
-		router.POST("", handler.Store)
-		router.PUT("/:socialMediaId", middleware.Authorization(handler.socialMediaUseCase), handler.Update)
-		router.DELETE("/:socialMediaId", middleware.Authorization(handler.socialMediaUseCase), handler.Delete)
+		router.GET("", middleware.Authentication(domain.ScopeSocialMediasRead), handler.Fetch)
+		router.POST("", middleware.Authentication(domain.ScopeSocialMediasWrite), handler.Store)
+		router.GET("/:socialMediaId", middleware.Authentication(domain.ScopeSocialMediasRead), handler.GetByID)
+		router.PUT("/:socialMediaId", middleware.Authentication(domain.ScopeSocialMediasWrite), middleware.Authorization[domain.SocialMedia](handler.socialMediaUseCase, "socialMediaId", policy.ResourceSocialMedia, policy.ActionUpdate), handler.Update)
+		router.DELETE("/:socialMediaId", middleware.Authentication(domain.ScopeSocialMediasWrite), middleware.Authorization[domain.SocialMedia](handler.socialMediaUseCase, "socialMediaId", policy.ResourceSocialMedia, policy.ActionDelete), handler.Delete)
 	}
 }
 
 // Fetch godoc
 // @Summary    	Fetch all social media
-// @Description	Get all social media with authentication user
+// @Description	Get a page of the social media of the authenticated user, newest first
 // @Tags        socialmedias
 // @Accept      json
 // @Produce     json
+// @Param       cursor					query			string	false	"Cursor of the page, the next_cursor of the previous one"
+// @Param       limit						query			int			false	"Items per page, 20 by default and at most 100"
+// @Param       sort						query			string	false	"newest (default) or oldest first"
+// @Param       created_after		query			string	false	"Only items created after this RFC 3339 time"
+// @Param       created_before	query			string	false	"Only items created before this RFC 3339 time"
 // @Success     200	{object}	utils.ResponseDataFetchedSocialMedia
 // @Failure     400	{object}	utils.ResponseMessage
 // @Failure     401	{object}	utils.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /socialmedias	[get]
 func (handler *socialMediaHandler) Fetch(ctx *gin.Context) {
-	var (
-		socialMedias []domain.SocialMedia
-		err          error
-	)
+	var socialMedias []domain.SocialMedia
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	userID := middleware.CurrentPrincipal(ctx).UserID
 
-	if err = handler.socialMediaUseCase.Fetch(ctx.Request.Context(), &socialMedias, userID); err != nil {
+	query, err := pagination.Parse(ctx.Request.URL.Query())
+
+	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -57,11 +64,81 @@ func (handler *socialMediaHandler) Fetch(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, helpers.ResponseData{
+	next, err := handler.socialMediaUseCase.Fetch(ctx.Request.Context(), &socialMedias, userID, query)
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponsePage{
 		Status: "success",
 		Data: utils.FetchedSocialMedia{
 			SocialMedias: socialMedias,
 		},
+		NextCursor: next,
+	})
+}
+
+// GetByID godoc
+// @Summary    	Fetch a social media
+// @Description	Get a social media by id with its owner
+// @Tags        socialmedias
+// @Accept      json
+// @Produce     json
+// @Param       id	path			string	true	"SocialMedia ID"
+// @Success     200	{object}	utils.ResponseDataSocialMediaDetail
+// @Failure     401	{object}	utils.ResponseMessage
+// @Failure     404	{object}	utils.ResponseMessage
+// @Failure     500	{object}	utils.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /socialmedias/{id}	[get]
+func (handler *socialMediaHandler) GetByID(ctx *gin.Context) {
+	var socialMedia domain.SocialMedia
+
+	socialMediaID := ctx.Param("socialMediaId")
+
+	if err := handler.socialMediaUseCase.GetDetailByID(ctx.Request.Context(), &socialMedia, socialMediaID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourceSocialMedia, socialMediaID)
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	socialMediaDetail := utils.SocialMediaDetail{
+		ID:             socialMedia.ID,
+		Name:           socialMedia.Name,
+		SocialMediaUrl: socialMedia.SocialMediaUrl,
+		UserID:         socialMedia.UserID,
+		CreatedAt:      socialMedia.CreatedAt,
+		UpdatedAt:      socialMedia.UpdatedAt,
+	}
+
+	if socialMedia.User != nil {
+		socialMediaDetail.User = &utils.Owner{
+			ID:              socialMedia.User.ID,
+			Username:        socialMedia.User.Username,
+			DisplayName:     socialMedia.User.DisplayName,
+			ProfileImageUrl: socialMedia.User.ProfileImageUrl,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   socialMediaDetail,
 	})
 }
 
@@ -76,6 +153,7 @@ func (handler *socialMediaHandler) Fetch(ctx *gin.Context) {
 // @Failure     400		{object}	utils.ResponseMessage
 // @Failure     401		{object}	utils.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /socialmedias		[post]
 func (handler *socialMediaHandler) Store(ctx *gin.Context) {
 	var (
@@ -83,8 +161,7 @@ func (handler *socialMediaHandler) Store(ctx *gin.Context) {
 		err         error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	userID := middleware.CurrentPrincipal(ctx).UserID
 
 	if err = ctx.ShouldBindJSON(&socialMedia); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -129,8 +206,10 @@ func (handler *socialMediaHandler) Store(ctx *gin.Context) {
 // @Success     200		{object}	utils.ResponseDataUpdatedSocialMedia
 // @Failure     400		{object}	utils.ResponseMessage
 // @Failure     401		{object}	utils.ResponseMessage
+// @Failure     403		{object}	utils.ResponseMessage
 // @Failure     404		{object}	utils.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /socialmedias/{id} [put]
 func (handler *socialMediaHandler) Update(ctx *gin.Context) {
 	var (
@@ -138,10 +217,6 @@ func (handler *socialMediaHandler) Update(ctx *gin.Context) {
 		err         error
 	)
 
-	socialMediaID := ctx.Param("socialMediaId")
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
-
 	if err = ctx.ShouldBindJSON(&socialMedia); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
@@ -152,12 +227,17 @@ func (handler *socialMediaHandler) Update(ctx *gin.Context) {
 	}
 
 	updatedSocialMedia := domain.SocialMedia{
-		UserID:         userID,
 		Name:           socialMedia.Name,
 		SocialMediaUrl: socialMedia.SocialMediaUrl,
 	}
 
-	if socialMedia, err = handler.socialMediaUseCase.Update(ctx.Request.Context(), updatedSocialMedia, socialMediaID); err != nil {
+	if socialMedia, err = handler.socialMediaUseCase.Update(ctx.Request.Context(), middleware.Loaded[domain.SocialMedia](ctx), updatedSocialMedia); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourceSocialMedia, ctx.Param("socialMediaId"))
+
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -185,13 +265,21 @@ func (handler *socialMediaHandler) Update(ctx *gin.Context) {
 // @Success     200  {object}	utils.ResponseMessageDeletedSocialMedia
 // @Failure     400  {object}	utils.ResponseMessage
 // @Failure     401  {object}	utils.ResponseMessage
+// @Failure     403  {object}	utils.ResponseMessage
 // @Failure     404  {object}	utils.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /socialmedias/{id} [delete]
 func (handler *socialMediaHandler) Delete(ctx *gin.Context) {
 	socialMediaID := ctx.Param("socialMediaId")
 
 	if err := handler.socialMediaUseCase.Delete(ctx.Request.Context(), socialMediaID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.AbortNotFound(ctx, policy.ResourceSocialMedia, socialMediaID)
+
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
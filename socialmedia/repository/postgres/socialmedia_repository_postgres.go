@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"mygram-api/domain"
+	"mygram-api/pagination"
 	"time"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
@@ -18,18 +19,22 @@ func NewSocialMediaRepository(db *gorm.DB) *socialMediaRepository {
 	return &socialMediaRepository{db}
 }
 
-func (socialMediaRepository *socialMediaRepository) Fetch(ctx context.Context, socialMedias *[]domain.SocialMedia, userID string) (err error) {
+func (socialMediaRepository *socialMediaRepository) Fetch(ctx context.Context, socialMedias *[]domain.SocialMedia, userID string, query pagination.Query) (next string, err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	if err = socialMediaRepository.db.WithContext(ctx).Where("user_id = ?", userID).Preload("User", func(db *gorm.DB) *gorm.DB {
+	query.UserID = userID
+
+	if err = socialMediaRepository.db.WithContext(ctx).Scopes(query.Scope("social_media")).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("ID", "Email", "Username", "ProfileImageUrl")
 	}).Find(&socialMedias).Error; err != nil {
-		return err
+		return "", err
 	}
 
-	return
+	return pagination.Trim(query, socialMedias, func(socialMedia domain.SocialMedia) pagination.Cursor {
+		return pagination.NewCursor(socialMedia.CreatedAt, socialMedia.ID)
+	}), nil
 }
 
 func (socialMediaRepository *socialMediaRepository) Store(ctx context.Context, socialMedia *domain.SocialMedia) (err error) {
@@ -60,18 +65,31 @@ func (socialMediaRepository *socialMediaRepository) GetByID(ctx context.Context,
 	return
 }
 
-func (socialMediaRepository *socialMediaRepository) Update(ctx context.Context, socialMedia domain.SocialMedia, id string) (socmed domain.SocialMedia, err error) {
+// GetDetailByID loads the social media with id along with its owner.
+func (socialMediaRepository *socialMediaRepository) GetDetailByID(ctx context.Context, socialMedia *domain.SocialMedia, id string) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	socmed = domain.SocialMedia{}
-
-	if err = socialMediaRepository.db.WithContext(ctx).First(&socmed, &id).Error; err != nil {
-		return socmed, err
+	if err = socialMediaRepository.db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "display_name", "profile_image_url")
+	}).First(&socialMedia, "id = ?", id).Error; err != nil {
+		return err
 	}
 
-	if err = socialMediaRepository.db.WithContext(ctx).Model(&socmed).Updates(socialMedia).Error; err != nil {
+	return
+}
+
+// Update applies the non-zero fields of updatedSocialMedia to socialMedia, which
+// callers have already loaded, and returns the result.
+func (socialMediaRepository *socialMediaRepository) Update(ctx context.Context, socialMedia domain.SocialMedia, updatedSocialMedia domain.SocialMedia) (socmed domain.SocialMedia, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	socmed = socialMedia
+
+	if err = socialMediaRepository.db.WithContext(ctx).Model(&socmed).Updates(updatedSocialMedia).Error; err != nil {
 		return socmed, err
 	}
 
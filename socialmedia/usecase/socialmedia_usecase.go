@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"mygram-api/domain"
+	"mygram-api/pagination"
 )
 
 type socialMediaUseCase struct {
@@ -13,12 +14,12 @@ func NewSocialMediaUseCase(socialMediaRepository domain.SocialMediaRepository) *
 	return &socialMediaUseCase{socialMediaRepository}
 }
 
-func (socialMediaUseCase *socialMediaUseCase) Fetch(ctx context.Context, socialMedias *[]domain.SocialMedia, userID string) (err error) {
-	if err = socialMediaUseCase.socialMediaRepository.Fetch(ctx, socialMedias, userID); err != nil {
-		return err
+func (socialMediaUseCase *socialMediaUseCase) Fetch(ctx context.Context, socialMedias *[]domain.SocialMedia, userID string, query pagination.Query) (next string, err error) {
+	if next, err = socialMediaUseCase.socialMediaRepository.Fetch(ctx, socialMedias, userID, query); err != nil {
+		return "", err
 	}
 
-	return
+	return next, nil
 }
 
 func (socialMediaUseCase *socialMediaUseCase) Store(ctx context.Context, socialMedia *domain.SocialMedia) (err error) {
@@ -37,8 +38,16 @@ func (socialMediaUseCase *socialMediaUseCase) GetByID(ctx context.Context, socia
 	return
 }
 
-func (socialMediaUseCase *socialMediaUseCase) Update(ctx context.Context, socialMedia domain.SocialMedia, id string) (socmed domain.SocialMedia, err error) {
-	if socmed, err = socialMediaUseCase.socialMediaRepository.Update(ctx, socialMedia, id); err != nil {
+func (socialMediaUseCase *socialMediaUseCase) GetDetailByID(ctx context.Context, socialMedia *domain.SocialMedia, id string) (err error) {
+	if err = socialMediaUseCase.socialMediaRepository.GetDetailByID(ctx, socialMedia, id); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (socialMediaUseCase *socialMediaUseCase) Update(ctx context.Context, socialMedia domain.SocialMedia, updatedSocialMedia domain.SocialMedia) (socmed domain.SocialMedia, err error) {
+	if socmed, err = socialMediaUseCase.socialMediaRepository.Update(ctx, socialMedia, updatedSocialMedia); err != nil {
 		return socmed, err
 	}
 
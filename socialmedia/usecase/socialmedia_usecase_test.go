@@ -5,6 +5,7 @@ import (
 	"errors"
 	"mygram-api/domain"
 	"mygram-api/domain/mocks"
+	"mygram-api/pagination"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/asaskevich/govalidator"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func TestFetch(t *testing.T) {
@@ -34,11 +36,12 @@ func TestFetch(t *testing.T) {
 	socialMediaUseCase := socialMediaUseCase.NewSocialMediaUseCase(mockSocialMediaRepository)
 
 	t.Run("fetch all social media correctly", func(t *testing.T) {
-		mockSocialMediaRepository.On("Fetch", mock.Anything, mock.AnythingOfType("*[]domain.SocialMedia"), mock.AnythingOfType("string")).Return(nil).Once()
+		mockSocialMediaRepository.On("Fetch", mock.Anything, mock.AnythingOfType("*[]domain.SocialMedia"), mock.AnythingOfType("string"), mock.AnythingOfType("pagination.Query")).Return("", nil).Once()
 
-		err := socialMediaUseCase.Fetch(context.Background(), &mockSocialMedias, mockSocialMedia.UserID)
+		next, err := socialMediaUseCase.Fetch(context.Background(), &mockSocialMedias, mockSocialMedia.UserID, pagination.Query{Limit: 20})
 
 		assert.NoError(t, err)
+		assert.Empty(t, next)
 	})
 }
 
@@ -189,6 +192,48 @@ func TestGetBy(t *testing.T) {
 	})
 }
 
+func TestGetDetailByID(t *testing.T) {
+	now := time.Now()
+
+	mockSocialMedia := domain.SocialMedia{
+		ID:             "socialmedia-123",
+		Name:           "Example",
+		SocialMediaUrl: "https://www.example.com/johndoe",
+		UserID:         "user-123",
+		CreatedAt:      &now,
+		UpdatedAt:      &now,
+		User:           &domain.User{ID: "user-123", Username: "johndoe"},
+	}
+
+	mockSocialMediaRepository := new(mocks.SocialMediaRepository)
+	socialMediaUseCase := socialMediaUseCase.NewSocialMediaUseCase(mockSocialMediaRepository)
+
+	t.Run("get detail by id correctly", func(t *testing.T) {
+		var socialMedia domain.SocialMedia
+
+		mockSocialMediaRepository.On("GetDetailByID", mock.Anything, mock.AnythingOfType("*domain.SocialMedia"), "socialmedia-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.SocialMedia) = mockSocialMedia
+		}).Return(nil).Once()
+
+		err := socialMediaUseCase.GetDetailByID(context.Background(), &socialMedia, "socialmedia-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockSocialMedia, socialMedia)
+		mockSocialMediaRepository.AssertExpectations(t)
+	})
+
+	t.Run("get detail by id with not found social media", func(t *testing.T) {
+		var socialMedia domain.SocialMedia
+
+		mockSocialMediaRepository.On("GetDetailByID", mock.Anything, mock.AnythingOfType("*domain.SocialMedia"), "socialmedia-234").Return(gorm.ErrRecordNotFound).Once()
+
+		err := socialMediaUseCase.GetDetailByID(context.Background(), &socialMedia, "socialmedia-234")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockSocialMediaRepository.AssertExpectations(t)
+	})
+}
+
 func TestUpdate(t *testing.T) {
 	now := time.Now()
 	mockUpdatedSocialMedia := domain.SocialMedia{
@@ -203,20 +248,20 @@ func TestUpdate(t *testing.T) {
 	socialMediaUseCase := socialMediaUseCase.NewSocialMediaUseCase(mockSocialMediaRepository)
 
 	t.Run("update social media correctly", func(t *testing.T) {
-		tempMockSocialMediaID := "socialmedia-123"
+		tempMockSocialMedia := domain.SocialMedia{ID: "socialmedia-123", UserID: "user-123"}
 		tempMockUpdateSocialMedia := domain.SocialMedia{
 			Name:           "New Example",
 			SocialMediaUrl: "https://www.newexample.com/johndoe",
 		}
 
-		mockSocialMediaRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.SocialMedia"), mock.AnythingOfType("string")).Return(mockUpdatedSocialMedia, nil).Once()
+		mockSocialMediaRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.SocialMedia"), mock.AnythingOfType("domain.SocialMedia")).Return(mockUpdatedSocialMedia, nil).Once()
 
-		socialmedia, err := socialMediaUseCase.Update(context.Background(), tempMockUpdateSocialMedia, tempMockSocialMediaID)
+		socialmedia, err := socialMediaUseCase.Update(context.Background(), tempMockSocialMedia, tempMockUpdateSocialMedia)
 
 		assert.NoError(t, err)
 
 		tempMockUpdatedSocialMedia := domain.SocialMedia{
-			ID:             tempMockSocialMediaID,
+			ID:             tempMockSocialMedia.ID,
 			Name:           tempMockUpdateSocialMedia.Name,
 			SocialMediaUrl: tempMockUpdateSocialMedia.SocialMediaUrl,
 			UserID:         "user-123",
@@ -236,20 +281,20 @@ func TestUpdate(t *testing.T) {
 	})
 
 	t.Run("update social media with empty name", func(t *testing.T) {
-		tempMockSocialMediaID := "socialmedia-123"
+		tempMockSocialMedia := domain.SocialMedia{ID: "socialmedia-123", UserID: "user-123"}
 		tempMockUpdateSocialMedia := domain.SocialMedia{
 			Name:           "",
 			SocialMediaUrl: "https://www.newexample.com/johndoe",
 		}
 
-		mockSocialMediaRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.SocialMedia"), mock.AnythingOfType("string")).Return(mockUpdatedSocialMedia, nil).Once()
+		mockSocialMediaRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.SocialMedia"), mock.AnythingOfType("domain.SocialMedia")).Return(mockUpdatedSocialMedia, nil).Once()
 
-		socialmedia, err := socialMediaUseCase.Update(context.Background(), tempMockUpdateSocialMedia, tempMockSocialMediaID)
+		socialmedia, err := socialMediaUseCase.Update(context.Background(), tempMockSocialMedia, tempMockUpdateSocialMedia)
 
 		assert.NoError(t, err)
 
 		tempMockUpdatedSocialMedia := domain.SocialMedia{
-			ID:             tempMockSocialMediaID,
+			ID:             tempMockSocialMedia.ID,
 			Name:           tempMockUpdateSocialMedia.Name,
 			SocialMediaUrl: tempMockUpdateSocialMedia.SocialMediaUrl,
 			UserID:         "user-123",
@@ -269,20 +314,20 @@ func TestUpdate(t *testing.T) {
 	})
 
 	t.Run("update social media with empty social media url", func(t *testing.T) {
-		tempMockSocialMediaID := "socialmedia-123"
+		tempMockSocialMedia := domain.SocialMedia{ID: "socialmedia-123", UserID: "user-123"}
 		tempMockUpdateSocialMedia := domain.SocialMedia{
 			Name:           "New Example",
 			SocialMediaUrl: "",
 		}
 
-		mockSocialMediaRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.SocialMedia"), mock.AnythingOfType("string")).Return(mockUpdatedSocialMedia, nil).Once()
+		mockSocialMediaRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.SocialMedia"), mock.AnythingOfType("domain.SocialMedia")).Return(mockUpdatedSocialMedia, nil).Once()
 
-		socialmedia, err := socialMediaUseCase.Update(context.Background(), tempMockUpdateSocialMedia, tempMockSocialMediaID)
+		socialmedia, err := socialMediaUseCase.Update(context.Background(), tempMockSocialMedia, tempMockUpdateSocialMedia)
 
 		assert.NoError(t, err)
 
 		tempMockUpdatedSocialMedia := domain.SocialMedia{
-			ID:             tempMockSocialMediaID,
+			ID:             tempMockSocialMedia.ID,
 			Name:           tempMockUpdateSocialMedia.Name,
 			SocialMediaUrl: tempMockUpdateSocialMedia.Name,
 			UserID:         "user-123",
@@ -302,19 +347,19 @@ func TestUpdate(t *testing.T) {
 	})
 
 	t.Run("update social media with not contain property", func(t *testing.T) {
-		tempMockSocialMediaID := "socialmedia-123"
+		tempMockSocialMedia := domain.SocialMedia{ID: "socialmedia-123", UserID: "user-123"}
 		tempMockUpdateSocialMedia := domain.SocialMedia{
 			Name: "New Example",
 		}
 
-		mockSocialMediaRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.SocialMedia"), mock.AnythingOfType("string")).Return(mockUpdatedSocialMedia, nil).Once()
+		mockSocialMediaRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.SocialMedia"), mock.AnythingOfType("domain.SocialMedia")).Return(mockUpdatedSocialMedia, nil).Once()
 
-		socialmedia, err := socialMediaUseCase.Update(context.Background(), tempMockUpdateSocialMedia, tempMockSocialMediaID)
+		socialmedia, err := socialMediaUseCase.Update(context.Background(), tempMockSocialMedia, tempMockUpdateSocialMedia)
 
 		assert.NoError(t, err)
 
 		tempMockUpdatedSocialMedia := domain.SocialMedia{
-			ID:             tempMockSocialMediaID,
+			ID:             tempMockSocialMedia.ID,
 			Name:           tempMockUpdateSocialMedia.Name,
 			SocialMediaUrl: tempMockUpdateSocialMedia.Name,
 			UserID:         "user-123",
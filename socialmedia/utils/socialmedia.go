@@ -8,6 +8,13 @@ type User struct {
 	Email    string `json:"email" example:"johndoe@example.com"`
 }
 
+type Owner struct {
+	ID              string `json:"id" example:"here is the generated user id"`
+	Username        string `json:"username" example:"johndoe"`
+	DisplayName     string `json:"display_name" example:"John Doe"`
+	ProfileImageUrl string `json:"profile_image_url" example:"https://www.example.com/image.jpg"`
+}
+
 type SocialMedia struct {
 	ID             string     `json:"id" example:"here is the generated social media id"`
 	Name           string     `json:"name" example:"Example"`
@@ -27,8 +34,24 @@ type FetchedSocialMedia struct {
 }
 
 type ResponseDataFetchedSocialMedia struct {
-	Status string       `json:"status" example:"success"`
-	Data   SocialMedias `json:"data"`
+	Status     string       `json:"status" example:"success"`
+	Data       SocialMedias `json:"data"`
+	NextCursor string       `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyMi0xMC0wMVQxMjozMDowMFoiLCJpZCI6InBob3RvLTEyMyJ9"`
+}
+
+type SocialMediaDetail struct {
+	ID             string     `json:"id" example:"here is the generated social media id"`
+	Name           string     `json:"name" example:"Example"`
+	SocialMediaUrl string     `json:"social_media_url" example:"https://www.example.com/johndoe"`
+	UserID         string     `json:"user_id" example:"here is the generated user id"`
+	CreatedAt      *time.Time `json:"created_at" example:"here is the generated created at"`
+	UpdatedAt      *time.Time `json:"updated_at" example:"here is the generated updated at"`
+	User           *Owner     `json:"user"`
+}
+
+type ResponseDataSocialMediaDetail struct {
+	Status string            `json:"status" example:"success"`
+	Data   SocialMediaDetail `json:"data"`
 }
 
 type AddSocialMedia struct {
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"log"
+	"mygram-api/domain"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Mountable is a BlobStore that serves its blobs itself.
+type Mountable interface {
+	Mount(*gin.Engine)
+}
+
+// NewFromEnv stores uploads in the S3_BUCKET at S3_ENDPOINT when it is set, see
+// S3Config for the other S3_ variables. Otherwise they go below BLOB_DIR
+// (default "uploads") and are served from BLOB_BASE_URL (default "/media").
+func NewFromEnv() domain.BlobStore {
+	if bucket := os.Getenv("S3_BUCKET"); bucket != "" {
+		region := os.Getenv("S3_REGION")
+
+		if region == "" {
+			region = "us-east-1"
+		}
+
+		endpoint := os.Getenv("S3_ENDPOINT")
+
+		if endpoint == "" {
+			endpoint = "https://s3." + region + ".amazonaws.com"
+		}
+
+		return NewS3BlobStore(S3Config{
+			Endpoint:        endpoint,
+			Region:          region,
+			Bucket:          bucket,
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PublicURL:       os.Getenv("S3_PUBLIC_URL"),
+		}, &http.Client{Timeout: 30 * time.Second})
+	}
+
+	dir := os.Getenv("BLOB_DIR")
+
+	if dir == "" {
+		dir = "uploads"
+	}
+
+	baseURL := os.Getenv("BLOB_BASE_URL")
+
+	if baseURL == "" {
+		baseURL = "/media"
+	}
+
+	log.Printf("S3_BUCKET is not set, uploads are stored in %s", dir)
+
+	return NewLocalBlobStore(dir, baseURL)
+}
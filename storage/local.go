@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errKeyInvalid = errors.New("storage: invalid key")
+
+type localBlobStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalBlobStore keeps blobs as files below dir, reachable at baseURL.
+func NewLocalBlobStore(dir string, baseURL string) *localBlobStore {
+	return &localBlobStore{dir, strings.TrimSuffix(baseURL, "/")}
+}
+
+func (localBlobStore *localBlobStore) Put(ctx context.Context, key string, contentType string, body io.Reader) (err error) {
+	name, err := localBlobStore.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	// Write next to the target and rename, so a reader never sees half a file.
+	file, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+
+	if _, err = io.Copy(file, body); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(file.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), name)
+}
+
+func (localBlobStore *localBlobStore) Delete(ctx context.Context, key string) (err error) {
+	name, err := localBlobStore.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (localBlobStore *localBlobStore) URL(key string) string {
+	return localBlobStore.baseURL + "/" + key
+}
+
+// Mount serves the stored files on routers below the path of baseURL, for when
+// no other web server is put in front of dir. Directory listings are refused,
+// and the type is taken from the file so the JSON default set for every
+// response doesn't stick.
+func (localBlobStore *localBlobStore) Mount(routers *gin.Engine) {
+	prefix := "/"
+
+	if baseURL, err := url.Parse(localBlobStore.baseURL); err == nil && baseURL.Path != "" {
+		prefix = baseURL.Path
+	}
+
+	routers.GET(path.Join(prefix, "*key"), func(ctx *gin.Context) {
+		name, err := localBlobStore.path(strings.TrimPrefix(ctx.Param("key"), "/"))
+
+		if err != nil {
+			ctx.AbortWithStatus(http.StatusNotFound)
+
+			return
+		}
+
+		if info, err := os.Stat(name); err != nil || info.IsDir() {
+			ctx.AbortWithStatus(http.StatusNotFound)
+
+			return
+		}
+
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Header("X-Content-Type-Options", "nosniff")
+		ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+
+		http.ServeFile(ctx.Writer, ctx.Request, name)
+	})
+}
+
+// path maps key to a file below dir.
+func (localBlobStore *localBlobStore) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(localBlobStore.dir, filepath.FromSlash(key)), nil
+}
+
+// validateKey refuses keys that are not plain relative paths, so none can reach
+// outside the directory or bucket they are meant for.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return errKeyInvalid
+	}
+
+	return nil
+}
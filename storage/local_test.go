@@ -0,0 +1,68 @@
+package storage_test
+
+import (
+	"context"
+	"mygram-api/storage"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	blobStore := storage.NewLocalBlobStore(dir, "/media")
+	routers := gin.New()
+
+	routers.Use(func(ctx *gin.Context) {
+		ctx.Writer.Header().Set("Content-Type", "application/json")
+	})
+	blobStore.Mount(routers)
+
+	t.Run("put and serve a blob", func(t *testing.T) {
+		err := blobStore.Put(context.Background(), "avatars/user-123/abc-64.jpg", "image/jpeg", strings.NewReader("\xff\xd8\xff\xe0 not really a jpeg"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/media/avatars/user-123/abc-64.jpg", blobStore.URL("avatars/user-123/abc-64.jpg"))
+
+		recorder := httptest.NewRecorder()
+
+		routers.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/media/avatars/user-123/abc-64.jpg", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "image/jpeg", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "nosniff", recorder.Header().Get("X-Content-Type-Options"))
+	})
+
+	t.Run("serve a directory", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+
+		routers.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/media/avatars/user-123", nil))
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("delete a blob", func(t *testing.T) {
+		err := blobStore.Delete(context.Background(), "avatars/user-123/abc-64.jpg")
+
+		assert.NoError(t, err)
+
+		_, err = os.Stat(filepath.Join(dir, "avatars", "user-123", "abc-64.jpg"))
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		assert.NoError(t, blobStore.Delete(context.Background(), "avatars/user-123/abc-64.jpg"))
+	})
+
+	t.Run("put a blob outside the directory", func(t *testing.T) {
+		for _, key := range []string{"../escape.jpg", "/etc/passwd", "avatars/../../escape.jpg", ""} {
+			assert.Error(t, blobStore.Put(context.Background(), key, "image/jpeg", strings.NewReader("x")), key)
+		}
+	})
+}
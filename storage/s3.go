@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Config points at a bucket of Amazon S3 or of a compatible service such as
+// MinIO. Objects are addressed path-style, Endpoint/Bucket/key, which every
+// implementation understands. PublicURL is where the objects are read from, a
+// CDN for instance, and defaults to the bucket itself.
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PublicURL       string
+}
+
+type s3BlobStore struct {
+	config S3Config
+	client *http.Client
+}
+
+func NewS3BlobStore(config S3Config, client *http.Client) *s3BlobStore {
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+
+	if config.PublicURL == "" {
+		config.PublicURL = config.Endpoint + "/" + config.Bucket
+	}
+
+	config.PublicURL = strings.TrimSuffix(config.PublicURL, "/")
+
+	return &s3BlobStore{config, client}
+}
+
+func (s3BlobStore *s3BlobStore) Put(ctx context.Context, key string, contentType string, body io.Reader) (err error) {
+	// The payload is hashed into the signature, uploads are small enough to hold.
+	data, err := io.ReadAll(body)
+
+	if err != nil {
+		return err
+	}
+
+	request, err := s3BlobStore.request(ctx, http.MethodPut, key, data)
+
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", contentType)
+
+	return s3BlobStore.do(request)
+}
+
+func (s3BlobStore *s3BlobStore) Delete(ctx context.Context, key string) (err error) {
+	request, err := s3BlobStore.request(ctx, http.MethodDelete, key, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return s3BlobStore.do(request)
+}
+
+func (s3BlobStore *s3BlobStore) URL(key string) string {
+	return s3BlobStore.config.PublicURL + "/" + key
+}
+
+func (s3BlobStore *s3BlobStore) request(ctx context.Context, method string, key string, data []byte) (*http.Request, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := url.Parse(s3BlobStore.config.Endpoint + "/" + s3BlobStore.config.Bucket + "/" + key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("X-Amz-Content-Sha256", hashHex(data))
+
+	return request, nil
+}
+
+func (s3BlobStore *s3BlobStore) do(request *http.Request) (err error) {
+	signV4(request, s3BlobStore.config.Region, s3BlobStore.config.AccessKeyID, s3BlobStore.config.SecretAccessKey, time.Now())
+
+	response, err := s3BlobStore.client.Do(request)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	// Deleting what is already gone is what the caller wanted anyway.
+	if response.StatusCode/100 == 2 || request.Method == http.MethodDelete && response.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+
+	return fmt.Errorf("storage: %s %s: %s: %s", request.Method, request.URL.Path, response.Status, strings.TrimSpace(string(message)))
+}
@@ -0,0 +1,87 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"mygram-api/storage"
+	"mygram-api/storage/s3test"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3BlobStore(t *testing.T) {
+	server := s3test.NewServer("mygram", "AKIAMYGRAM")
+
+	defer server.Close()
+
+	blobStore := storage.NewS3BlobStore(storage.S3Config{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		Bucket:          "mygram",
+		AccessKeyID:     "AKIAMYGRAM",
+		SecretAccessKey: "secret",
+	}, server.Client())
+
+	t.Run("put a blob", func(t *testing.T) {
+		err := blobStore.Put(context.Background(), "photos/abc.jpg", "image/jpeg", strings.NewReader("jpeg bytes"))
+
+		assert.NoError(t, err)
+
+		object, ok := server.Object("photos/abc.jpg")
+
+		assert.True(t, ok)
+		assert.Equal(t, "image/jpeg", object.ContentType)
+		assert.Equal(t, "jpeg bytes", string(object.Data))
+	})
+
+	t.Run("read a blob from its url", func(t *testing.T) {
+		assert.Equal(t, server.URL+"/mygram/photos/abc.jpg", blobStore.URL("photos/abc.jpg"))
+
+		response, err := server.Client().Get(blobStore.URL("photos/abc.jpg"))
+
+		assert.NoError(t, err)
+
+		defer response.Body.Close()
+
+		data, _ := io.ReadAll(response.Body)
+
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "jpeg bytes", string(data))
+	})
+
+	t.Run("delete a blob", func(t *testing.T) {
+		err := blobStore.Delete(context.Background(), "photos/abc.jpg")
+
+		assert.NoError(t, err)
+
+		_, ok := server.Object("photos/abc.jpg")
+
+		assert.False(t, ok)
+	})
+
+	t.Run("put a blob with the wrong credentials", func(t *testing.T) {
+		blobStore := storage.NewS3BlobStore(storage.S3Config{
+			Endpoint:    server.URL,
+			Region:      "us-east-1",
+			Bucket:      "mygram",
+			AccessKeyID: "AKIAOTHER",
+		}, server.Client())
+
+		err := blobStore.Put(context.Background(), "photos/abc.jpg", "image/jpeg", strings.NewReader("jpeg bytes"))
+
+		assert.ErrorContains(t, err, "403")
+	})
+
+	t.Run("put a blob with a public url", func(t *testing.T) {
+		blobStore := storage.NewS3BlobStore(storage.S3Config{
+			Endpoint:  server.URL,
+			Bucket:    "mygram",
+			PublicURL: "https://cdn.example.com/",
+		}, server.Client())
+
+		assert.Equal(t, "https://cdn.example.com/photos/abc.jpg", blobStore.URL("photos/abc.jpg"))
+	})
+}
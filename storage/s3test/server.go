@@ -0,0 +1,116 @@
+// Package s3test runs a stand-in for an S3-compatible bucket on httptest. It
+// keeps objects in memory and checks that writes carry credentials for its
+// access key and a payload hash that matches the body, as a real service would.
+package s3test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Object is a stored blob.
+type Object struct {
+	ContentType string
+	Data        []byte
+}
+
+// Server is the stand-in service. Its URL is the endpoint to configure.
+type Server struct {
+	*httptest.Server
+	Bucket      string
+	AccessKeyID string
+
+	mu      sync.Mutex
+	objects map[string]Object
+}
+
+// NewServer starts a service holding a single, empty bucket.
+func NewServer(bucket string, accessKeyID string) *Server {
+	server := &Server{
+		Bucket:      bucket,
+		AccessKeyID: accessKeyID,
+		objects:     map[string]Object{},
+	}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serve))
+
+	return server
+}
+
+// Object returns the object stored under key, if any.
+func (server *Server) Object(key string) (Object, bool) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	object, ok := server.objects[key]
+
+	return object, ok
+}
+
+func (server *Server) serve(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/"+server.Bucket+"/")
+
+	if key == r.URL.Path || key == "" {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		object, ok := server.Object(key)
+
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", object.ContentType)
+		w.Write(object.Data)
+
+		return
+	}
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="+server.AccessKeyID+"/") || r.Header.Get("X-Amz-Date") == "" {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		http.Error(w, "IncompleteBody", http.StatusBadRequest)
+
+		return
+	}
+
+	sum := sha256.Sum256(data)
+
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+		http.Error(w, "XAmzContentSHA256Mismatch", http.StatusBadRequest)
+
+		return
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		server.objects[key] = Object{ContentType: r.Header.Get("Content-Type"), Data: data}
+
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(server.objects, key)
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,207 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"mygram-api/middleware"
+	"mygram-api/policy"
+	"mygram-api/user/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type adminHandler struct {
+	userUseCase  domain.UserUseCase
+	tokenUseCase domain.TokenUseCase
+}
+
+func NewAdminHandler(routers *gin.Engine, userUseCase domain.UserUseCase, tokenUseCase domain.TokenUseCase) {
+	handler := &adminHandler{userUseCase, tokenUseCase}
+
+	router := routers.Group("/admin/users")
+	{
+		router.Use(middleware.Authentication(), middleware.Authorize(policy.ActionManage, policy.ResourceUser))
+		router.GET("", handler.Fetch)
+		router.PUT("/:userId/role", handler.ChangeRole)
+		router.DELETE("/:userId", handler.Delete)
+	}
+}
+
+// Fetch godoc
+// @Summary			Fetch all users
+// @Description	Fetch every account, for admins only
+// @Tags				admin
+// @Produce			json
+// @Success			200		{object}	utils.ResponseDataAdminUsers
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			403		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/admin/users	[get]
+func (handler *adminHandler) Fetch(ctx *gin.Context) {
+	var users []domain.User
+
+	if err := handler.userUseCase.Fetch(ctx.Request.Context(), &users); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	fetchedUsers := make([]utils.AdminUser, 0, len(users))
+
+	for _, user := range users {
+		fetchedUsers = append(fetchedUsers, utils.AdminUser{
+			ID:              user.ID,
+			Username:        user.Username,
+			Email:           user.Email,
+			Role:            string(user.Role),
+			EmailVerifiedAt: user.EmailVerifiedAt,
+			TwoFactor:       user.TOTPEnabledAt != nil,
+			CreatedAt:       user.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   fetchedUsers,
+	})
+}
+
+// ChangeRole godoc
+// @Summary			Change the role of a user
+// @Description	Make a user a regular user, a moderator or an admin, for admins only. The user is signed out everywhere so the new role applies at once. The last admin cannot be demoted
+// @Tags				admin
+// @Accept			json
+// @Produce			json
+// @Param				userId	path			string						true	"User ID"
+// @Param				json		body			utils.ChangeRole	true	"Change Role"
+// @Success			200			{object}	utils.ResponseMessageChangedRole
+// @Failure			400			{object}	utils.ResponseMessage
+// @Failure			401			{object}	utils.ResponseMessage
+// @Failure			403			{object}	utils.ResponseMessage
+// @Failure			404			{object}	utils.ResponseMessage
+// @Failure			409			{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/admin/users/{userId}/role	[put]
+func (handler *adminHandler) ChangeRole(ctx *gin.Context) {
+	var (
+		changeRole utils.ChangeRole
+		err        error
+	)
+
+	userID := ctx.Param("userId")
+
+	if err = ctx.ShouldBindJSON(&changeRole); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.userUseCase.ChangeRole(ctx.Request.Context(), userID, domain.Role(changeRole.Role)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("user with id %s doesn't exist", userID),
+			})
+
+			return
+		}
+
+		if errors.Is(err, domain.ErrRoleInvalid) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		if errors.Is(err, domain.ErrLastAdmin) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.tokenUseCase.RevokeAll(ctx.Request.Context(), userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the role has been successfully changed",
+	})
+}
+
+// Delete godoc
+// @Summary			Delete a user
+// @Description	Delete any account but the last admin, for admins only
+// @Tags				admin
+// @Produce			json
+// @Param				userId	path			string	true	"User ID"
+// @Success			200			{object}	utils.ResponseMessageAdminDeletedUser
+// @Failure			401			{object}	utils.ResponseMessage
+// @Failure			403			{object}	utils.ResponseMessage
+// @Failure			404			{object}	utils.ResponseMessage
+// @Failure			409			{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/admin/users/{userId}	[delete]
+func (handler *adminHandler) Delete(ctx *gin.Context) {
+	userID := ctx.Param("userId")
+
+	if err := handler.userUseCase.Delete(ctx.Request.Context(), userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("user with id %s doesn't exist", userID),
+			})
+
+			return
+		}
+
+		if errors.Is(err, domain.ErrLastAdmin) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the account has been successfully deleted",
+	})
+}
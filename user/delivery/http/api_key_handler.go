@@ -0,0 +1,184 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"mygram-api/middleware"
+	"mygram-api/user/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type apiKeyHandler struct {
+	apiKeyUseCase domain.APIKeyUseCase
+}
+
+func NewAPIKeyHandler(routers *gin.Engine, apiKeyUseCase domain.APIKeyUseCase) {
+	handler := &apiKeyHandler{apiKeyUseCase}
+
+	router := routers.Group("/users/api-keys")
+	{
+		router.Use(middleware.Authentication())
+		router.POST("", handler.Store)
+		router.GET("", handler.Fetch)
+		router.DELETE("/:apiKeyId", handler.Delete)
+	}
+}
+
+// Store godoc
+// @Summary			Create an API key
+// @Description	Create an API key for scripts and integrations. The key is only shown in this response, send it in the X-API-Key header
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.CreateAPIKey	true	"Create API Key"
+// @Success			201		{object}	utils.ResponseDataCreatedAPIKey
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/api-keys	[post]
+func (handler *apiKeyHandler) Store(ctx *gin.Context) {
+	var (
+		createAPIKey utils.CreateAPIKey
+		key          string
+		err          error
+	)
+
+	if err = ctx.ShouldBindJSON(&createAPIKey); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	apiKey := domain.APIKey{
+		UserID:    middleware.CurrentPrincipal(ctx).UserID,
+		Name:      createAPIKey.Name,
+		ExpiresAt: createAPIKey.ExpiresAt,
+	}
+
+	for _, scope := range createAPIKey.Scopes {
+		apiKey.Scopes = append(apiKey.Scopes, domain.Scope(scope))
+	}
+
+	if key, err = handler.apiKeyUseCase.Create(ctx.Request.Context(), &apiKey); err != nil {
+		if errors.Is(err, domain.ErrAPIKeyScopeInvalid) || errors.Is(err, domain.ErrAPIKeyExpiryInvalid) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helpers.ResponseData{
+		Status: "success",
+		Data: utils.CreatedAPIKey{
+			APIKey: apiKeyResponse(apiKey),
+			Key:    key,
+		},
+	})
+}
+
+// Fetch godoc
+// @Summary			Fetch API keys
+// @Description	Fetch the API keys of the authenticated user
+// @Tags				users
+// @Produce			json
+// @Success			200		{object}	utils.ResponseDataAPIKeys
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/api-keys	[get]
+func (handler *apiKeyHandler) Fetch(ctx *gin.Context) {
+	var apiKeys []domain.APIKey
+
+	if err := handler.apiKeyUseCase.Fetch(ctx.Request.Context(), &apiKeys, middleware.CurrentPrincipal(ctx).UserID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	fetchedAPIKeys := make([]utils.APIKey, 0, len(apiKeys))
+
+	for _, apiKey := range apiKeys {
+		fetchedAPIKeys = append(fetchedAPIKeys, apiKeyResponse(apiKey))
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   fetchedAPIKeys,
+	})
+}
+
+// Delete godoc
+// @Summary			Delete an API key
+// @Description	Delete an API key of the authenticated user, it stops working right away
+// @Tags				users
+// @Produce			json
+// @Param				apiKeyId	path		string	true	"API Key ID"
+// @Success			200		{object}	utils.ResponseMessageDeletedAPIKey
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			404		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/api-keys/{apiKeyId}	[delete]
+func (handler *apiKeyHandler) Delete(ctx *gin.Context) {
+	apiKeyID := ctx.Param("apiKeyId")
+
+	if err := handler.apiKeyUseCase.Delete(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, apiKeyID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("API key with id %s doesn't exist", apiKeyID),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the API key has been successfully deleted",
+	})
+}
+
+func apiKeyResponse(apiKey domain.APIKey) utils.APIKey {
+	scopes := make([]string, 0, len(apiKey.Scopes))
+
+	for _, scope := range apiKey.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	return utils.APIKey{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
@@ -0,0 +1,156 @@
+package delivery
+
+import (
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"mygram-api/middleware"
+	"mygram-api/user/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// avatarBodyMaxBytes caps the whole upload request: the 5 MB image the usecase
+// accepts plus room for the multipart framing around it.
+const avatarBodyMaxBytes = 6 << 20
+
+type avatarHandler struct {
+	avatarUseCase domain.AvatarUseCase
+}
+
+func NewAvatarHandler(routers *gin.Engine, avatarUseCase domain.AvatarUseCase) {
+	handler := &avatarHandler{avatarUseCase}
+
+	router := routers.Group("/users/me/avatar")
+	{
+		router.Use(middleware.Authentication())
+		router.PUT("", handler.Update)
+		router.DELETE("", handler.Delete)
+	}
+}
+
+// Update godoc
+// @Summary			Upload an avatar
+// @Description	Replace the avatar of the authenticated user with a JPEG, PNG, GIF or WebP image of at most 5 MB. It is cropped to a square and stored in several sizes without any of its metadata
+// @Tags				users
+// @Accept			multipart/form-data
+// @Produce			json
+// @Param				avatar	formData	file	true	"Avatar image"
+// @Success			200		{object}	utils.ResponseDataAvatar
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			413		{object}	utils.ResponseMessage
+// @Failure			415		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/me/avatar	[put]
+func (handler *avatarHandler) Update(ctx *gin.Context) {
+	// Without a cap FormFile would spool a body of any size to disk before the
+	// usecase ever gets to look at it.
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, avatarBodyMaxBytes)
+
+	fileHeader, err := ctx.FormFile("avatar")
+
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+
+		if errors.As(err, &maxBytesError) {
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: domain.ErrImageTooLarge.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	file, err := fileHeader.Open()
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	defer file.Close()
+
+	avatarSizes, err := handler.avatarUseCase.Set(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, file)
+
+	if err != nil {
+		if errors.Is(err, domain.ErrImageTooLarge) {
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		if errors.Is(err, domain.ErrImageUnsupported) {
+			ctx.AbortWithStatusJSON(http.StatusUnsupportedMediaType, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	avatar := utils.Avatar{
+		ProfileImageUrl: avatarSizes[len(avatarSizes)-1].URL,
+	}
+
+	for _, avatarSize := range avatarSizes {
+		avatar.Sizes = append(avatar.Sizes, utils.AvatarSize{
+			Size: avatarSize.Size,
+			URL:  avatarSize.URL,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   avatar,
+	})
+}
+
+// Delete godoc
+// @Summary			Delete the avatar
+// @Description	Remove the avatar of the authenticated user
+// @Tags				users
+// @Produce			json
+// @Success			200		{object}	utils.ResponseMessageDeletedAvatar
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/me/avatar	[delete]
+func (handler *avatarHandler) Delete(ctx *gin.Context) {
+	if err := handler.avatarUseCase.Delete(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "your avatar has been successfully deleted",
+	})
+}
@@ -0,0 +1,120 @@
+package delivery
+
+import (
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"mygram-api/middleware"
+	"mygram-api/user/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type emailVerificationHandler struct {
+	emailVerificationUseCase domain.EmailVerificationUseCase
+	userUseCase              domain.UserUseCase
+}
+
+func NewEmailVerificationHandler(routers *gin.Engine, emailVerificationUseCase domain.EmailVerificationUseCase, userUseCase domain.UserUseCase) {
+	handler := &emailVerificationHandler{emailVerificationUseCase, userUseCase}
+
+	router := routers.Group("/users/verify-email")
+	{
+		router.POST("", handler.Verify)
+		router.POST("/resend", middleware.Authentication(), handler.Resend)
+	}
+}
+
+// Verify godoc
+// @Summary			Verify an email address
+// @Description	Confirm the email address with the token from the verification email
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.VerifyEmail	true	"Verify Email"
+// @Success			200		{object}	utils.ResponseMessageVerifiedEmail
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			409		{object}	utils.ResponseMessage
+// @Router			/users/verify-email	[post]
+func (handler *emailVerificationHandler) Verify(ctx *gin.Context) {
+	var (
+		verifyEmail utils.VerifyEmail
+		err         error
+	)
+
+	if err = ctx.ShouldBindJSON(&verifyEmail); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.emailVerificationUseCase.Verify(ctx.Request.Context(), verifyEmail.Token); err != nil {
+		if errors.Is(err, domain.ErrEmailAlreadyUsed) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "your email has been successfully verified",
+	})
+}
+
+// Resend godoc
+// @Summary			Resend the verification email
+// @Description	Mail a new verification link for the email address that still needs confirming
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Success			202		{object}	utils.ResponseMessageResentVerification
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			404		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/verify-email/resend	[post]
+func (handler *emailVerificationHandler) Resend(ctx *gin.Context) {
+	var (
+		user domain.User
+		err  error
+	)
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err = handler.userUseCase.GetByID(ctx.Request.Context(), &user, userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: "account not found",
+		})
+
+		return
+	}
+
+	if err = handler.emailVerificationUseCase.Send(ctx.Request.Context(), user); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: "we couldn't send the verification email, please try again later",
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "a new verification link has been sent to your email",
+	})
+}
@@ -0,0 +1,30 @@
+package delivery
+
+import (
+	"mygram-api/helpers"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type jwksHandler struct {
+	keyRing *helpers.KeyRing
+}
+
+func NewJWKSHandler(routers *gin.Engine, keyRing *helpers.KeyRing) {
+	handler := &jwksHandler{keyRing}
+
+	routers.GET("/.well-known/jwks.json", handler.JWKS)
+}
+
+// JWKS godoc
+// @Summary			Fetch the token verification keys
+// @Description	Publish the public keys access tokens are signed with, as a JSON Web Key Set, so other services can verify them
+// @Tags				users
+// @Produce			json
+// @Success			200		{object}	helpers.JWKSet
+// @Router			/.well-known/jwks.json	[get]
+func (handler *jwksHandler) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, handler.keyRing.JWKS())
+}
@@ -0,0 +1,277 @@
+package delivery
+
+import (
+	"errors"
+	"log"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"mygram-api/middleware"
+	"mygram-api/user/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mfaHandler struct {
+	mfaUseCase          domain.MFAUseCase
+	loginAttemptUseCase domain.LoginAttemptUseCase
+	tokenUseCase        domain.TokenUseCase
+}
+
+func NewMFAHandler(routers *gin.Engine, mfaUseCase domain.MFAUseCase, loginAttemptUseCase domain.LoginAttemptUseCase, tokenUseCase domain.TokenUseCase) {
+	handler := &mfaHandler{mfaUseCase, loginAttemptUseCase, tokenUseCase}
+
+	router := routers.Group("/users")
+	{
+		router.POST("/login/2fa", handler.Login)
+		router.POST("/2fa/setup", middleware.Authentication(), handler.Setup)
+		router.POST("/2fa/confirm", middleware.Authentication(), handler.Confirm)
+		router.POST("/2fa/disable", middleware.Authentication(), handler.Disable)
+	}
+}
+
+// Setup godoc
+// @Summary			Set up two-factor authentication
+// @Description	Generate a TOTP secret for the authenticated user. It only takes effect once confirmed with a code
+// @Tags				users
+// @Produce			json
+// @Success			200		{object}	utils.ResponseDataMFASetup
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			409		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/2fa/setup	[post]
+func (handler *mfaHandler) Setup(ctx *gin.Context) {
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	setup, err := handler.mfaUseCase.Setup(ctx.Request.Context(), userID)
+
+	if err != nil {
+		if errors.Is(err, domain.ErrMFAAlreadyEnabled) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.MFASetup{
+			Secret:     setup.Secret,
+			OtpauthURI: setup.URI,
+		},
+	})
+}
+
+// Confirm godoc
+// @Summary			Confirm two-factor authentication
+// @Description	Enable two-factor authentication with a code from the authenticator app and retrieve one-time recovery codes
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.MFAConfirm	true	"Confirm Two-Factor Authentication"
+// @Success			200		{object}	utils.ResponseDataMFARecoveryCodes
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			409		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/2fa/confirm	[post]
+func (handler *mfaHandler) Confirm(ctx *gin.Context) {
+	var (
+		mfaConfirm    utils.MFAConfirm
+		recoveryCodes []string
+		err           error
+	)
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err = ctx.ShouldBindJSON(&mfaConfirm); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if recoveryCodes, err = handler.mfaUseCase.Confirm(ctx.Request.Context(), userID, mfaConfirm.Code); err != nil {
+		if errors.Is(err, domain.ErrMFAAlreadyEnabled) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.MFARecoveryCodes{
+			RecoveryCodes: recoveryCodes,
+		},
+	})
+}
+
+// Disable godoc
+// @Summary			Disable two-factor authentication
+// @Description	Disable two-factor authentication with the password and a current code or a recovery code
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.MFADisable	true	"Disable Two-Factor Authentication"
+// @Success			200		{object}	utils.ResponseMessageMFADisabled
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/2fa/disable	[post]
+func (handler *mfaHandler) Disable(ctx *gin.Context) {
+	var (
+		mfaDisable utils.MFADisable
+		err        error
+	)
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err = ctx.ShouldBindJSON(&mfaDisable); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.mfaUseCase.Disable(ctx.Request.Context(), userID, mfaDisable.Password, mfaDisable.Code); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "two-factor authentication has been disabled",
+	})
+}
+
+// Login godoc
+// @Summary			Complete a two-factor login
+// @Description	Exchange the mfa token from the login response and a current code or a recovery code for an access token and a refresh token
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.MFALogin	true	"Two-Factor Login"
+// @Success			200		{object}	utils.ResponseDataLoggedinUser
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			429		{object}	utils.ResponseMessage
+// @Router			/users/login/2fa	[post]
+func (handler *mfaHandler) Login(ctx *gin.Context) {
+	var (
+		mfaLogin utils.MFALogin
+		user     domain.User
+		token    domain.Token
+		err      error
+	)
+
+	if err = ctx.ShouldBindJSON(&mfaLogin); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if user, err = handler.mfaUseCase.Pending(ctx.Request.Context(), mfaLogin.MFAToken); err != nil {
+		if errors.Is(err, domain.ErrMFAPendingInvalid) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	// Wrong codes count towards the same lockout as wrong passwords, so the six
+	// digits cannot be guessed by anyone who has the password.
+	email := user.Email
+
+	if !checkLoginAttempt(ctx, handler.loginAttemptUseCase, email) {
+		return
+	}
+
+	if user, err = handler.mfaUseCase.Verify(ctx.Request.Context(), mfaLogin.MFAToken, mfaLogin.Code); err != nil {
+		if errors.Is(err, domain.ErrMFACodeInvalid) {
+			if err := handler.loginAttemptUseCase.Fail(ctx.Request.Context(), email, ctx.ClientIP()); err != nil {
+				log.Printf("recording the failed login for %s failed: %s", email, err)
+			}
+		}
+
+		if errors.Is(err, domain.ErrMFAPendingInvalid) || errors.Is(err, domain.ErrMFACodeInvalid) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.loginAttemptUseCase.Succeed(ctx.Request.Context(), email, ctx.ClientIP()); err != nil {
+		log.Printf("clearing the failed logins for %s failed: %s", email, err)
+	}
+
+	if token, err = handler.tokenUseCase.Issue(ctx.Request.Context(), user, requestClient(ctx)); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.LoggedinUser{
+			Token:        token.AccessToken,
+			RefreshToken: token.RefreshToken,
+			TokenType:    "Bearer",
+			ExpiresIn:    token.ExpiresIn,
+		},
+	})
+}
@@ -0,0 +1,150 @@
+package delivery
+
+import (
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"mygram-api/user/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oidcLoginCookie keeps the started login in the browser until the provider
+// sends the user back to the callback.
+const oidcLoginCookie = "oidc_login"
+
+type oidcHandler struct {
+	oidcUseCase  domain.OIDCUseCase
+	mfaUseCase   domain.MFAUseCase
+	tokenUseCase domain.TokenUseCase
+}
+
+func NewOIDCHandler(routers *gin.Engine, oidcUseCase domain.OIDCUseCase, mfaUseCase domain.MFAUseCase, tokenUseCase domain.TokenUseCase) {
+	handler := &oidcHandler{oidcUseCase, mfaUseCase, tokenUseCase}
+
+	router := routers.Group("/auth")
+	{
+		router.GET("/:provider/start", handler.Start)
+		router.GET("/:provider/callback", handler.Callback)
+	}
+}
+
+// Start godoc
+// @Summary			Start signing in with a provider
+// @Description	Redirect to the OpenID Connect provider to sign in with the authorization code flow and PKCE
+// @Tags				users
+// @Param				provider	path		string	true	"Provider"
+// @Success			302
+// @Failure			404		{object}	utils.ResponseMessage
+// @Failure			500		{object}	utils.ResponseMessage
+// @Router			/auth/{provider}/start	[get]
+func (handler *oidcHandler) Start(ctx *gin.Context) {
+	provider := ctx.Param("provider")
+
+	login, err := handler.oidcUseCase.Start(ctx.Request.Context(), provider)
+
+	if err != nil {
+		if errors.Is(err, domain.ErrOIDCProviderUnknown) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	// Lax still sends the cookie along when the provider redirects back.
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcLoginCookie, login.Flow, int(helpers.OIDCLoginTTL().Seconds()), "/auth/"+provider, "", ctx.Request.TLS != nil, true)
+	ctx.Redirect(http.StatusFound, login.URL)
+}
+
+// Callback godoc
+// @Summary			Finish signing in with a provider
+// @Description	The provider redirects here. Links the identity to the account with the same verified email or creates a new account, then answers like /users/login
+// @Tags				users
+// @Produce			json
+// @Param				provider	path		string	true	"Provider"
+// @Param				code			query		string	true	"Authorization Code"
+// @Param				state			query		string	true	"State"
+// @Success			200		{object}	utils.ResponseDataLoggedinUser
+// @Success			202		{object}	utils.ResponseDataMFAPending
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			403		{object}	utils.ResponseMessage
+// @Failure			404		{object}	utils.ResponseMessage
+// @Failure			409		{object}	utils.ResponseMessage
+// @Router			/auth/{provider}/callback	[get]
+func (handler *oidcHandler) Callback(ctx *gin.Context) {
+	var callback utils.OIDCCallback
+
+	provider := ctx.Param("provider")
+	flow, _ := ctx.Cookie(oidcLoginCookie)
+
+	// The login can only be finished once, whatever comes of it.
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcLoginCookie, "", -1, "/auth/"+provider, "", ctx.Request.TLS != nil, true)
+
+	if err := ctx.ShouldBindQuery(&callback); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if callback.Error != "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+			Status:  "unauthenticated",
+			Message: "the provider didn't sign you in: " + callback.Error,
+		})
+
+		return
+	}
+
+	user, err := handler.oidcUseCase.Callback(ctx.Request.Context(), provider, flow, callback.State, callback.Code)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrOIDCProviderUnknown):
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+		case errors.Is(err, domain.ErrOIDCLoginInvalid), errors.Is(err, domain.ErrOIDCCodeExchangeFailed), errors.Is(err, domain.ErrOIDCIDTokenInvalid):
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: err.Error(),
+			})
+		case errors.Is(err, domain.ErrOIDCEmailUnverified):
+			ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+				Status:  "unverified",
+				Message: err.Error(),
+			})
+		case errors.Is(err, domain.ErrOIDCAccountUnverified):
+			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+				Status:  "error",
+				Message: err.Error(),
+			})
+		}
+
+		return
+	}
+
+	signIn(ctx, user, handler.mfaUseCase, handler.tokenUseCase)
+}
@@ -0,0 +1,198 @@
+package delivery
+
+import (
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"mygram-api/middleware"
+	"mygram-api/user/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type passwordHandler struct {
+	passwordResetUseCase domain.PasswordResetUseCase
+	passwordResetQueue   domain.PasswordResetQueue
+	userUseCase          domain.UserUseCase
+	tokenUseCase         domain.TokenUseCase
+}
+
+func NewPasswordHandler(routers *gin.Engine, passwordResetUseCase domain.PasswordResetUseCase, passwordResetQueue domain.PasswordResetQueue, userUseCase domain.UserUseCase, tokenUseCase domain.TokenUseCase) {
+	handler := &passwordHandler{passwordResetUseCase, passwordResetQueue, userUseCase, tokenUseCase}
+
+	router := routers.Group("/users/password")
+	{
+		router.PUT("", middleware.Authentication(), handler.Change)
+		router.POST("/forgot", handler.Forgot)
+		router.POST("/reset", handler.Reset)
+	}
+}
+
+// Change godoc
+// @Summary			Change a password
+// @Description	Change the password of the authenticated user, sign out every other login and retrieve fresh tokens
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.ChangePassword	true	"Change Password"
+// @Success			200		{object}	utils.ResponseDataRefreshedToken
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/password	[put]
+func (handler *passwordHandler) Change(ctx *gin.Context) {
+	var (
+		changePassword utils.ChangePassword
+		user           domain.User
+		token          domain.Token
+		err            error
+	)
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err = ctx.ShouldBindJSON(&changePassword); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.userUseCase.ChangePassword(ctx.Request.Context(), userID, changePassword.CurrentPassword, changePassword.NewPassword); err != nil {
+		if errors.Is(err, domain.ErrPasswordIncorrect) || errors.Is(err, domain.ErrPasswordTooShort) || errors.Is(err, helpers.ErrPasswordTooLong) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.tokenUseCase.RevokeAll(ctx.Request.Context(), userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	// Every token, including the one used for this request, is revoked above, so
+	// hand the caller a new pair to stay signed in on this device.
+	if err = handler.userUseCase.GetByID(ctx.Request.Context(), &user, userID); err == nil {
+		token, err = handler.tokenUseCase.Issue(ctx.Request.Context(), user, requestClient(ctx))
+	}
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.RefreshedToken{
+			Token:        token.AccessToken,
+			RefreshToken: token.RefreshToken,
+			TokenType:    "Bearer",
+			ExpiresIn:    token.ExpiresIn,
+		},
+	})
+}
+
+// Forgot godoc
+// @Summary			Request a password reset
+// @Description	Mail a single-use password reset link to the account registered with the email
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.ForgotPassword	true	"Forgot Password"
+// @Success			202		{object}	utils.ResponseMessageForgotPassword
+// @Failure			400		{object}	utils.ResponseMessage
+// @Router			/users/password/forgot	[post]
+func (handler *passwordHandler) Forgot(ctx *gin.Context) {
+	var (
+		forgotPassword utils.ForgotPassword
+		err            error
+	)
+
+	if err = ctx.ShouldBindJSON(&forgotPassword); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	// The lookup and the email happen after answering, so neither the status nor
+	// the response time tells whether the address is registered.
+	handler.passwordResetQueue.Enqueue(forgotPassword.Email)
+
+	ctx.JSON(http.StatusAccepted, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "if the email you entered is registered, a password reset link has been sent to it",
+	})
+}
+
+// Reset godoc
+// @Summary			Reset a password
+// @Description	Choose a new password with the token from the password reset email and sign out everywhere
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.ResetPassword	true	"Reset Password"
+// @Success			200		{object}	utils.ResponseMessageResetPassword
+// @Failure			400		{object}	utils.ResponseMessage
+// @Router			/users/password/reset	[post]
+func (handler *passwordHandler) Reset(ctx *gin.Context) {
+	var (
+		resetPassword utils.ResetPassword
+		err           error
+	)
+
+	if err = ctx.ShouldBindJSON(&resetPassword); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.passwordResetUseCase.Reset(ctx.Request.Context(), resetPassword.Token, resetPassword.Password); err != nil {
+		if errors.Is(err, domain.ErrPasswordResetInvalid) || errors.Is(err, domain.ErrPasswordTooShort) || errors.Is(err, helpers.ErrPasswordTooLong) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "your password has been successfully reset",
+	})
+}
@@ -0,0 +1,154 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"mygram-api/middleware"
+	"mygram-api/user/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type sessionHandler struct {
+	sessionUseCase domain.SessionUseCase
+	tokenUseCase   domain.TokenUseCase
+}
+
+func NewSessionHandler(routers *gin.Engine, sessionUseCase domain.SessionUseCase, tokenUseCase domain.TokenUseCase) {
+	handler := &sessionHandler{sessionUseCase, tokenUseCase}
+
+	router := routers.Group("/users/sessions")
+	{
+		router.Use(middleware.Authentication())
+		router.GET("", handler.Fetch)
+		router.GET("/history", handler.History)
+		router.DELETE("/:sessionId", handler.Delete)
+	}
+}
+
+// Fetch godoc
+// @Summary			Fetch sessions
+// @Description	Fetch the devices the authenticated user is logged in on, the one making the request is marked as current
+// @Tags				users
+// @Produce			json
+// @Success			200		{object}	utils.ResponseDataSessions
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/sessions	[get]
+func (handler *sessionHandler) Fetch(ctx *gin.Context) {
+	var sessions []domain.Session
+
+	principal := middleware.CurrentPrincipal(ctx)
+
+	if err := handler.sessionUseCase.Fetch(ctx.Request.Context(), &sessions, principal.UserID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	fetchedSessions := make([]utils.Session, 0, len(sessions))
+
+	for _, session := range sessions {
+		fetchedSessions = append(fetchedSessions, utils.Session{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			Current:    session.ID == principal.SessionID,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			CreatedAt:  session.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   fetchedSessions,
+	})
+}
+
+// History godoc
+// @Summary			Fetch the login history
+// @Description	Fetch the latest logins of the authenticated user, including the sessions that have ended
+// @Tags				users
+// @Produce			json
+// @Success			200		{object}	utils.ResponseDataLoginHistory
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/sessions/history	[get]
+func (handler *sessionHandler) History(ctx *gin.Context) {
+	var sessions []domain.Session
+
+	if err := handler.sessionUseCase.History(ctx.Request.Context(), &sessions, middleware.CurrentPrincipal(ctx).UserID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	now := time.Now()
+	history := make([]utils.LoginHistoryEntry, 0, len(sessions))
+
+	for _, session := range sessions {
+		history = append(history, utils.LoginHistoryEntry{
+			ID:        session.ID,
+			UserAgent: session.UserAgent,
+			IP:        session.IP,
+			Active:    session.RevokedAt == nil && session.ExpiresAt != nil && session.ExpiresAt.After(now),
+			RevokedAt: session.RevokedAt,
+			CreatedAt: session.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   history,
+	})
+}
+
+// Delete godoc
+// @Summary			End a session
+// @Description	Log the authenticated user out on one of their devices, its tokens stop working right away
+// @Tags				users
+// @Produce			json
+// @Param				sessionId	path		string	true	"Session ID"
+// @Success			200		{object}	utils.ResponseMessageRevokedSession
+// @Failure			401		{object}	utils.ResponseMessage
+// @Failure			404		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/sessions/{sessionId}	[delete]
+func (handler *sessionHandler) Delete(ctx *gin.Context) {
+	sessionID := ctx.Param("sessionId")
+
+	if err := handler.tokenUseCase.RevokeSession(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, sessionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("session with id %s doesn't exist", sessionID),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the session has been successfully ended",
+	})
+}
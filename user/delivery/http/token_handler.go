@@ -0,0 +1,186 @@
+package delivery
+
+import (
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"mygram-api/middleware"
+	"mygram-api/user/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type tokenHandler struct {
+	tokenUseCase domain.TokenUseCase
+}
+
+func NewTokenHandler(routers *gin.Engine, tokenUseCase domain.TokenUseCase) {
+	handler := &tokenHandler{tokenUseCase}
+
+	router := routers.Group("/users")
+	{
+		router.POST("/token/refresh", handler.Refresh)
+		router.POST("/logout", middleware.Authentication(), handler.Logout)
+		router.POST("/logout-all", middleware.Authentication(), handler.LogoutAll)
+	}
+}
+
+// Refresh godoc
+// @Summary			Refresh an access token
+// @Description	Exchange a refresh token for a new access token and a rotated refresh token
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.RefreshToken	true	"Refresh Token"
+// @Success			200		{object}	utils.ResponseDataRefreshedToken
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Router			/users/token/refresh	[post]
+func (handler *tokenHandler) Refresh(ctx *gin.Context) {
+	var (
+		refreshToken utils.RefreshToken
+		token        domain.Token
+		err          error
+	)
+
+	if err = ctx.ShouldBindJSON(&refreshToken); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if token, err = handler.tokenUseCase.Refresh(ctx.Request.Context(), refreshToken.RefreshToken); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenInvalid) || errors.Is(err, domain.ErrRefreshTokenExpired) || errors.Is(err, domain.ErrRefreshTokenReused) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.RefreshedToken{
+			Token:        token.AccessToken,
+			RefreshToken: token.RefreshToken,
+			TokenType:    "Bearer",
+			ExpiresIn:    token.ExpiresIn,
+		},
+	})
+}
+
+// Logout godoc
+// @Summary			Logout a user
+// @Description	End the current session, revoking its access and refresh tokens, and revoke the refresh token given in the body
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.Logout	false	"Logout"
+// @Success			200		{object}	utils.ResponseMessageLoggedOut
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/logout	[post]
+func (handler *tokenHandler) Logout(ctx *gin.Context) {
+	var (
+		logout utils.Logout
+		err    error
+	)
+
+	principal := middleware.CurrentPrincipal(ctx)
+	userID := principal.UserID
+	expiresAt := principal.ExpiresAt
+
+	if ctx.Request.ContentLength > 0 {
+		if err = ctx.ShouldBindJSON(&logout); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+	}
+
+	if err = handler.tokenUseCase.Revoke(ctx.Request.Context(), domain.RevokedToken{
+		JTI:       principal.TokenID,
+		UserID:    userID,
+		ExpiresAt: &expiresAt,
+	}); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if principal.SessionID != "" {
+		if err = handler.tokenUseCase.RevokeSession(ctx.Request.Context(), userID, principal.SessionID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+				Status:  "error",
+				Message: err.Error(),
+			})
+
+			return
+		}
+	}
+
+	if logout.RefreshToken != "" {
+		if err = handler.tokenUseCase.RevokeRefreshToken(ctx.Request.Context(), userID, logout.RefreshToken); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+				Status:  "error",
+				Message: err.Error(),
+			})
+
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "you have been successfully logged out",
+	})
+}
+
+// LogoutAll godoc
+// @Summary			Logout a user everywhere
+// @Description	Revoke every access token and refresh token of the authenticated user
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Success			200		{object}	utils.ResponseMessageLoggedOut
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/logout-all	[post]
+func (handler *tokenHandler) LogoutAll(ctx *gin.Context) {
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err := handler.tokenUseCase.RevokeAll(ctx.Request.Context(), userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "you have been successfully logged out from every device",
+	})
+}
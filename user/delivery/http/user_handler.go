@@ -1,36 +1,49 @@
 package delivery
 
 import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"mygram-api/domain"
 	"mygram-api/helpers"
-	"mygram-api/user/delivery/http/middleware"
+	"mygram-api/middleware"
 	"mygram-api/user/utils"
 	"net/http"
+	"strconv"
 	"strings"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type userHandler struct {
-	userUseCase domain.UserUseCase
+	userUseCase              domain.UserUseCase
+	tokenUseCase             domain.TokenUseCase
+	emailVerificationUseCase domain.EmailVerificationUseCase
+	mfaUseCase               domain.MFAUseCase
+	loginAttemptUseCase      domain.LoginAttemptUseCase
 }
 
-func NewUserHandler(routers *gin.Engine, userUseCase domain.UserUseCase) {
-	handler := &userHandler{userUseCase}
+func NewUserHandler(routers *gin.Engine, userUseCase domain.UserUseCase, tokenUseCase domain.TokenUseCase, emailVerificationUseCase domain.EmailVerificationUseCase, mfaUseCase domain.MFAUseCase, loginAttemptUseCase domain.LoginAttemptUseCase) {
+	handler := &userHandler{userUseCase, tokenUseCase, emailVerificationUseCase, mfaUseCase, loginAttemptUseCase}
 
 	router := routers.Group("/users")
 	{
 		router.POST("/register", handler.Register)
 		router.POST("/login", handler.Login)
+		router.GET("/me", middleware.Authentication(), handler.Me)
+		router.PUT("/me", middleware.Authentication(), handler.UpdateProfile)
+		router.GET("/:username", handler.Profile)
 		router.PUT("", middleware.Authentication(), handler.Update)
+		router.PATCH("", middleware.Authentication(), handler.Update)
 		router.DELETE("", middleware.Authentication(), handler.Delete)
 	}
 }
 
 // Register godoc
 // @Summary			Register a user
-// @Description	create and store a user
+// @Description	create and store a user and mail a link to verify the email address
 // @Tags				users
 // @Accept			json
 // @Produce			json
@@ -55,19 +68,10 @@ func (handler *userHandler) Register(ctx *gin.Context) {
 	}
 
 	if err = handler.userUseCase.Register(ctx.Request.Context(), &user); err != nil {
-		if strings.Contains(err.Error(), "idx_users_username") {
+		if message, ok := conflictMessage(err); ok {
 			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
 				Status:  "fail",
-				Message: "the username you entered has been used",
-			})
-
-			return
-		}
-
-		if strings.Contains(err.Error(), "idx_users_email") {
-			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
-				Status:  "fail",
-				Message: "the email you entered has been used",
+				Message: message,
 			})
 
 			return
@@ -81,6 +85,11 @@ func (handler *userHandler) Register(ctx *gin.Context) {
 		return
 	}
 
+	// The account exists at this point; a lost email can be re-sent from /users/verify-email/resend.
+	if err = handler.emailVerificationUseCase.Send(ctx.Request.Context(), user); err != nil {
+		log.Printf("sending the verification email to user %s failed: %s", user.ID, err)
+	}
+
 	ctx.JSON(http.StatusCreated, helpers.ResponseData{
 		Status: "success",
 		Data: utils.RegisteredUser{
@@ -94,20 +103,21 @@ func (handler *userHandler) Register(ctx *gin.Context) {
 
 // Login godoc
 // @Summary			Login a user
-// @Description	Authentication a user and retrieve a token
+// @Description	Authentication a user and retrieve an access token and a refresh token. Users with two-factor authentication get an mfa token to complete the login at /users/login/2fa instead
 // @Tags				users
 // @Accept			json
 // @Produce			json
 // @Param				json	body			utils.LoginUser	true	"Login User"
 // @Success			200		{object}	utils.ResponseDataLoggedinUser
+// @Success			202		{object}	utils.ResponseDataMFAPending
 // @Failure			400		{object}	utils.ResponseMessage
 // @Failure			401		{object}	utils.ResponseMessage
+// @Failure			429		{object}	utils.ResponseMessage
 // @Router			/users/login		[post]
 func (handler *userHandler) Login(ctx *gin.Context) {
 	var (
-		user  domain.User
-		err   error
-		token string
+		user domain.User
+		err  error
 	)
 
 	if err = ctx.ShouldBindJSON(&user); err != nil {
@@ -119,8 +129,18 @@ func (handler *userHandler) Login(ctx *gin.Context) {
 		return
 	}
 
+	email := user.Email
+
+	if !checkLoginAttempt(ctx, handler.loginAttemptUseCase, email) {
+		return
+	}
+
 	if err = handler.userUseCase.Login(ctx.Request.Context(), &user); err != nil {
-		if strings.Contains(err.Error(), "the credential you entered are wrong") {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			if err := handler.loginAttemptUseCase.Fail(ctx.Request.Context(), email, ctx.ClientIP()); err != nil {
+				log.Printf("recording the failed login for %s failed: %s", email, err)
+			}
+
 			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 				Status:  "unauthenticated",
 				Message: err.Error(),
@@ -129,18 +149,147 @@ func (handler *userHandler) Login(ctx *gin.Context) {
 			return
 		}
 
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	// With two-factor authentication the failures are only cleared once the second
+	// factor passes too, at /users/login/2fa.
+	if user.TOTPEnabledAt == nil {
+		if err = handler.loginAttemptUseCase.Succeed(ctx.Request.Context(), email, ctx.ClientIP()); err != nil {
+			log.Printf("clearing the failed logins for %s failed: %s", email, err)
+		}
+	}
+
+	signIn(ctx, user, handler.mfaUseCase, handler.tokenUseCase)
+}
+
+// Me godoc
+// @Summary			Fetch the authenticated user
+// @Description	Fetch the profile of the authenticated user, including the private fields only they get to see
+// @Tags				users
+// @Produce			json
+// @Success			200		{object}	utils.ResponseDataMyProfile
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/me	[get]
+func (handler *userHandler) Me(ctx *gin.Context) {
+	var profile domain.Profile
+
+	if err := handler.userUseCase.GetProfileByID(ctx.Request.Context(), &profile, middleware.CurrentPrincipal(ctx).UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: "account not found",
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   myProfileResponse(profile),
+	})
+}
+
+// Profile godoc
+// @Summary			Fetch a user profile
+// @Description	Fetch the public profile of a user by their username
+// @Tags				users
+// @Produce			json
+// @Param				username	path		string	true	"Username"
+// @Success			200		{object}	utils.ResponseDataPublicProfile
+// @Failure			404		{object}	utils.ResponseMessage
+// @Router			/users/{username}	[get]
+func (handler *userHandler) Profile(ctx *gin.Context) {
+	var profile domain.Profile
+
+	username := ctx.Param("username")
+
+	if err := handler.userUseCase.GetProfileByUsername(ctx.Request.Context(), &profile, username); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("user with username %s doesn't exist", username),
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data:   publicProfileResponse(profile),
+	})
+}
+
+// UpdateProfile godoc
+// @Summary			Update the profile of the authenticated user
+// @Description	Update the display name, bio, age and avatar of the authenticated user. Fields left out stay as they are
+// @Tags				users
+// @Accept			json
+// @Produce			json
+// @Param				json	body			utils.UpdateProfile	true	"Update Profile"
+// @Success			200		{object}	utils.ResponseDataMyProfile
+// @Failure			400		{object}	utils.ResponseMessage
+// @Failure			401		{object}	utils.ResponseMessage
+// @Security		Bearer
+// @Router			/users/me	[put]
+func (handler *userHandler) UpdateProfile(ctx *gin.Context) {
+	var (
+		updateProfile utils.UpdateProfile
+		profile       domain.Profile
+		err           error
+	)
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err = ctx.ShouldBindJSON(&updateProfile); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
-			Status:  "unauthenticated",
+			Status:  "fail",
 			Message: err.Error(),
 		})
 
 		return
 	}
 
-	if token = helpers.GenerateToken(user.ID, user.Email); err != nil {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error":   "unauthenticated",
-			"message": err.Error(),
+	if _, err = handler.userUseCase.Update(ctx.Request.Context(), userID, domain.UserUpdate{
+		DisplayName:     updateProfile.DisplayName,
+		Bio:             updateProfile.Bio,
+		Age:             updateProfile.Age,
+		ProfileImageUrl: updateProfile.ProfileImageUrl,
+	}); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if err = handler.userUseCase.GetProfileByID(ctx.Request.Context(), &profile, userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
 		})
 
 		return
@@ -148,15 +297,13 @@ func (handler *userHandler) Login(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, helpers.ResponseData{
 		Status: "success",
-		Data: utils.LoggedinUser{
-			Token: token,
-		},
+		Data:   myProfileResponse(profile),
 	})
 }
 
 // Update godoc
 // @Summary			Update a user
-// @Description	Update a user with authentication user
+// @Description	Update the fields of the authenticated user that are present in the body, the others stay as they are. A new email only replaces the current one once it is verified
 // @Tags				users
 // @Accept			json
 // @Produce			json
@@ -164,19 +311,21 @@ func (handler *userHandler) Login(ctx *gin.Context) {
 // @Success			200			{object}  utils.ResponseDataUpdatedUser
 // @Failure			400			{object}	utils.ResponseMessage
 // @Failure			401			{object}	utils.ResponseMessage
+// @Failure			404			{object}	utils.ResponseMessage
 // @Failure			409			{object}	utils.ResponseMessage
 // @Security		Bearer
 // @Router			/users	[put]
+// @Router			/users	[patch]
 func (handler *userHandler) Update(ctx *gin.Context) {
 	var (
-		user domain.User
-		err  error
+		updateUser utils.UpdateUser
+		user       domain.User
+		err        error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	_ = string(userData["id"].(string))
+	userID := middleware.CurrentPrincipal(ctx).UserID
 
-	if err = ctx.ShouldBindJSON(&user); err != nil {
+	if err = ctx.ShouldBindJSON(&updateUser); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -185,32 +334,86 @@ func (handler *userHandler) Update(ctx *gin.Context) {
 		return
 	}
 
-	updatedUser := domain.User{
-		Username: user.Username,
-		Email:    user.Email,
+	userUpdate := domain.UserUpdate{
+		Username:        updateUser.Username,
+		Age:             updateUser.Age,
+		ProfileImageUrl: updateUser.ProfileImageUrl,
+		Bio:             updateUser.Bio,
 	}
 
-	if user, err = handler.userUseCase.Update(ctx.Request.Context(), updatedUser); err != nil {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
-			Status:  "fail",
-			Message: err.Error(),
-		})
+	// The new email is checked before anything is written and stored with the
+	// other fields, so a taken or invalid one leaves the account untouched.
+	if updateUser.Email != nil {
+		if userUpdate.PendingEmail, err = handler.emailVerificationUseCase.PrepareEmailChange(ctx.Request.Context(), userID, *updateUser.Email); err != nil {
+			abortUpdate(ctx, err)
+
+			return
+		}
+	}
+
+	if user, err = handler.userUseCase.Update(ctx.Request.Context(), userID, userUpdate); err != nil {
+		abortUpdate(ctx, err)
 
 		return
 	}
 
+	if userUpdate.PendingEmail != nil {
+		// The change is stored at this point; a lost email can be re-sent from /users/verify-email/resend.
+		if err = handler.emailVerificationUseCase.Send(ctx.Request.Context(), user); err != nil {
+			log.Printf("sending the verification email to user %s failed: %s", user.ID, err)
+		}
+	}
+
 	ctx.JSON(http.StatusOK, helpers.ResponseData{
 		Status: "success",
 		Data: utils.UpdatedUser{
-			ID:        user.ID,
-			Email:     user.Email,
-			Username:  user.Username,
-			Age:       user.Age,
-			UpdatedAt: user.UpdatedAt,
+			ID:              user.ID,
+			Email:           user.Email,
+			PendingEmail:    user.PendingEmail,
+			Username:        user.Username,
+			Age:             user.Age,
+			ProfileImageUrl: user.ProfileImageUrl,
+			Bio:             user.Bio,
+			UpdatedAt:       user.UpdatedAt,
 		},
 	})
 }
 
+// abortUpdate answers a failed update of the authenticated user.
+func abortUpdate(ctx *gin.Context, err error) {
+	if message, ok := conflictMessage(err); ok {
+		ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: message,
+		})
+
+		return
+	}
+
+	if errors.Is(err, domain.ErrEmailAlreadyUsed) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: "account not found",
+		})
+
+		return
+	}
+
+	ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+		Status:  "fail",
+		Message: err.Error(),
+	})
+}
+
 // Delete godoc
 // @Summary			Delete a user
 // @Description	Delete a user with authentication user
@@ -221,16 +424,34 @@ func (handler *userHandler) Update(ctx *gin.Context) {
 // @Failure			400			{object}	utils.ResponseMessage
 // @Failure			401			{object}	utils.ResponseMessage
 // @Failure			404			{object}	utils.ResponseMessage
+// @Failure			409			{object}	utils.ResponseMessage
 // @Security		Bearer
 // @Router			/users	[delete]
 func (handler *userHandler) Delete(ctx *gin.Context) {
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	userID := middleware.CurrentPrincipal(ctx).UserID
 
-	if err := handler.userUseCase.Delete(ctx, userID); err != nil {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
-			Status:  "fail",
-			Message: "account not found",
+	if err := handler.userUseCase.Delete(ctx.Request.Context(), userID); err != nil {
+		if errors.Is(err, domain.ErrLastAdmin) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: "account not found",
+			})
+
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
 		})
 
 		return
@@ -244,3 +465,137 @@ func (handler *userHandler) Delete(ctx *gin.Context) {
 		},
 	)
 }
+
+// checkLoginAttempt answers 429 with a Retry-After header while earlier failures
+// still hold back logins for email or the client address, and reports whether the
+// login may go on.
+func checkLoginAttempt(ctx *gin.Context, loginAttemptUseCase domain.LoginAttemptUseCase, email string) bool {
+	throttled := &domain.LoginThrottledError{}
+
+	if err := loginAttemptUseCase.Check(ctx.Request.Context(), email, ctx.ClientIP()); err != nil {
+		if errors.As(err, &throttled) {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return false
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return false
+	}
+
+	return true
+}
+
+// signIn answers a successful first factor: with an mfa token when the user has
+// two-factor authentication enabled, and with their tokens otherwise.
+func signIn(ctx *gin.Context, user domain.User, mfaUseCase domain.MFAUseCase, tokenUseCase domain.TokenUseCase) {
+	if user.TOTPEnabledAt != nil {
+		mfaToken, err := mfaUseCase.Challenge(ctx.Request.Context(), user)
+
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+				Status:  "error",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, helpers.ResponseData{
+			Status: "mfa_required",
+			Data: utils.MFAPending{
+				MFAToken:  mfaToken,
+				TokenType: "mfa_pending",
+				ExpiresIn: int64(helpers.MFAPendingTTL().Seconds()),
+			},
+		})
+
+		return
+	}
+
+	token, err := tokenUseCase.Issue(ctx.Request.Context(), user, requestClient(ctx))
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, helpers.ResponseMessage{
+			Status:  "error",
+			Message: err.Error(),
+		})
+
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseData{
+		Status: "success",
+		Data: utils.LoggedinUser{
+			Token:        token.AccessToken,
+			RefreshToken: token.RefreshToken,
+			TokenType:    "Bearer",
+			ExpiresIn:    token.ExpiresIn,
+		},
+	})
+}
+
+// conflictMessage tells which unique field of a user err complains about, going
+// by the name of the index the database reports.
+func conflictMessage(err error) (string, bool) {
+	if strings.Contains(err.Error(), "idx_users_username") {
+		return "the username you entered has been used", true
+	}
+
+	if strings.Contains(err.Error(), "idx_users_email") {
+		return domain.ErrEmailAlreadyUsed.Error(), true
+	}
+
+	return "", false
+}
+
+func publicProfileResponse(profile domain.Profile) utils.PublicProfile {
+	socialMedias := make([]utils.ProfileSocialMedia, 0, len(profile.SocialMedias))
+
+	for _, socialMedia := range profile.SocialMedias {
+		socialMedias = append(socialMedias, utils.ProfileSocialMedia{
+			ID:             socialMedia.ID,
+			Name:           socialMedia.Name,
+			SocialMediaUrl: socialMedia.SocialMediaUrl,
+		})
+	}
+
+	return utils.PublicProfile{
+		ID:              profile.User.ID,
+		Username:        profile.User.Username,
+		DisplayName:     profile.User.DisplayName,
+		Bio:             profile.User.Bio,
+		ProfileImageUrl: profile.User.ProfileImageUrl,
+		PhotoCount:      profile.PhotoCount,
+		FollowerCount:   profile.FollowerCount,
+		SocialMedias:    socialMedias,
+		CreatedAt:       profile.User.CreatedAt,
+	}
+}
+
+func myProfileResponse(profile domain.Profile) utils.MyProfile {
+	return utils.MyProfile{
+		PublicProfile: publicProfileResponse(profile),
+		Email:         profile.User.Email,
+		PendingEmail:  profile.User.PendingEmail,
+		EmailVerified: profile.User.EmailVerifiedAt != nil,
+		Age:           profile.User.Age,
+		Role:          string(profile.User.Role),
+		MFAEnabled:    profile.User.TOTPEnabledAt != nil,
+	}
+}
+
+func requestClient(ctx *gin.Context) domain.Client {
+	return domain.Client{
+		UserAgent: ctx.Request.UserAgent(),
+		IP:        ctx.ClientIP(),
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"mygram-api/domain"
+	"sync"
+	"time"
+)
+
+type loginAttemptEntry struct {
+	attempt   domain.LoginAttempt
+	expiresAt time.Time
+}
+
+// loginAttemptStore keeps failure counters in process memory. It suits a single
+// instance; several instances behind a load balancer each count on their own.
+type loginAttemptStore struct {
+	mu          sync.Mutex
+	entries     map[string]loginAttemptEntry
+	lastEvicted time.Time
+}
+
+func NewLoginAttemptStore() *loginAttemptStore {
+	return &loginAttemptStore{entries: map[string]loginAttemptEntry{}}
+}
+
+func (loginAttemptStore *loginAttemptStore) Get(ctx context.Context, key string) (domain.LoginAttempt, error) {
+	loginAttemptStore.mu.Lock()
+
+	defer loginAttemptStore.mu.Unlock()
+
+	entry, ok := loginAttemptStore.entries[key]
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		return domain.LoginAttempt{}, nil
+	}
+
+	return entry.attempt, nil
+}
+
+func (loginAttemptStore *loginAttemptStore) Fail(ctx context.Context, key string, at time.Time, ttl time.Duration) (domain.LoginAttempt, error) {
+	loginAttemptStore.mu.Lock()
+
+	defer loginAttemptStore.mu.Unlock()
+
+	loginAttemptStore.evictExpired(at)
+
+	entry, ok := loginAttemptStore.entries[key]
+
+	if !ok || at.After(entry.expiresAt) {
+		entry = loginAttemptEntry{}
+	}
+
+	entry.attempt.Failures++
+	entry.attempt.LastFailureAt = at
+	entry.expiresAt = at.Add(ttl)
+
+	loginAttemptStore.entries[key] = entry
+
+	return entry.attempt, nil
+}
+
+func (loginAttemptStore *loginAttemptStore) Reset(ctx context.Context, key string) error {
+	loginAttemptStore.mu.Lock()
+
+	defer loginAttemptStore.mu.Unlock()
+
+	delete(loginAttemptStore.entries, key)
+
+	return nil
+}
+
+// evictExpired drops stale counters at most once a minute so a flood of one-off
+// keys, e.g. from rotating IP addresses, cannot grow the map without bound.
+func (loginAttemptStore *loginAttemptStore) evictExpired(now time.Time) {
+	if now.Sub(loginAttemptStore.lastEvicted) < time.Minute {
+		return
+	}
+
+	for key, entry := range loginAttemptStore.entries {
+		if now.After(entry.expiresAt) {
+			delete(loginAttemptStore.entries, key)
+		}
+	}
+
+	loginAttemptStore.lastEvicted = now
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+// lastUsedPrecision bounds how often a busy key writes its last use.
+const lastUsedPrecision = time.Minute
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *apiKeyRepository {
+	return &apiKeyRepository{db}
+}
+
+func (apiKeyRepository *apiKeyRepository) Store(ctx context.Context, apiKey *domain.APIKey) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	apiKey.ID = fmt.Sprintf("apikey-%s", ID)
+
+	if err = apiKeyRepository.db.WithContext(ctx).Create(apiKey).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (apiKeyRepository *apiKeyRepository) Fetch(ctx context.Context, apiKeys *[]domain.APIKey, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = apiKeyRepository.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(apiKeys).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (apiKeyRepository *apiKeyRepository) GetByHash(ctx context.Context, apiKey *domain.APIKey, hash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = apiKeyRepository.db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "email", "email_verified_at", "role")
+	}).Where("key_hash = ?", hash).Take(apiKey).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Touch records that the key was used at the given time, unless a use within
+// the last minute has already been recorded.
+func (apiKeyRepository *apiKeyRepository) Touch(ctx context.Context, id string, at time.Time) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = apiKeyRepository.db.WithContext(ctx).Model(&domain.APIKey{}).Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, at.Add(-lastUsedPrecision)).UpdateColumn("last_used_at", at).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (apiKeyRepository *apiKeyRepository) Delete(ctx context.Context, userID string, id string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := apiKeyRepository.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&domain.APIKey{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
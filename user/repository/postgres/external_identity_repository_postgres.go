@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+type externalIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewExternalIdentityRepository(db *gorm.DB) *externalIdentityRepository {
+	return &externalIdentityRepository{db}
+}
+
+func (externalIdentityRepository *externalIdentityRepository) GetBySubject(ctx context.Context, externalIdentity *domain.ExternalIdentity, provider string, subject string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = externalIdentityRepository.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).Take(externalIdentity).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (externalIdentityRepository *externalIdentityRepository) Store(ctx context.Context, externalIdentity *domain.ExternalIdentity) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	externalIdentity.ID = fmt.Sprintf("identity-%s", ID)
+
+	if err = externalIdentityRepository.db.WithContext(ctx).Create(externalIdentity).Error; err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+type lockoutEventRepository struct {
+	db *gorm.DB
+}
+
+func NewLockoutEventRepository(db *gorm.DB) *lockoutEventRepository {
+	return &lockoutEventRepository{db}
+}
+
+func (lockoutEventRepository *lockoutEventRepository) Store(ctx context.Context, lockoutEvent *domain.LockoutEvent) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	lockoutEvent.ID = fmt.Sprintf("lockoutevent-%s", ID)
+
+	if err = lockoutEventRepository.db.WithContext(ctx).Create(lockoutEvent).Error; err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) *mfaRepository {
+	return &mfaRepository{db}
+}
+
+// SetTOTPSecret stores a secret that is not yet in use. Setting up again before
+// confirming simply replaces it.
+func (mfaRepository *mfaRepository) SetTOTPSecret(ctx context.Context, userID string, secret string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := mfaRepository.db.WithContext(ctx).Model(&domain.User{}).Where("id = ? AND totp_enabled_at IS NULL", userID).UpdateColumn("totp_secret", secret)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMFAAlreadyEnabled
+	}
+
+	return
+}
+
+// EnableTOTP turns the stored secret on, remembering step as already used, and
+// replaces the recovery codes of the user with recoveryCodes.
+func (mfaRepository *mfaRepository) EnableTOTP(ctx context.Context, userID string, step int64, recoveryCodes []domain.RecoveryCode) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	for i := range recoveryCodes {
+		ID, _ := gonanoid.New(16)
+
+		recoveryCodes[i].ID = fmt.Sprintf("recoverycode-%s", ID)
+		recoveryCodes[i].UserID = userID
+	}
+
+	return mfaRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.User{}).Where("id = ? AND totp_enabled_at IS NULL AND totp_secret <> ''", userID).UpdateColumns(map[string]interface{}{
+			"totp_enabled_at": time.Now(),
+			"totp_last_step":  step,
+		})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrMFAAlreadyEnabled
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&recoveryCodes).Error
+	})
+}
+
+func (mfaRepository *mfaRepository) DisableTOTP(ctx context.Context, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	return mfaRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.User{}).Where("id = ?", userID).UpdateColumns(map[string]interface{}{
+			"totp_secret":     "",
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error
+	})
+}
+
+// UseTOTPStep records step as the last one a code was accepted for. A step at or
+// before the recorded one is a replayed code and is reported as invalid.
+func (mfaRepository *mfaRepository) UseTOTPStep(ctx context.Context, userID string, step int64) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := mfaRepository.db.WithContext(ctx).Model(&domain.User{}).Where("id = ? AND totp_last_step < ?", userID, step).UpdateColumn("totp_last_step", step)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMFACodeInvalid
+	}
+
+	return
+}
+
+func (mfaRepository *mfaRepository) UseRecoveryCode(ctx context.Context, userID string, hash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := mfaRepository.db.WithContext(ctx).Model(&domain.RecoveryCode{}).Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMFACodeInvalid
+	}
+
+	return
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) *passwordResetRepository {
+	return &passwordResetRepository{db}
+}
+
+// Store saves a new reset and retires every reset the user has not used yet, so
+// only the most recently mailed link works.
+func (passwordResetRepository *passwordResetRepository) Store(ctx context.Context, passwordReset *domain.PasswordReset) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	passwordReset.ID = fmt.Sprintf("passwordreset-%s", ID)
+
+	return passwordResetRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.PasswordReset{}).Where("user_id = ? AND used_at IS NULL", passwordReset.UserID).Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(passwordReset).Error
+	})
+}
+
+// Consume marks the reset identified by hash as used and loads it into
+// passwordReset. Used or expired resets are reported as not found.
+func (passwordResetRepository *passwordResetRepository) Consume(ctx context.Context, passwordReset *domain.PasswordReset, hash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := passwordResetRepository.db.WithContext(ctx).Model(passwordReset).Clauses(clause.Returning{}).Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hash, time.Now()).Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
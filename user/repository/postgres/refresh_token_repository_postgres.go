@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *refreshTokenRepository {
+	return &refreshTokenRepository{db}
+}
+
+func (refreshTokenRepository *refreshTokenRepository) Store(ctx context.Context, refreshToken *domain.RefreshToken) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	refreshToken.ID = fmt.Sprintf("refreshtoken-%s", ID)
+
+	if refreshToken.FamilyID == "" {
+		refreshToken.FamilyID = refreshToken.ID
+	}
+
+	if err = refreshTokenRepository.db.WithContext(ctx).Create(refreshToken).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (refreshTokenRepository *refreshTokenRepository) GetByHash(ctx context.Context, refreshToken *domain.RefreshToken, hash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = refreshTokenRepository.db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "email", "email_verified_at", "role")
+	}).Where("token_hash = ?", hash).Take(refreshToken).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Rotate retires current and stores next in one transaction. The conditional update
+// makes two concurrent refreshes with the same token race safely: the loser sees
+// zero affected rows and is treated as a replay.
+func (refreshTokenRepository *refreshTokenRepository) Rotate(ctx context.Context, current *domain.RefreshToken, next *domain.RefreshToken) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	next.ID = fmt.Sprintf("refreshtoken-%s", ID)
+	next.FamilyID = current.FamilyID
+
+	return refreshTokenRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.RefreshToken{}).Where("id = ? AND revoked_at IS NULL", current.ID).Updates(map[string]interface{}{
+			"revoked_at":  time.Now(),
+			"replaced_by": next.ID,
+		})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrRefreshTokenReused
+		}
+
+		return tx.Create(next).Error
+	})
+}
+
+func (refreshTokenRepository *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = refreshTokenRepository.db.WithContext(ctx).Model(&domain.RefreshToken{}).Where("family_id = ? AND revoked_at IS NULL", familyID).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (refreshTokenRepository *refreshTokenRepository) RevokeUser(ctx context.Context, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = refreshTokenRepository.db.WithContext(ctx).Model(&domain.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	return
+}
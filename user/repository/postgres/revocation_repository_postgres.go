@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"mygram-api/domain"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// revocationRepository answers IsRevoked on every authenticated request, so lookups
+// go through an in-memory cache. Revocations made by this process are written
+// through immediately; everything else is re-read from Postgres once cacheTTL lapses
+// so revocations made by other instances are picked up.
+type revocationRepository struct {
+	db       *gorm.DB
+	cacheTTL time.Duration
+
+	mu          sync.RWMutex
+	tokens      map[string]revocationCacheEntry
+	sessions    map[string]revocationCacheEntry
+	cutoffs     map[string]revocationCacheEntry
+	lastEvicted time.Time
+}
+
+type revocationCacheEntry struct {
+	revoked    bool
+	cutoff     *time.Time
+	validUntil time.Time
+}
+
+func NewRevocationRepository(db *gorm.DB, cacheTTL time.Duration) *revocationRepository {
+	return &revocationRepository{
+		db:       db,
+		cacheTTL: cacheTTL,
+		tokens:   map[string]revocationCacheEntry{},
+		sessions: map[string]revocationCacheEntry{},
+		cutoffs:  map[string]revocationCacheEntry{},
+	}
+}
+
+func (revocationRepository *revocationRepository) RevokeToken(ctx context.Context, revokedToken domain.RevokedToken) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = revocationRepository.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&revokedToken).Error; err != nil {
+		return err
+	}
+
+	if err = revocationRepository.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&domain.RevokedToken{}).Error; err != nil {
+		return err
+	}
+
+	revocationRepository.mu.Lock()
+	revocationRepository.tokens[revokedToken.JTI] = revocationCacheEntry{revoked: true, validUntil: *revokedToken.ExpiresAt}
+	revocationRepository.mu.Unlock()
+
+	return
+}
+
+func (revocationRepository *revocationRepository) RevokeUser(ctx context.Context, userID string, revokedBefore time.Time) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	userRevocation := domain.UserRevocation{
+		UserID:        userID,
+		RevokedBefore: &revokedBefore,
+	}
+
+	if err = revocationRepository.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_before", "updated_at"}),
+	}).Create(&userRevocation).Error; err != nil {
+		return err
+	}
+
+	revocationRepository.mu.Lock()
+	revocationRepository.cutoffs[userID] = revocationCacheEntry{cutoff: &revokedBefore, validUntil: time.Now().Add(revocationRepository.cacheTTL)}
+	revocationRepository.mu.Unlock()
+
+	return
+}
+
+// RevokeSession ends the session. Its refresh tokens are revoked separately,
+// through the refresh token repository.
+func (revocationRepository *revocationRepository) RevokeSession(ctx context.Context, sessionID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	session := domain.Session{}
+
+	if err = revocationRepository.db.WithContext(ctx).Model(&domain.Session{}).Where("id = ? AND revoked_at IS NULL", sessionID).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	if err = revocationRepository.db.WithContext(ctx).Select("expires_at").Where("id = ?", sessionID).Take(&session).Error; err != nil {
+		return err
+	}
+
+	revocationRepository.mu.Lock()
+	revocationRepository.sessions[sessionID] = revocationCacheEntry{revoked: true, validUntil: *session.ExpiresAt}
+	revocationRepository.mu.Unlock()
+
+	return
+}
+
+func (revocationRepository *revocationRepository) IsRevoked(ctx context.Context, jti string, sessionID string, userID string, issuedAt time.Time) (bool, error) {
+	cutoff, err := revocationRepository.cutoff(ctx, userID)
+
+	if err != nil {
+		return false, err
+	}
+
+	if cutoff != nil && issuedAt.Before(*cutoff) {
+		return true, nil
+	}
+
+	if sessionID != "" {
+		if revoked, err := revocationRepository.sessionRevoked(ctx, sessionID); err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	if jti == "" {
+		return false, nil
+	}
+
+	return revocationRepository.tokenRevoked(ctx, jti)
+}
+
+func (revocationRepository *revocationRepository) cutoff(ctx context.Context, userID string) (*time.Time, error) {
+	now := time.Now()
+
+	revocationRepository.mu.RLock()
+	entry, ok := revocationRepository.cutoffs[userID]
+	revocationRepository.mu.RUnlock()
+
+	if ok && now.Before(entry.validUntil) {
+		return entry.cutoff, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	userRevocation := domain.UserRevocation{}
+	entry = revocationCacheEntry{validUntil: now.Add(revocationRepository.cacheTTL)}
+
+	if err := revocationRepository.db.WithContext(ctx).Where("user_id = ?", userID).Take(&userRevocation).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	} else {
+		entry.cutoff = userRevocation.RevokedBefore
+	}
+
+	revocationRepository.mu.Lock()
+	revocationRepository.cutoffs[userID] = entry
+	revocationRepository.mu.Unlock()
+
+	return entry.cutoff, nil
+}
+
+func (revocationRepository *revocationRepository) tokenRevoked(ctx context.Context, jti string) (bool, error) {
+	now := time.Now()
+
+	revocationRepository.mu.RLock()
+	entry, ok := revocationRepository.tokens[jti]
+	revocationRepository.mu.RUnlock()
+
+	if ok && now.Before(entry.validUntil) {
+		return entry.revoked, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	revokedToken := domain.RevokedToken{}
+	entry = revocationCacheEntry{validUntil: now.Add(revocationRepository.cacheTTL)}
+
+	if err := revocationRepository.db.WithContext(ctx).Where("jti = ?", jti).Take(&revokedToken).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, err
+		}
+	} else {
+		entry.revoked = true
+		entry.validUntil = *revokedToken.ExpiresAt
+	}
+
+	revocationRepository.mu.Lock()
+	revocationRepository.evictExpired(now)
+	revocationRepository.tokens[jti] = entry
+	revocationRepository.mu.Unlock()
+
+	return entry.revoked, nil
+}
+
+func (revocationRepository *revocationRepository) sessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	now := time.Now()
+
+	revocationRepository.mu.RLock()
+	entry, ok := revocationRepository.sessions[sessionID]
+	revocationRepository.mu.RUnlock()
+
+	if ok && now.Before(entry.validUntil) {
+		return entry.revoked, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	session := domain.Session{}
+	entry = revocationCacheEntry{validUntil: now.Add(revocationRepository.cacheTTL)}
+
+	if err := revocationRepository.db.WithContext(ctx).Select("revoked_at", "expires_at").Where("id = ?", sessionID).Take(&session).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, err
+		}
+	} else if session.RevokedAt != nil {
+		entry.revoked = true
+		entry.validUntil = *session.ExpiresAt
+	}
+
+	revocationRepository.mu.Lock()
+	revocationRepository.evictExpired(now)
+	revocationRepository.sessions[sessionID] = entry
+	revocationRepository.mu.Unlock()
+
+	return entry.revoked, nil
+}
+
+// evictExpired keeps the cache bounded by the number of live tokens, sweeping at
+// most once per cacheTTL. Callers must hold mu.
+func (revocationRepository *revocationRepository) evictExpired(now time.Time) {
+	if now.Sub(revocationRepository.lastEvicted) < revocationRepository.cacheTTL {
+		return
+	}
+
+	revocationRepository.lastEvicted = now
+
+	for jti, entry := range revocationRepository.tokens {
+		if now.After(entry.validUntil) {
+			delete(revocationRepository.tokens, jti)
+		}
+	}
+
+	for sessionID, entry := range revocationRepository.sessions {
+		if now.After(entry.validUntil) {
+			delete(revocationRepository.sessions, sessionID)
+		}
+	}
+
+	for userID, entry := range revocationRepository.cutoffs {
+		if now.After(entry.validUntil) {
+			delete(revocationRepository.cutoffs, userID)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *sessionRepository {
+	return &sessionRepository{db}
+}
+
+func (sessionRepository *sessionRepository) Store(ctx context.Context, session *domain.Session) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	session.ID = fmt.Sprintf("session-%s", ID)
+
+	if err = sessionRepository.db.WithContext(ctx).Create(session).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (sessionRepository *sessionRepository) GetByID(ctx context.Context, session *domain.Session, id string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = sessionRepository.db.WithContext(ctx).Where("id = ?", id).Take(session).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Fetch returns the sessions of the user that are still going, most recently seen first.
+func (sessionRepository *sessionRepository) Fetch(ctx context.Context, sessions *[]domain.Session, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = sessionRepository.db.WithContext(ctx).Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).Order("last_seen_at DESC").Find(sessions).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// FetchHistory returns the latest limit sessions of the user, ended ones included.
+func (sessionRepository *sessionRepository) FetchHistory(ctx context.Context, sessions *[]domain.Session, userID string, limit int) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = sessionRepository.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(sessions).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (sessionRepository *sessionRepository) HasAny(ctx context.Context, userID string) (exists bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = sessionRepository.db.WithContext(ctx).Model(&domain.Session{}).Select("count(*) > 0").Where("user_id = ?", userID).Find(&exists).Error; err != nil {
+		return false, err
+	}
+
+	return
+}
+
+func (sessionRepository *sessionRepository) HasDevice(ctx context.Context, userID string, deviceHash string) (exists bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = sessionRepository.db.WithContext(ctx).Model(&domain.Session{}).Select("count(*) > 0").Where("user_id = ? AND device_hash = ?", userID, deviceHash).Find(&exists).Error; err != nil {
+		return false, err
+	}
+
+	return
+}
+
+// Touch records that the session was used at seenAt and keeps it going until expiresAt.
+func (sessionRepository *sessionRepository) Touch(ctx context.Context, id string, seenAt time.Time, expiresAt time.Time) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = sessionRepository.db.WithContext(ctx).Model(&domain.Session{}).Where("id = ? AND revoked_at IS NULL", id).Updates(map[string]interface{}{
+		"last_seen_at": seenAt,
+		"expires_at":   expiresAt,
+	}).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (sessionRepository *sessionRepository) RevokeUser(ctx context.Context, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = sessionRepository.db.WithContext(ctx).Model(&domain.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	return
+}
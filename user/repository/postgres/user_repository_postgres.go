@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"mygram-api/domain"
 	"mygram-api/helpers"
+	"sync"
 	"time"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type userRepository struct {
@@ -28,6 +30,7 @@ func (userRepository *userRepository) Register(ctx context.Context, user *domain
 	ID, _ := gonanoid.New(16)
 
 	user.ID = fmt.Sprintf("user-%s", ID)
+	user.Role = domain.RoleUser
 
 	if err = userRepository.db.WithContext(ctx).Create(&user).Error; err != nil {
 		return err
@@ -43,35 +46,241 @@ func (userRepository *userRepository) Login(ctx context.Context, user *domain.Us
 
 	password := user.Password
 
+	// Unknown emails are checked against a throwaway hash so both failures take
+	// as long and read the same, and nobody can probe which emails are registered.
+	// Any other error is no verdict on the credentials and must not count as one.
 	if err = userRepository.db.WithContext(ctx).Where("email = ?", user.Email).Take(&user).Error; err != nil {
-		return errors.New("the email you entered are not found")
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		helpers.Compare([]byte(dummyPasswordHash()), []byte(password))
+
+		return domain.ErrInvalidCredentials
 	}
 
 	if isValid := helpers.Compare([]byte(user.Password), []byte(password)); !isValid {
-		return errors.New("the credential you entered are wrong")
+		return domain.ErrInvalidCredentials
+	}
+
+	// Move hashes made with another algorithm or an older, cheaper setting to the
+	// configured one while the plain password is at hand. A failure here must not
+	// fail the login, the next one simply retries.
+	if helpers.NeedsRehash([]byte(user.Password)) {
+		if hash, err := helpers.Hash(password); err == nil {
+			userRepository.db.WithContext(ctx).Model(user).UpdateColumn("password", hash)
+		}
 	}
 
 	return
 }
 
-func (userRepository *userRepository) Update(ctx context.Context, user domain.User) (u domain.User, err error) {
+func (userRepository *userRepository) GetByID(ctx context.Context, user *domain.User, id string) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
-	u = domain.User{}
+	if err = userRepository.db.WithContext(ctx).First(user, "id = ?", id).Error; err != nil {
+		return err
+	}
 
-	if err = userRepository.db.WithContext(ctx).First(&u).Error; err != nil {
-		return u, err
+	return
+}
+
+func (userRepository *userRepository) GetByEmail(ctx context.Context, user *domain.User, email string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = userRepository.db.WithContext(ctx).Where("email = ?", email).Take(user).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userRepository *userRepository) Fetch(ctx context.Context, users *[]domain.User) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = userRepository.db.WithContext(ctx).Omit("password", "totp_secret").Order("created_at").Find(users).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userRepository *userRepository) GetProfileByID(ctx context.Context, profile *domain.Profile, id string) (err error) {
+	return userRepository.getProfile(ctx, profile, "id = ?", id)
+}
+
+func (userRepository *userRepository) GetProfileByUsername(ctx context.Context, profile *domain.Profile, username string) (err error) {
+	return userRepository.getProfile(ctx, profile, "username = ?", username)
+}
+
+func (userRepository *userRepository) getProfile(ctx context.Context, profile *domain.Profile, query string, value string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = userRepository.db.WithContext(ctx).Omit("password", "totp_secret").Where(query, value).Take(&profile.User).Error; err != nil {
+		return err
+	}
+
+	if err = userRepository.db.WithContext(ctx).Model(&domain.Photo{}).Where("user_id = ?", profile.User.ID).Count(&profile.PhotoCount).Error; err != nil {
+		return err
+	}
+
+	// There is no follow relation yet, so nobody has followers.
+	profile.FollowerCount = 0
+
+	if err = userRepository.db.WithContext(ctx).Where("user_id = ?", profile.User.ID).Order("created_at").Find(&profile.SocialMedias).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Update only writes the fields that are set on userUpdate to the user with id.
+// Like the other column updates it skips the model hooks, the caller validates
+// instead, so a taken username surfaces as a violation of idx_users_username.
+func (userRepository *userRepository) Update(ctx context.Context, id string, userUpdate domain.UserUpdate) (u domain.User, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	columns := map[string]interface{}{"updated_at": time.Now()}
+
+	if userUpdate.Username != nil {
+		columns["username"] = *userUpdate.Username
+	}
+
+	if userUpdate.DisplayName != nil {
+		columns["display_name"] = *userUpdate.DisplayName
+	}
+
+	if userUpdate.Bio != nil {
+		columns["bio"] = *userUpdate.Bio
+	}
+
+	if userUpdate.Age != nil {
+		columns["age"] = *userUpdate.Age
+	}
+
+	if userUpdate.ProfileImageUrl != nil {
+		columns["profile_image_url"] = *userUpdate.ProfileImageUrl
+	}
+
+	if userUpdate.PendingEmail != nil {
+		columns["pending_email"] = *userUpdate.PendingEmail
+	}
+
+	result := userRepository.db.WithContext(ctx).Model(&domain.User{ID: id}).UpdateColumns(columns)
+
+	if result.Error != nil {
+		return u, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return u, gorm.ErrRecordNotFound
 	}
 
-	if err = userRepository.db.WithContext(ctx).Model(&u).Updates(user).Error; err != nil {
+	if err = userRepository.db.WithContext(ctx).Omit("password", "totp_secret").Where("id = ?", id).Take(&u).Error; err != nil {
 		return u, err
 	}
 
 	return u, nil
 }
 
+// UpdatePassword stores an already hashed password. It bypasses the model hooks
+// because BeforeUpdate would validate, and BeforeCreate would re-hash, the row.
+func (userRepository *userRepository) UpdatePassword(ctx context.Context, id string, password string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	if err = userRepository.db.WithContext(ctx).Model(&domain.User{ID: id}).UpdateColumn("password", password).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// UpdateAvatar points the user at the avatar stored under key and served from url.
+// Empty values take the avatar away.
+func (userRepository *userRepository) UpdateAvatar(ctx context.Context, id string, key string, url string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := userRepository.db.WithContext(ctx).Model(&domain.User{ID: id}).UpdateColumns(map[string]interface{}{
+		"avatar_key":        key,
+		"profile_image_url": url,
+		"updated_at":        time.Now(),
+	})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
+
+func (userRepository *userRepository) UpdateRole(ctx context.Context, id string, role domain.Role) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	return userRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if role != domain.RoleAdmin {
+			if err := keepAnAdmin(tx, id); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&domain.User{ID: id}).UpdateColumn("role", role)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+}
+
+// VerifyEmail marks email as verified for the user. When email is the pending
+// address it replaces the current one, which stays in use until this point.
+func (userRepository *userRepository) VerifyEmail(ctx context.Context, id string, email string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	defer cancel()
+
+	result := userRepository.db.WithContext(ctx).Model(&domain.User{ID: id}).Where("email = ? OR pending_email = ?", email, email).UpdateColumns(map[string]interface{}{
+		"email":             email,
+		"pending_email":     gorm.Expr("CASE WHEN pending_email = ? THEN '' ELSE pending_email END", email),
+		"email_verified_at": time.Now(),
+	})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
+
 func (userRepository *userRepository) Delete(ctx context.Context, id string) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
@@ -81,13 +290,86 @@ func (userRepository *userRepository) Delete(ctx context.Context, id string) (er
 		return err
 	}
 
-	if err = userRepository.db.WithContext(ctx).Where("user_id = ?", id).Delete(&domain.SocialMedia{}).Error; err != nil {
-		return err
+	return userRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := keepAnAdmin(tx, id); err != nil {
+			return err
+		}
+
+		// The likes of the user cascade away with them, so they come off the
+		// like counts first.
+		if err := tx.Exec("UPDATE photos SET like_count = like_count - 1 WHERE id IN (SELECT photo_id FROM photo_likes WHERE user_id = ?)", id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("UPDATE comments SET like_count = like_count - 1 WHERE id IN (SELECT comment_id FROM comment_likes WHERE user_id = ?)", id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", id).Delete(&domain.SocialMedia{}).Error; err != nil {
+			return err
+		}
+
+		// The comments of the user would cascade away with them, and every reply
+		// under them through parent_id, so they are detached as tombstones first.
+		if err := tx.Model(&domain.Comment{}).Where("user_id = ?", id).UpdateColumns(map[string]interface{}{
+			"user_id":    nil,
+			"message":    domain.CommentTombstone,
+			"deleted":    true,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&domain.User{}, &id).Error; err != nil {
+			return err
+		}
+
+		return pruneTombstones(tx)
+	})
+}
+
+// pruneTombstones removes the tombstones nobody replies to anymore, one level of
+// a thread at a time, the same way deleting a single comment does.
+func pruneTombstones(tx *gorm.DB) error {
+	for {
+		result := tx.Where("deleted = ? AND NOT EXISTS (SELECT 1 FROM comments AS replies WHERE replies.parent_id = comments.id)", true).Delete(&domain.Comment{})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return nil
+		}
 	}
+}
+
+// keepAnAdmin refuses to take the admin role away from id when it is the only
+// admin left. The admin rows stay locked until tx ends, so two admins demoting
+// each other at the same time cannot both succeed.
+func keepAnAdmin(tx *gorm.DB, id string) error {
+	var admins []string
 
-	if err = userRepository.db.WithContext(ctx).Delete(&domain.User{}, &id).Error; err != nil {
+	if err := tx.Model(&domain.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("role = ?", domain.RoleAdmin).Pluck("id", &admins).Error; err != nil {
 		return err
 	}
 
-	return
+	if len(admins) == 1 && admins[0] == id {
+		return domain.ErrLastAdmin
+	}
+
+	return nil
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+func dummyPasswordHash() string {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = helpers.Hash("mygram-dummy-password")
+	})
+
+	return dummyHash
 }
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"time"
+)
+
+const (
+	apiKeyPrefix = "mgk_"
+	// apiKeyVisibleLength is how much of a key, its prefix included, stays on record.
+	apiKeyVisibleLength = 12
+)
+
+type apiKeyUseCase struct {
+	apiKeyRepository domain.APIKeyRepository
+}
+
+func NewAPIKeyUseCase(apiKeyRepository domain.APIKeyRepository) *apiKeyUseCase {
+	return &apiKeyUseCase{apiKeyRepository}
+}
+
+// Create stores apiKey and returns the key itself, which is never retrievable again.
+func (apiKeyUseCase *apiKeyUseCase) Create(ctx context.Context, apiKey *domain.APIKey) (key string, err error) {
+	if len(apiKey.Scopes) == 0 {
+		return "", domain.ErrAPIKeyScopeInvalid
+	}
+
+	for _, scope := range apiKey.Scopes {
+		if !scope.IsValid() {
+			return "", domain.ErrAPIKeyScopeInvalid
+		}
+	}
+
+	if apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(time.Now()) {
+		return "", domain.ErrAPIKeyExpiryInvalid
+	}
+
+	secret, err := helpers.GenerateRandomToken(32)
+
+	if err != nil {
+		return "", err
+	}
+
+	key = apiKeyPrefix + secret
+	apiKey.Prefix = key[:apiKeyVisibleLength]
+	apiKey.KeyHash = helpers.HashToken(key)
+
+	if err = apiKeyUseCase.apiKeyRepository.Store(ctx, apiKey); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func (apiKeyUseCase *apiKeyUseCase) Fetch(ctx context.Context, apiKeys *[]domain.APIKey, userID string) (err error) {
+	if err = apiKeyUseCase.apiKeyRepository.Fetch(ctx, apiKeys, userID); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (apiKeyUseCase *apiKeyUseCase) Delete(ctx context.Context, userID string, id string) (err error) {
+	if err = apiKeyUseCase.apiKeyRepository.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+
+	return
+}
+
+// Authenticate resolves key to the principal it acts as. The role and email
+// status come from the user as they are now, not as they were at creation.
+func (apiKeyUseCase *apiKeyUseCase) Authenticate(ctx context.Context, key string) (principal domain.Principal, err error) {
+	apiKey := domain.APIKey{}
+
+	if err = apiKeyUseCase.apiKeyRepository.GetByHash(ctx, &apiKey, helpers.HashToken(key)); err != nil || apiKey.User == nil {
+		return principal, domain.ErrAPIKeyInvalid
+	}
+
+	now := time.Now()
+
+	if apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(now) {
+		return principal, domain.ErrAPIKeyInvalid
+	}
+
+	// Losing track of a use is preferable to turning the request away.
+	_ = apiKeyUseCase.apiKeyRepository.Touch(ctx, apiKey.ID, now)
+
+	role := apiKey.User.Role
+
+	if !role.IsValid() {
+		role = domain.RoleUser
+	}
+
+	return domain.Principal{
+		UserID:        apiKey.UserID,
+		Email:         apiKey.User.Email,
+		EmailVerified: apiKey.User.EmailVerifiedAt != nil,
+		Role:          role,
+		APIKeyID:      apiKey.ID,
+		Scopes:        apiKey.Scopes,
+	}, nil
+}
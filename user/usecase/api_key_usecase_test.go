@@ -0,0 +1,144 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/domain/mocks"
+	"mygram-api/helpers"
+	"strings"
+	"testing"
+	"time"
+
+	userUseCase "mygram-api/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	mockAPIKeyRepository := new(mocks.APIKeyRepository)
+	apiKeyUseCase := userUseCase.NewAPIKeyUseCase(mockAPIKeyRepository)
+
+	t.Run("create api key correctly", func(t *testing.T) {
+		apiKey := domain.APIKey{
+			UserID: "user-123",
+			Name:   "nightly backup",
+			Scopes: []domain.Scope{domain.ScopePhotosRead},
+		}
+
+		mockAPIKeyRepository.On("Store", mock.Anything, &apiKey).Return(nil).Once()
+
+		key, err := apiKeyUseCase.Create(context.Background(), &apiKey)
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(key, "mgk_"))
+		assert.True(t, strings.HasPrefix(key, apiKey.Prefix))
+		assert.Len(t, apiKey.Prefix, 12)
+		assert.Equal(t, helpers.HashToken(key), apiKey.KeyHash)
+		mockAPIKeyRepository.AssertExpectations(t)
+	})
+
+	t.Run("create api key with an unknown scope", func(t *testing.T) {
+		mockAPIKeyRepository := new(mocks.APIKeyRepository)
+		apiKeyUseCase := userUseCase.NewAPIKeyUseCase(mockAPIKeyRepository)
+
+		key, err := apiKeyUseCase.Create(context.Background(), &domain.APIKey{Scopes: []domain.Scope{"users:write"}})
+
+		assert.ErrorIs(t, err, domain.ErrAPIKeyScopeInvalid)
+		assert.Empty(t, key)
+		mockAPIKeyRepository.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("create api key without scopes", func(t *testing.T) {
+		_, err := apiKeyUseCase.Create(context.Background(), &domain.APIKey{})
+
+		assert.ErrorIs(t, err, domain.ErrAPIKeyScopeInvalid)
+	})
+
+	t.Run("create api key expiring in the past", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+
+		_, err := apiKeyUseCase.Create(context.Background(), &domain.APIKey{Scopes: []domain.Scope{domain.ScopePhotosRead}, ExpiresAt: &past})
+
+		assert.ErrorIs(t, err, domain.ErrAPIKeyExpiryInvalid)
+	})
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	now := time.Now()
+	key := "mgk_the-key"
+	mockAPIKey := domain.APIKey{
+		ID:     "apikey-123",
+		UserID: "user-123",
+		Scopes: []domain.Scope{domain.ScopePhotosRead, domain.ScopeCommentsWrite},
+		User: &domain.User{
+			ID:              "user-123",
+			Email:           "johndoe@example.com",
+			EmailVerifiedAt: &now,
+			Role:            domain.RoleModerator,
+		},
+	}
+
+	t.Run("authenticate api key correctly", func(t *testing.T) {
+		mockAPIKeyRepository := new(mocks.APIKeyRepository)
+		apiKeyUseCase := userUseCase.NewAPIKeyUseCase(mockAPIKeyRepository)
+
+		mockAPIKeyRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.APIKey"), helpers.HashToken(key)).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.APIKey) = mockAPIKey
+		}).Return(nil).Once()
+		mockAPIKeyRepository.On("Touch", mock.Anything, mockAPIKey.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		principal, err := apiKeyUseCase.Authenticate(context.Background(), key)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", principal.UserID)
+		assert.Equal(t, domain.RoleModerator, principal.Role)
+		assert.True(t, principal.EmailVerified)
+		assert.True(t, principal.HasScope(domain.ScopeCommentsWrite))
+		assert.False(t, principal.HasScope(domain.ScopePhotosWrite))
+		mockAPIKeyRepository.AssertExpectations(t)
+	})
+
+	t.Run("authenticate api key that failed to record its use", func(t *testing.T) {
+		mockAPIKeyRepository := new(mocks.APIKeyRepository)
+		apiKeyUseCase := userUseCase.NewAPIKeyUseCase(mockAPIKeyRepository)
+
+		mockAPIKeyRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.APIKey"), helpers.HashToken(key)).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.APIKey) = mockAPIKey
+		}).Return(nil).Once()
+		mockAPIKeyRepository.On("Touch", mock.Anything, mockAPIKey.ID, mock.AnythingOfType("time.Time")).Return(errors.New("fail")).Once()
+
+		_, err := apiKeyUseCase.Authenticate(context.Background(), key)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("authenticate unknown api key", func(t *testing.T) {
+		mockAPIKeyRepository := new(mocks.APIKeyRepository)
+		apiKeyUseCase := userUseCase.NewAPIKeyUseCase(mockAPIKeyRepository)
+
+		mockAPIKeyRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.APIKey"), helpers.HashToken(key)).Return(errors.New("record not found")).Once()
+
+		_, err := apiKeyUseCase.Authenticate(context.Background(), key)
+
+		assert.ErrorIs(t, err, domain.ErrAPIKeyInvalid)
+	})
+
+	t.Run("authenticate expired api key", func(t *testing.T) {
+		mockAPIKeyRepository := new(mocks.APIKeyRepository)
+		apiKeyUseCase := userUseCase.NewAPIKeyUseCase(mockAPIKeyRepository)
+		past := now.Add(-time.Hour)
+
+		mockAPIKeyRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.APIKey"), helpers.HashToken(key)).Run(func(args mock.Arguments) {
+			expired := mockAPIKey
+			expired.ExpiresAt = &past
+			*args.Get(1).(*domain.APIKey) = expired
+		}).Return(nil).Once()
+
+		_, err := apiKeyUseCase.Authenticate(context.Background(), key)
+
+		assert.ErrorIs(t, err, domain.ErrAPIKeyInvalid)
+		mockAPIKeyRepository.AssertNotCalled(t, "Touch", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mygram-api/domain"
+	"mygram-api/imaging"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// avatarMaxBytes is the largest avatar upload accepted, before decoding.
+const avatarMaxBytes = 5 << 20
+
+type avatarUseCase struct {
+	userRepository domain.UserRepository
+	blobStore      domain.BlobStore
+}
+
+func NewAvatarUseCase(userRepository domain.UserRepository, blobStore domain.BlobStore) *avatarUseCase {
+	return &avatarUseCase{userRepository, blobStore}
+}
+
+// Set turns the uploaded image into square JPEGs of every avatar size and makes
+// them the avatar of the user. The files of the previous avatar are removed once
+// nothing points at them anymore.
+func (avatarUseCase *avatarUseCase) Set(ctx context.Context, userID string, body io.Reader) (avatarSizes []domain.AvatarSize, err error) {
+	user := domain.User{}
+
+	if err = avatarUseCase.userRepository.GetByID(ctx, &user, userID); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, avatarMaxBytes+1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > avatarMaxBytes {
+		return nil, domain.ErrImageTooLarge
+	}
+
+	img, err := imaging.Decode(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ID, _ := gonanoid.New(16)
+	key := fmt.Sprintf("avatars/%s/%s", userID, ID)
+
+	for _, size := range domain.AvatarSizes {
+		buffer := bytes.Buffer{}
+
+		if err = imaging.EncodeJPEG(&buffer, imaging.Square(img, size)); err != nil {
+			return nil, err
+		}
+
+		if err = avatarUseCase.blobStore.Put(ctx, avatarFile(key, size), "image/jpeg", &buffer); err != nil {
+			avatarUseCase.RemoveFiles(ctx, key)
+
+			return nil, err
+		}
+
+		avatarSizes = append(avatarSizes, domain.AvatarSize{Size: size, URL: avatarUseCase.blobStore.URL(avatarFile(key, size))})
+	}
+
+	if err = avatarUseCase.userRepository.UpdateAvatar(ctx, userID, key, avatarSizes[len(avatarSizes)-1].URL); err != nil {
+		avatarUseCase.RemoveFiles(ctx, key)
+
+		return nil, err
+	}
+
+	if user.AvatarKey != "" {
+		avatarUseCase.RemoveFiles(ctx, user.AvatarKey)
+	}
+
+	return avatarSizes, nil
+}
+
+// Delete takes the avatar of the user away, along with its files.
+func (avatarUseCase *avatarUseCase) Delete(ctx context.Context, userID string) (err error) {
+	user := domain.User{}
+
+	if err = avatarUseCase.userRepository.GetByID(ctx, &user, userID); err != nil {
+		return err
+	}
+
+	if user.AvatarKey == "" {
+		return
+	}
+
+	if err = avatarUseCase.userRepository.UpdateAvatar(ctx, userID, "", ""); err != nil {
+		return err
+	}
+
+	avatarUseCase.RemoveFiles(ctx, user.AvatarKey)
+
+	return
+}
+
+// RemoveFiles deletes every size of the avatar under key. A file left behind only
+// costs disk space, so failures are logged rather than returned.
+func (avatarUseCase *avatarUseCase) RemoveFiles(ctx context.Context, key string) {
+	for _, size := range domain.AvatarSizes {
+		if err := avatarUseCase.blobStore.Delete(ctx, avatarFile(key, size)); err != nil {
+			log.Printf("removing avatar file %s failed: %s", avatarFile(key, size), err)
+		}
+	}
+}
+
+func avatarFile(key string, size int) string {
+	return fmt.Sprintf("%s-%d.jpg", key, size)
+}
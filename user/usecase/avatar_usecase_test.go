@@ -0,0 +1,121 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mygram-api/domain"
+	"mygram-api/domain/mocks"
+	"strings"
+	"testing"
+
+	userUseCase "mygram-api/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func pngImage(t *testing.T, width int, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+
+	buffer := bytes.Buffer{}
+
+	if err := png.Encode(&buffer, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestSetAvatar(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockBlobStore := new(mocks.BlobStore)
+	avatarUseCase := userUseCase.NewAvatarUseCase(mockUserRepository, mockBlobStore)
+
+	withUser := func(user domain.User) func(args mock.Arguments) {
+		return func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = user
+		}
+	}
+
+	mockBlobStore.On("URL", mock.AnythingOfType("string")).Return(func(key string) string {
+		return "/media/" + key
+	})
+
+	t.Run("set avatar correctly", func(t *testing.T) {
+		stored := map[string][]byte{}
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(withUser(domain.User{ID: "user-123", AvatarKey: "avatars/user-123/old"})).Return(nil).Once()
+		mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), "image/jpeg", mock.Anything).Run(func(args mock.Arguments) {
+			stored[args.String(1)], _ = io.ReadAll(args.Get(3).(io.Reader))
+		}).Return(nil).Times(len(domain.AvatarSizes))
+		mockUserRepository.On("UpdateAvatar", mock.Anything, "user-123", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil).Once()
+
+		for _, size := range domain.AvatarSizes {
+			mockBlobStore.On("Delete", mock.Anything, fmt.Sprintf("avatars/user-123/old-%d.jpg", size)).Return(nil).Once()
+		}
+
+		avatarSizes, err := avatarUseCase.Set(context.Background(), "user-123", bytes.NewReader(pngImage(t, 300, 200)))
+
+		assert.NoError(t, err)
+		assert.Len(t, avatarSizes, len(domain.AvatarSizes))
+
+		for _, avatarSize := range avatarSizes {
+			key := strings.TrimPrefix(avatarSize.URL, "/media/")
+
+			assert.True(t, strings.HasPrefix(key, "avatars/user-123/"))
+
+			img, err := jpeg.Decode(bytes.NewReader(stored[key]))
+
+			assert.NoError(t, err)
+			assert.Equal(t, avatarSize.Size, img.Bounds().Dx())
+			assert.Equal(t, avatarSize.Size, img.Bounds().Dy())
+		}
+
+		mockUserRepository.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
+
+	t.Run("set avatar with something that is not an image", func(t *testing.T) {
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(withUser(domain.User{ID: "user-123"})).Return(nil).Once()
+
+		_, err := avatarUseCase.Set(context.Background(), "user-123", strings.NewReader("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+
+		assert.ErrorIs(t, err, domain.ErrImageUnsupported)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("set avatar with a file that is too large", func(t *testing.T) {
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(withUser(domain.User{ID: "user-123"})).Return(nil).Once()
+
+		_, err := avatarUseCase.Set(context.Background(), "user-123", bytes.NewReader(make([]byte, 6<<20)))
+
+		assert.ErrorIs(t, err, domain.ErrImageTooLarge)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("set avatar with failing repository removes the new files", func(t *testing.T) {
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(withUser(domain.User{ID: "user-123"})).Return(nil).Once()
+		mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), "image/jpeg", mock.Anything).Return(nil).Times(len(domain.AvatarSizes))
+		mockUserRepository.On("UpdateAvatar", mock.Anything, "user-123", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(errors.New("fail")).Once()
+		mockBlobStore.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Times(len(domain.AvatarSizes))
+
+		_, err := avatarUseCase.Set(context.Background(), "user-123", bytes.NewReader(pngImage(t, 64, 64)))
+
+		assert.Error(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
+}
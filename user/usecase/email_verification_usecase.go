@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"gorm.io/gorm"
+)
+
+const emailVerificationPurpose = "email_verification"
+
+type emailVerification struct {
+	UserID string `json:"id"`
+	Email  string `json:"email"`
+}
+
+type emailVerificationUseCase struct {
+	userRepository domain.UserRepository
+	mailer         domain.Mailer
+}
+
+func NewEmailVerificationUseCase(userRepository domain.UserRepository, mailer domain.Mailer) *emailVerificationUseCase {
+	return &emailVerificationUseCase{userRepository, mailer}
+}
+
+// Send mails a verification link to the address that still needs confirming:
+// the pending address while an email change is in flight, the current one otherwise.
+func (emailVerificationUseCase *emailVerificationUseCase) Send(ctx context.Context, user domain.User) (err error) {
+	email := user.Email
+
+	if user.PendingEmail != "" {
+		email = user.PendingEmail
+	} else if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	ttl := helpers.EmailVerificationTTL()
+	token, err := helpers.Sign(emailVerificationPurpose, emailVerification{user.ID, email}, ttl)
+
+	if err != nil {
+		return err
+	}
+
+	return emailVerificationUseCase.mailer.Send(ctx, domain.Mail{
+		To:      []string{email},
+		Subject: "Verify your MyGram email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm that %s is your email address by opening the link below within %s:\n\n%s\n\nIf you didn't sign up for MyGram, you can ignore this email.\n",
+			user.Username, email, ttl, helpers.EmailVerificationURL(token),
+		),
+	})
+}
+
+func (emailVerificationUseCase *emailVerificationUseCase) Verify(ctx context.Context, token string) (err error) {
+	verification := emailVerification{}
+
+	if err = helpers.Verify(emailVerificationPurpose, token, &verification); err != nil {
+		return domain.ErrEmailVerificationInvalid
+	}
+
+	if err = emailVerificationUseCase.userRepository.VerifyEmail(ctx, verification.UserID, verification.Email); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrEmailVerificationInvalid
+		}
+
+		if strings.Contains(err.Error(), "idx_users_email") {
+			return domain.ErrEmailAlreadyUsed
+		}
+
+		return err
+	}
+
+	return
+}
+
+// PrepareEmailChange checks that the user with id may switch to email and returns
+// it to be stored as their pending address, or nil when it already is their
+// address. It writes nothing: the caller stores the address together with the
+// rest of the update, so a rejected email leaves every other field alone, and
+// calls Send once it is stored.
+func (emailVerificationUseCase *emailVerificationUseCase) PrepareEmailChange(ctx context.Context, id string, email string) (pendingEmail *string, err error) {
+	user := domain.User{}
+	owner := domain.User{}
+
+	if err = emailVerificationUseCase.userRepository.GetByID(ctx, &user, id); err != nil {
+		return nil, err
+	}
+
+	if !govalidator.IsEmail(email) {
+		return nil, domain.ErrEmailInvalid
+	}
+
+	if strings.EqualFold(user.Email, email) {
+		return nil, nil
+	}
+
+	if err = emailVerificationUseCase.userRepository.GetByEmail(ctx, &owner, email); err == nil {
+		return nil, domain.ErrEmailAlreadyUsed
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return &email, nil
+}
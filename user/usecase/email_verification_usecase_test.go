@@ -0,0 +1,194 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/domain/mocks"
+	"mygram-api/mailer"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	userUseCase "mygram-api/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func verificationToken(t *testing.T, mail domain.Mail) string {
+	link := mail.Body[strings.Index(mail.Body, "http"):]
+	link = link[:strings.Index(link, "\n")]
+	parsed, err := url.Parse(link)
+
+	assert.NoError(t, err)
+
+	return parsed.Query().Get("token")
+}
+
+func TestSendEmailVerification(t *testing.T) {
+	t.Setenv("SIGNING_KEY", "secret")
+
+	mockUserRepository := new(mocks.UserRepository)
+
+	t.Run("send verification to an unverified email", func(t *testing.T) {
+		memoryMailer := mailer.NewMemoryMailer()
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, memoryMailer)
+
+		err := emailVerificationUseCase.Send(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com"})
+
+		assert.NoError(t, err)
+		assert.Len(t, memoryMailer.Sent(), 1)
+		assert.Equal(t, []string{"johndoe@example.com"}, memoryMailer.Sent()[0].To)
+	})
+
+	t.Run("send verification to the pending email", func(t *testing.T) {
+		now := time.Now()
+		memoryMailer := mailer.NewMemoryMailer()
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, memoryMailer)
+
+		err := emailVerificationUseCase.Send(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com", PendingEmail: "newjohndoe@example.com", EmailVerifiedAt: &now})
+
+		assert.NoError(t, err)
+		assert.Len(t, memoryMailer.Sent(), 1)
+		assert.Equal(t, []string{"newjohndoe@example.com"}, memoryMailer.Sent()[0].To)
+	})
+
+	t.Run("send verification to an already verified email", func(t *testing.T) {
+		now := time.Now()
+		memoryMailer := mailer.NewMemoryMailer()
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, memoryMailer)
+
+		err := emailVerificationUseCase.Send(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com", EmailVerifiedAt: &now})
+
+		assert.NoError(t, err)
+		assert.Empty(t, memoryMailer.Sent())
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	t.Setenv("SIGNING_KEY", "secret")
+
+	mockUser := domain.User{ID: "user-123", Email: "johndoe@example.com"}
+
+	t.Run("verify email correctly", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		memoryMailer := mailer.NewMemoryMailer()
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, memoryMailer)
+
+		assert.NoError(t, emailVerificationUseCase.Send(context.Background(), mockUser))
+
+		mockUserRepository.On("VerifyEmail", mock.Anything, mockUser.ID, mockUser.Email).Return(nil).Once()
+
+		err := emailVerificationUseCase.Verify(context.Background(), verificationToken(t, memoryMailer.Sent()[0]))
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("verify email with tampered token", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		memoryMailer := mailer.NewMemoryMailer()
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, memoryMailer)
+
+		assert.NoError(t, emailVerificationUseCase.Send(context.Background(), mockUser))
+
+		err := emailVerificationUseCase.Verify(context.Background(), "x"+verificationToken(t, memoryMailer.Sent()[0]))
+
+		assert.ErrorIs(t, err, domain.ErrEmailVerificationInvalid)
+		mockUserRepository.AssertNotCalled(t, "VerifyEmail", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("verify email that is no longer current or pending", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		memoryMailer := mailer.NewMemoryMailer()
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, memoryMailer)
+
+		assert.NoError(t, emailVerificationUseCase.Send(context.Background(), mockUser))
+
+		mockUserRepository.On("VerifyEmail", mock.Anything, mockUser.ID, mockUser.Email).Return(gorm.ErrRecordNotFound).Once()
+
+		err := emailVerificationUseCase.Verify(context.Background(), verificationToken(t, memoryMailer.Sent()[0]))
+
+		assert.ErrorIs(t, err, domain.ErrEmailVerificationInvalid)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestPrepareEmailChange(t *testing.T) {
+	mockUser := domain.User{ID: "user-123", Email: "johndoe@example.com"}
+
+	withUser := func(args mock.Arguments) {
+		*args.Get(1).(*domain.User) = mockUser
+	}
+
+	t.Run("prepare email change correctly", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, mailer.NewMemoryMailer())
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+		mockUserRepository.On("GetByEmail", mock.Anything, mock.AnythingOfType("*domain.User"), "newjohndoe@example.com").Return(gorm.ErrRecordNotFound).Once()
+
+		pendingEmail, err := emailVerificationUseCase.PrepareEmailChange(context.Background(), mockUser.ID, "newjohndoe@example.com")
+
+		assert.NoError(t, err)
+
+		if assert.NotNil(t, pendingEmail) {
+			assert.Equal(t, "newjohndoe@example.com", *pendingEmail)
+		}
+
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("prepare email change to the current email", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, mailer.NewMemoryMailer())
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+
+		pendingEmail, err := emailVerificationUseCase.PrepareEmailChange(context.Background(), mockUser.ID, "JohnDoe@example.com")
+
+		assert.NoError(t, err)
+		assert.Nil(t, pendingEmail)
+		mockUserRepository.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("prepare email change to one that has been used", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, mailer.NewMemoryMailer())
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+		mockUserRepository.On("GetByEmail", mock.Anything, mock.AnythingOfType("*domain.User"), "janedoe@example.com").Return(nil).Once()
+
+		_, err := emailVerificationUseCase.PrepareEmailChange(context.Background(), mockUser.ID, "janedoe@example.com")
+
+		assert.ErrorIs(t, err, domain.ErrEmailAlreadyUsed)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("prepare email change with invalid email format", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, mailer.NewMemoryMailer())
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+
+		_, err := emailVerificationUseCase.PrepareEmailChange(context.Background(), mockUser.ID, "johndoe")
+
+		assert.ErrorIs(t, err, domain.ErrEmailInvalid)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("prepare email change with not found user", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		emailVerificationUseCase := userUseCase.NewEmailVerificationUseCase(mockUserRepository, mailer.NewMemoryMailer())
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-234").Return(errors.New("fail")).Once()
+
+		_, err := emailVerificationUseCase.PrepareEmailChange(context.Background(), "user-234", "newjohndoe@example.com")
+
+		assert.Error(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"strings"
+	"time"
+)
+
+// loginThrottle lets free failures through, then makes every further attempt wait
+// twice as long as the previous one, and locks the key out once lockout failures
+// have piled up. A key that stays quiet for window starts over.
+type loginThrottle struct {
+	prefix     string
+	free       int
+	lockout    int
+	base       time.Duration
+	lockoutFor time.Duration
+	window     time.Duration
+}
+
+var (
+	accountLoginThrottle = loginThrottle{prefix: "account:", free: 3, lockout: 10, base: time.Second, lockoutFor: 15 * time.Minute, window: 24 * time.Hour}
+	ipLoginThrottle      = loginThrottle{prefix: "ip:", free: 20, lockout: 100, base: time.Second, lockoutFor: time.Hour, window: time.Hour}
+)
+
+func (loginThrottle loginThrottle) delay(failures int) time.Duration {
+	if failures < loginThrottle.free {
+		return 0
+	}
+
+	if failures >= loginThrottle.lockout || failures-loginThrottle.free >= 30 {
+		return loginThrottle.lockoutFor
+	}
+
+	if delay := loginThrottle.base << (failures - loginThrottle.free); delay < loginThrottle.lockoutFor {
+		return delay
+	}
+
+	return loginThrottle.lockoutFor
+}
+
+type loginAttemptUseCase struct {
+	loginAttemptStore      domain.LoginAttemptStore
+	lockoutEventRepository domain.LockoutEventRepository
+	userRepository         domain.UserRepository
+	mailer                 domain.Mailer
+}
+
+func NewLoginAttemptUseCase(loginAttemptStore domain.LoginAttemptStore, lockoutEventRepository domain.LockoutEventRepository, userRepository domain.UserRepository, mailer domain.Mailer) *loginAttemptUseCase {
+	return &loginAttemptUseCase{loginAttemptStore, lockoutEventRepository, userRepository, mailer}
+}
+
+// Check reports a *domain.LoginThrottledError while either the account or the IP
+// address still has to wait after earlier failures. Unknown emails are throttled
+// the same way as registered ones so the two cannot be told apart.
+func (loginAttemptUseCase *loginAttemptUseCase) Check(ctx context.Context, email string, ip string) (err error) {
+	var retryAfter time.Duration
+
+	now := time.Now()
+
+	for _, key := range loginKeys(email, ip) {
+		attempt, err := loginAttemptUseCase.loginAttemptStore.Get(ctx, key.throttle.prefix+key.value)
+
+		if err != nil {
+			return err
+		}
+
+		if wait := attempt.LastFailureAt.Add(key.throttle.delay(attempt.Failures)).Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &domain.LoginThrottledError{RetryAfter: retryAfter}
+	}
+
+	return
+}
+
+func (loginAttemptUseCase *loginAttemptUseCase) Fail(ctx context.Context, email string, ip string) (err error) {
+	now := time.Now()
+
+	for _, key := range loginKeys(email, ip) {
+		attempt, err := loginAttemptUseCase.loginAttemptStore.Fail(ctx, key.throttle.prefix+key.value, now, key.throttle.window)
+
+		if err != nil {
+			return err
+		}
+
+		if key.throttle == accountLoginThrottle && attempt.Failures == key.throttle.lockout {
+			if err = loginAttemptUseCase.lockOut(ctx, key.value, ip, attempt, now.Add(key.throttle.lockoutFor)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return
+}
+
+// Succeed clears the failures of the account. The IP address keeps its count so
+// logging into an own account cannot be used to keep guessing others.
+func (loginAttemptUseCase *loginAttemptUseCase) Succeed(ctx context.Context, email string, ip string) (err error) {
+	return loginAttemptUseCase.loginAttemptStore.Reset(ctx, accountLoginThrottle.prefix+normalizeEmail(email))
+}
+
+func (loginAttemptUseCase *loginAttemptUseCase) lockOut(ctx context.Context, email string, ip string, attempt domain.LoginAttempt, lockedUntil time.Time) (err error) {
+	user := domain.User{}
+
+	if err = loginAttemptUseCase.userRepository.GetByEmail(ctx, &user, email); err != nil {
+		return nil
+	}
+
+	if err = loginAttemptUseCase.lockoutEventRepository.Store(ctx, &domain.LockoutEvent{
+		UserID:      user.ID,
+		IPAddress:   ip,
+		Failures:    attempt.Failures,
+		LockedUntil: &lockedUntil,
+	}); err != nil {
+		return err
+	}
+
+	return loginAttemptUseCase.mailer.Send(ctx, domain.Mail{
+		To:      []string{user.Email},
+		Subject: "Your MyGram account has been temporarily locked",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nAfter %d failed login attempts, the last one from %s, logging into your MyGram account is blocked until %s.\n\nIf it wasn't you, someone may be trying to guess your password. Consider resetting it and enabling two-factor authentication.\n",
+			user.Username, attempt.Failures, ip, lockedUntil.UTC().Format(time.RFC1123),
+		),
+	})
+}
+
+type loginKey struct {
+	throttle loginThrottle
+	value    string
+}
+
+func loginKeys(email string, ip string) []loginKey {
+	return []loginKey{
+		{accountLoginThrottle, normalizeEmail(email)},
+		{ipLoginThrottle, ip},
+	}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,144 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/domain/mocks"
+	"mygram-api/mailer"
+	"testing"
+	"time"
+
+	userUseCase "mygram-api/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCheckLoginAttempt(t *testing.T) {
+	t.Run("check login without failures", func(t *testing.T) {
+		mockLoginAttemptStore := new(mocks.LoginAttemptStore)
+		loginAttemptUseCase := userUseCase.NewLoginAttemptUseCase(mockLoginAttemptStore, new(mocks.LockoutEventRepository), new(mocks.UserRepository), mailer.NewMemoryMailer())
+
+		mockLoginAttemptStore.On("Get", mock.Anything, mock.AnythingOfType("string")).Return(domain.LoginAttempt{}, nil).Twice()
+
+		err := loginAttemptUseCase.Check(context.Background(), "johndoe@example.com", "10.0.0.1")
+
+		assert.NoError(t, err)
+		mockLoginAttemptStore.AssertExpectations(t)
+	})
+
+	t.Run("check login of an account backing off", func(t *testing.T) {
+		mockLoginAttemptStore := new(mocks.LoginAttemptStore)
+		loginAttemptUseCase := userUseCase.NewLoginAttemptUseCase(mockLoginAttemptStore, new(mocks.LockoutEventRepository), new(mocks.UserRepository), mailer.NewMemoryMailer())
+
+		mockLoginAttemptStore.On("Get", mock.Anything, "account:johndoe@example.com").Return(domain.LoginAttempt{Failures: 5, LastFailureAt: time.Now()}, nil).Once()
+		mockLoginAttemptStore.On("Get", mock.Anything, "ip:10.0.0.1").Return(domain.LoginAttempt{}, nil).Once()
+
+		err := loginAttemptUseCase.Check(context.Background(), " JohnDoe@example.com", "10.0.0.1")
+
+		throttled := &domain.LoginThrottledError{}
+
+		assert.ErrorIs(t, err, domain.ErrLoginThrottled)
+		assert.True(t, errors.As(err, &throttled))
+		assert.InDelta(t, 4*time.Second, throttled.RetryAfter, float64(time.Second))
+		mockLoginAttemptStore.AssertExpectations(t)
+	})
+
+	t.Run("check login of a locked out ip address", func(t *testing.T) {
+		mockLoginAttemptStore := new(mocks.LoginAttemptStore)
+		loginAttemptUseCase := userUseCase.NewLoginAttemptUseCase(mockLoginAttemptStore, new(mocks.LockoutEventRepository), new(mocks.UserRepository), mailer.NewMemoryMailer())
+
+		mockLoginAttemptStore.On("Get", mock.Anything, "account:janedoe@example.com").Return(domain.LoginAttempt{}, nil).Once()
+		mockLoginAttemptStore.On("Get", mock.Anything, "ip:10.0.0.1").Return(domain.LoginAttempt{Failures: 100, LastFailureAt: time.Now().Add(-time.Minute)}, nil).Once()
+
+		err := loginAttemptUseCase.Check(context.Background(), "janedoe@example.com", "10.0.0.1")
+
+		assert.ErrorIs(t, err, domain.ErrLoginThrottled)
+		mockLoginAttemptStore.AssertExpectations(t)
+	})
+}
+
+func TestFailLoginAttempt(t *testing.T) {
+	mockUser := domain.User{ID: "user-123", Username: "johndoe", Email: "johndoe@example.com"}
+
+	t.Run("fail login below the lockout", func(t *testing.T) {
+		mockLoginAttemptStore := new(mocks.LoginAttemptStore)
+		mockLockoutEventRepository := new(mocks.LockoutEventRepository)
+		memoryMailer := mailer.NewMemoryMailer()
+		loginAttemptUseCase := userUseCase.NewLoginAttemptUseCase(mockLoginAttemptStore, mockLockoutEventRepository, new(mocks.UserRepository), memoryMailer)
+
+		mockLoginAttemptStore.On("Fail", mock.Anything, "account:johndoe@example.com", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Duration")).Return(domain.LoginAttempt{Failures: 9}, nil).Once()
+		mockLoginAttemptStore.On("Fail", mock.Anything, "ip:10.0.0.1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Duration")).Return(domain.LoginAttempt{Failures: 9}, nil).Once()
+
+		err := loginAttemptUseCase.Fail(context.Background(), mockUser.Email, "10.0.0.1")
+
+		assert.NoError(t, err)
+		assert.Empty(t, memoryMailer.Sent())
+		mockLockoutEventRepository.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+		mockLoginAttemptStore.AssertExpectations(t)
+	})
+
+	t.Run("fail login reaching the lockout records and notifies", func(t *testing.T) {
+		mockLoginAttemptStore := new(mocks.LoginAttemptStore)
+		mockLockoutEventRepository := new(mocks.LockoutEventRepository)
+		mockUserRepository := new(mocks.UserRepository)
+		memoryMailer := mailer.NewMemoryMailer()
+		loginAttemptUseCase := userUseCase.NewLoginAttemptUseCase(mockLoginAttemptStore, mockLockoutEventRepository, mockUserRepository, memoryMailer)
+
+		var stored *domain.LockoutEvent
+
+		mockLoginAttemptStore.On("Fail", mock.Anything, "account:johndoe@example.com", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Duration")).Return(domain.LoginAttempt{Failures: 10}, nil).Once()
+		mockLoginAttemptStore.On("Fail", mock.Anything, "ip:10.0.0.1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Duration")).Return(domain.LoginAttempt{Failures: 10}, nil).Once()
+		mockUserRepository.On("GetByEmail", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.Email).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = mockUser
+		}).Return(nil).Once()
+		mockLockoutEventRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.LockoutEvent")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.LockoutEvent)
+		}).Return(nil).Once()
+
+		err := loginAttemptUseCase.Fail(context.Background(), mockUser.Email, "10.0.0.1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockUser.ID, stored.UserID)
+		assert.Equal(t, "10.0.0.1", stored.IPAddress)
+		assert.True(t, stored.LockedUntil.After(time.Now()))
+		assert.Len(t, memoryMailer.Sent(), 1)
+		assert.Equal(t, []string{mockUser.Email}, memoryMailer.Sent()[0].To)
+		mockLockoutEventRepository.AssertExpectations(t)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("fail login reaching the lockout for an unknown email", func(t *testing.T) {
+		mockLoginAttemptStore := new(mocks.LoginAttemptStore)
+		mockLockoutEventRepository := new(mocks.LockoutEventRepository)
+		mockUserRepository := new(mocks.UserRepository)
+		memoryMailer := mailer.NewMemoryMailer()
+		loginAttemptUseCase := userUseCase.NewLoginAttemptUseCase(mockLoginAttemptStore, mockLockoutEventRepository, mockUserRepository, memoryMailer)
+
+		mockLoginAttemptStore.On("Fail", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Duration")).Return(domain.LoginAttempt{Failures: 10}, nil).Twice()
+		mockUserRepository.On("GetByEmail", mock.Anything, mock.AnythingOfType("*domain.User"), "nobody@example.com").Return(gorm.ErrRecordNotFound).Once()
+
+		err := loginAttemptUseCase.Fail(context.Background(), "nobody@example.com", "10.0.0.1")
+
+		assert.NoError(t, err)
+		assert.Empty(t, memoryMailer.Sent())
+		mockLockoutEventRepository.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSucceedLoginAttempt(t *testing.T) {
+	mockLoginAttemptStore := new(mocks.LoginAttemptStore)
+	loginAttemptUseCase := userUseCase.NewLoginAttemptUseCase(mockLoginAttemptStore, new(mocks.LockoutEventRepository), new(mocks.UserRepository), mailer.NewMemoryMailer())
+
+	t.Run("succeed login clears the account only", func(t *testing.T) {
+		mockLoginAttemptStore.On("Reset", mock.Anything, "account:johndoe@example.com").Return(nil).Once()
+
+		err := loginAttemptUseCase.Succeed(context.Background(), "johndoe@example.com", "10.0.0.1")
+
+		assert.NoError(t, err)
+		mockLoginAttemptStore.AssertNotCalled(t, "Reset", mock.Anything, "ip:10.0.0.1")
+		mockLoginAttemptStore.AssertExpectations(t)
+	})
+}
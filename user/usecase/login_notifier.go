@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"time"
+)
+
+type mailLoginNotifier struct {
+	mailer domain.Mailer
+}
+
+// NewMailLoginNotifier mails the user whenever they log in from a device they have not used before.
+func NewMailLoginNotifier(mailer domain.Mailer) *mailLoginNotifier {
+	return &mailLoginNotifier{mailer}
+}
+
+func (mailLoginNotifier *mailLoginNotifier) NewDevice(ctx context.Context, user domain.User, session domain.Session) (err error) {
+	userAgent := session.UserAgent
+
+	if userAgent == "" {
+		userAgent = "an unknown device"
+	}
+
+	return mailLoginNotifier.mailer.Send(ctx, domain.Mail{
+		To:      []string{user.Email},
+		Subject: "New login to your MyGram account",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nYour MyGram account was just logged into from %s at %s, address %s.\n\nIf it was you, there is nothing to do. Otherwise end the session from your account settings and change your password.\n",
+			user.Username, userAgent, session.LastSeenAt.UTC().Format(time.RFC1123), session.IP,
+		),
+	})
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"strings"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+const (
+	mfaPendingPurpose  = "mfa_pending"
+	mfaIssuer          = "MyGram"
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+)
+
+// mfaPending is spent by its first successful Verify, through the revocation list
+// under JTI, so it cannot be exchanged for a second pair of tokens.
+type mfaPending struct {
+	UserID   string `json:"id"`
+	JTI      string `json:"jti"`
+	IssuedAt int64  `json:"iat"`
+}
+
+type mfaUseCase struct {
+	userRepository       domain.UserRepository
+	mfaRepository        domain.MFARepository
+	revocationRepository domain.RevocationRepository
+}
+
+func NewMFAUseCase(userRepository domain.UserRepository, mfaRepository domain.MFARepository, revocationRepository domain.RevocationRepository) *mfaUseCase {
+	return &mfaUseCase{userRepository, mfaRepository, revocationRepository}
+}
+
+// Setup generates a new secret for the user. It only takes effect once a code
+// made from it is confirmed.
+func (mfaUseCase *mfaUseCase) Setup(ctx context.Context, userID string) (setup domain.MFASetup, err error) {
+	user := domain.User{}
+
+	if err = mfaUseCase.userRepository.GetByID(ctx, &user, userID); err != nil {
+		return setup, err
+	}
+
+	if user.TOTPEnabledAt != nil {
+		return setup, domain.ErrMFAAlreadyEnabled
+	}
+
+	if setup.Secret, err = helpers.GenerateTOTPSecret(); err != nil {
+		return setup, err
+	}
+
+	if err = mfaUseCase.mfaRepository.SetTOTPSecret(ctx, userID, setup.Secret); err != nil {
+		return domain.MFASetup{}, err
+	}
+
+	setup.URI = helpers.TOTPURI(mfaIssuer, user.Email, setup.Secret)
+
+	return setup, nil
+}
+
+// Confirm enables two-factor authentication when code matches the secret from
+// Setup and returns the recovery codes, which are never retrievable again.
+func (mfaUseCase *mfaUseCase) Confirm(ctx context.Context, userID string, code string) (recoveryCodes []string, err error) {
+	user := domain.User{}
+
+	if err = mfaUseCase.userRepository.GetByID(ctx, &user, userID); err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabledAt != nil {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	if user.TOTPSecret == "" {
+		return nil, domain.ErrMFANotSetUp
+	}
+
+	step, isValid := helpers.ValidateTOTP(user.TOTPSecret, normalizeCode(code), time.Now())
+
+	if !isValid {
+		return nil, domain.ErrMFACodeInvalid
+	}
+
+	stored := make([]domain.RecoveryCode, recoveryCodeCount)
+	recoveryCodes = make([]string, recoveryCodeCount)
+
+	for i := range recoveryCodes {
+		if recoveryCodes[i], err = newRecoveryCode(); err != nil {
+			return nil, err
+		}
+
+		stored[i].CodeHash = helpers.HashToken(normalizeCode(recoveryCodes[i]))
+	}
+
+	if err = mfaUseCase.mfaRepository.EnableTOTP(ctx, userID, step, stored); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func (mfaUseCase *mfaUseCase) Disable(ctx context.Context, userID string, password string, code string) (err error) {
+	user := domain.User{}
+
+	if err = mfaUseCase.userRepository.GetByID(ctx, &user, userID); err != nil {
+		return err
+	}
+
+	if user.TOTPEnabledAt == nil {
+		return domain.ErrMFANotEnabled
+	}
+
+	if isValid := helpers.Compare([]byte(user.Password), []byte(password)); !isValid {
+		return domain.ErrPasswordIncorrect
+	}
+
+	if err = mfaUseCase.useCode(ctx, user, code); err != nil {
+		return err
+	}
+
+	return mfaUseCase.mfaRepository.DisableTOTP(ctx, userID)
+}
+
+// Challenge returns the short-lived token a user whose password was accepted
+// exchanges, together with a code, for their access and refresh tokens.
+func (mfaUseCase *mfaUseCase) Challenge(ctx context.Context, user domain.User) (string, error) {
+	jti, err := gonanoid.New(21)
+
+	if err != nil {
+		return "", err
+	}
+
+	return helpers.Sign(mfaPendingPurpose, mfaPending{user.ID, jti, time.Now().UnixMilli()}, helpers.MFAPendingTTL())
+}
+
+// Pending returns the user completing the login with token, so the attempt can be
+// throttled before any code is checked.
+func (mfaUseCase *mfaUseCase) Pending(ctx context.Context, token string) (user domain.User, err error) {
+	user, _, err = mfaUseCase.pending(ctx, token)
+
+	return user, err
+}
+
+func (mfaUseCase *mfaUseCase) Verify(ctx context.Context, token string, code string) (user domain.User, err error) {
+	user, pending, err := mfaUseCase.pending(ctx, token)
+
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if err = mfaUseCase.useCode(ctx, user, code); err != nil {
+		return domain.User{}, err
+	}
+
+	expiresAt := time.UnixMilli(pending.IssuedAt).Add(helpers.MFAPendingTTL())
+
+	if err = mfaUseCase.revocationRepository.RevokeToken(ctx, domain.RevokedToken{
+		JTI:       pending.JTI,
+		UserID:    user.ID,
+		ExpiresAt: &expiresAt,
+	}); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// pending decodes token and rejects it once spent, or once every login of its
+// user was revoked after it was issued.
+func (mfaUseCase *mfaUseCase) pending(ctx context.Context, token string) (user domain.User, pending mfaPending, err error) {
+	if err = helpers.Verify(mfaPendingPurpose, token, &pending); err != nil || pending.JTI == "" {
+		return user, pending, domain.ErrMFAPendingInvalid
+	}
+
+	revoked, err := mfaUseCase.revocationRepository.IsRevoked(ctx, pending.JTI, "", pending.UserID, time.UnixMilli(pending.IssuedAt))
+
+	if err != nil {
+		return user, pending, err
+	}
+
+	if revoked {
+		return user, pending, domain.ErrMFAPendingInvalid
+	}
+
+	if err = mfaUseCase.userRepository.GetByID(ctx, &user, pending.UserID); err != nil || user.TOTPEnabledAt == nil {
+		return domain.User{}, pending, domain.ErrMFAPendingInvalid
+	}
+
+	return user, pending, nil
+}
+
+// useCode accepts either a current TOTP code or an unused recovery code. Each is
+// spent on success so neither can be replayed.
+func (mfaUseCase *mfaUseCase) useCode(ctx context.Context, user domain.User, code string) error {
+	code = normalizeCode(code)
+
+	if len(code) == recoveryCodeLength {
+		return mfaUseCase.mfaRepository.UseRecoveryCode(ctx, user.ID, helpers.HashToken(code))
+	}
+
+	step, isValid := helpers.ValidateTOTP(user.TOTPSecret, code, time.Now())
+
+	if !isValid {
+		return domain.ErrMFACodeInvalid
+	}
+
+	return mfaUseCase.mfaRepository.UseTOTPStep(ctx, user.ID, step)
+}
+
+func newRecoveryCode() (string, error) {
+	raw := make([]byte, 7)
+
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	code := base32.StdEncoding.EncodeToString(raw)[:recoveryCodeLength]
+
+	return code[:5] + "-" + code[5:], nil
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,288 @@
+package usecase_test
+
+import (
+	"context"
+	"mygram-api/domain"
+	"mygram-api/domain/mocks"
+	"mygram-api/helpers"
+	"strings"
+	"testing"
+	"time"
+
+	userUseCase "mygram-api/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetupMFA(t *testing.T) {
+	mockUser := domain.User{ID: "user-123", Email: "johndoe@example.com"}
+
+	t.Run("setup mfa correctly", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = mockUser
+		}).Return(nil).Once()
+		mockMFARepository.On("SetTOTPSecret", mock.Anything, mockUser.ID, mock.AnythingOfType("string")).Return(nil).Once()
+
+		setup, err := mfaUseCase.Setup(context.Background(), mockUser.ID)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, setup.Secret)
+		assert.True(t, strings.HasPrefix(setup.URI, "otpauth://totp/MyGram:johndoe@example.com?"))
+		assert.Contains(t, setup.URI, "secret="+setup.Secret)
+		mockUserRepository.AssertExpectations(t)
+		mockMFARepository.AssertExpectations(t)
+	})
+
+	t.Run("setup mfa that is already enabled", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+		now := time.Now()
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = domain.User{ID: mockUser.ID, TOTPSecret: "SECRET", TOTPEnabledAt: &now}
+		}).Return(nil).Once()
+
+		_, err := mfaUseCase.Setup(context.Background(), mockUser.ID)
+
+		assert.ErrorIs(t, err, domain.ErrMFAAlreadyEnabled)
+		mockMFARepository.AssertNotCalled(t, "SetTOTPSecret", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestConfirmMFA(t *testing.T) {
+	secret, _ := helpers.GenerateTOTPSecret()
+	mockUser := domain.User{ID: "user-123", Email: "johndoe@example.com", TOTPSecret: secret}
+
+	withUser := func(user domain.User) func(args mock.Arguments) {
+		return func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = user
+		}
+	}
+
+	t.Run("confirm mfa correctly", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+		step := helpers.TOTPStep(time.Now())
+		code, _ := helpers.TOTPCode(secret, step)
+
+		var stored []domain.RecoveryCode
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser(mockUser)).Return(nil).Once()
+		mockMFARepository.On("EnableTOTP", mock.Anything, mockUser.ID, mock.AnythingOfType("int64"), mock.AnythingOfType("[]domain.RecoveryCode")).Run(func(args mock.Arguments) {
+			stored = args.Get(3).([]domain.RecoveryCode)
+		}).Return(nil).Once()
+
+		recoveryCodes, err := mfaUseCase.Confirm(context.Background(), mockUser.ID, code)
+
+		assert.NoError(t, err)
+		assert.Len(t, recoveryCodes, 10)
+		assert.Len(t, stored, 10)
+		assert.Equal(t, helpers.HashToken(strings.ReplaceAll(recoveryCodes[0], "-", "")), stored[0].CodeHash)
+		mockMFARepository.AssertExpectations(t)
+	})
+
+	t.Run("confirm mfa with invalid code", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+		code, _ := helpers.TOTPCode(secret, helpers.TOTPStep(time.Now())-10)
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser(mockUser)).Return(nil).Once()
+
+		_, err := mfaUseCase.Confirm(context.Background(), mockUser.ID, code)
+
+		assert.ErrorIs(t, err, domain.ErrMFACodeInvalid)
+		mockMFARepository.AssertNotCalled(t, "EnableTOTP", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("confirm mfa before setting it up", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser(domain.User{ID: mockUser.ID})).Return(nil).Once()
+
+		_, err := mfaUseCase.Confirm(context.Background(), mockUser.ID, "123456")
+
+		assert.ErrorIs(t, err, domain.ErrMFANotSetUp)
+	})
+}
+
+func TestDisableMFA(t *testing.T) {
+	now := time.Now()
+	secret, _ := helpers.GenerateTOTPSecret()
+	hashedPassword, _ := helpers.NewBcryptHasher(4).Hash("secret")
+	mockUser := domain.User{ID: "user-123", Password: hashedPassword, TOTPSecret: secret, TOTPEnabledAt: &now}
+
+	t.Run("disable mfa correctly", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+		step := helpers.TOTPStep(time.Now())
+		code, _ := helpers.TOTPCode(secret, step)
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = mockUser
+		}).Return(nil).Once()
+		mockMFARepository.On("UseTOTPStep", mock.Anything, mockUser.ID, step).Return(nil).Once()
+		mockMFARepository.On("DisableTOTP", mock.Anything, mockUser.ID).Return(nil).Once()
+
+		err := mfaUseCase.Disable(context.Background(), mockUser.ID, "secret", code)
+
+		assert.NoError(t, err)
+		mockMFARepository.AssertExpectations(t)
+	})
+
+	t.Run("disable mfa with incorrect password", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+		code, _ := helpers.TOTPCode(secret, helpers.TOTPStep(time.Now()))
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = mockUser
+		}).Return(nil).Once()
+
+		err := mfaUseCase.Disable(context.Background(), mockUser.ID, "wrong", code)
+
+		assert.ErrorIs(t, err, domain.ErrPasswordIncorrect)
+		mockMFARepository.AssertNotCalled(t, "DisableTOTP", mock.Anything, mock.Anything)
+	})
+}
+
+func TestVerifyMFA(t *testing.T) {
+	t.Setenv("SIGNING_KEY", "secret")
+
+	now := time.Now()
+	secret, _ := helpers.GenerateTOTPSecret()
+	mockUser := domain.User{ID: "user-123", TOTPSecret: secret, TOTPEnabledAt: &now}
+
+	withUser := func(args mock.Arguments) {
+		*args.Get(1).(*domain.User) = mockUser
+	}
+
+	t.Run("verify mfa with a current code", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+		step := helpers.TOTPStep(time.Now())
+		code, _ := helpers.TOTPCode(secret, step)
+		pending, _ := mfaUseCase.Challenge(context.Background(), mockUser)
+
+		mockRevocationRepository.On("IsRevoked", mock.Anything, mock.AnythingOfType("string"), "", mockUser.ID, mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+		mockMFARepository.On("UseTOTPStep", mock.Anything, mockUser.ID, step).Return(nil).Once()
+		mockRevocationRepository.On("RevokeToken", mock.Anything, mock.MatchedBy(func(revokedToken domain.RevokedToken) bool {
+			return revokedToken.JTI != "" && revokedToken.UserID == mockUser.ID && revokedToken.ExpiresAt.After(time.Now())
+		})).Return(nil).Once()
+
+		user, err := mfaUseCase.Verify(context.Background(), pending, code)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockUser.ID, user.ID)
+		mockMFARepository.AssertExpectations(t)
+		mockRevocationRepository.AssertExpectations(t)
+	})
+
+	t.Run("verify mfa with a spent token", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+		code, _ := helpers.TOTPCode(secret, helpers.TOTPStep(time.Now()))
+		pending, _ := mfaUseCase.Challenge(context.Background(), mockUser)
+
+		mockRevocationRepository.On("IsRevoked", mock.Anything, mock.AnythingOfType("string"), "", mockUser.ID, mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+
+		_, err := mfaUseCase.Verify(context.Background(), pending, code)
+
+		assert.ErrorIs(t, err, domain.ErrMFAPendingInvalid)
+		mockMFARepository.AssertNotCalled(t, "UseTOTPStep", mock.Anything, mock.Anything, mock.Anything)
+		mockRevocationRepository.AssertExpectations(t)
+	})
+
+	t.Run("verify mfa with a replayed code", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+		step := helpers.TOTPStep(time.Now())
+		code, _ := helpers.TOTPCode(secret, step)
+		pending, _ := mfaUseCase.Challenge(context.Background(), mockUser)
+
+		mockRevocationRepository.On("IsRevoked", mock.Anything, mock.AnythingOfType("string"), "", mockUser.ID, mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+		mockMFARepository.On("UseTOTPStep", mock.Anything, mockUser.ID, step).Return(domain.ErrMFACodeInvalid).Once()
+
+		user, err := mfaUseCase.Verify(context.Background(), pending, code)
+
+		assert.ErrorIs(t, err, domain.ErrMFACodeInvalid)
+		assert.Empty(t, user.ID)
+		mockRevocationRepository.AssertNotCalled(t, "RevokeToken", mock.Anything, mock.Anything)
+	})
+
+	t.Run("verify mfa with a recovery code", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+		pending, _ := mfaUseCase.Challenge(context.Background(), mockUser)
+
+		mockRevocationRepository.On("IsRevoked", mock.Anything, mock.AnythingOfType("string"), "", mockUser.ID, mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+		mockMFARepository.On("UseRecoveryCode", mock.Anything, mockUser.ID, helpers.HashToken("ABCDEFGHIJ")).Return(nil).Once()
+		mockRevocationRepository.On("RevokeToken", mock.Anything, mock.AnythingOfType("domain.RevokedToken")).Return(nil).Once()
+
+		user, err := mfaUseCase.Verify(context.Background(), pending, "abcde-fghij")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockUser.ID, user.ID)
+		mockMFARepository.AssertExpectations(t)
+	})
+
+	t.Run("verify mfa with tampered token", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+		pending, _ := mfaUseCase.Challenge(context.Background(), mockUser)
+
+		_, err := mfaUseCase.Verify(context.Background(), "x"+pending, "123456")
+
+		assert.ErrorIs(t, err, domain.ErrMFAPendingInvalid)
+		mockUserRepository.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("fetch the user of a pending login", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockMFARepository := new(mocks.MFARepository)
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		mfaUseCase := userUseCase.NewMFAUseCase(mockUserRepository, mockMFARepository, mockRevocationRepository)
+		pending, _ := mfaUseCase.Challenge(context.Background(), mockUser)
+
+		mockRevocationRepository.On("IsRevoked", mock.Anything, mock.AnythingOfType("string"), "", mockUser.ID, mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+
+		user, err := mfaUseCase.Pending(context.Background(), pending)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockUser.ID, user.ID)
+		mockMFARepository.AssertNotCalled(t, "UseTOTPStep", mock.Anything, mock.Anything, mock.Anything)
+		mockRevocationRepository.AssertNotCalled(t, "RevokeToken", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"strings"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	oidcLoginPurpose = "oidc_login"
+	usernameAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// oidcLogin is what the browser carries between Start and Callback. It is
+// signed, so the verifier and nonce can't be swapped for the attacker's own.
+type oidcLogin struct {
+	Provider     string `json:"p"`
+	State        string `json:"s"`
+	Nonce        string `json:"n"`
+	CodeVerifier string `json:"v"`
+}
+
+type oidcUseCase struct {
+	providers                  map[string]domain.OIDCProvider
+	userRepository             domain.UserRepository
+	externalIdentityRepository domain.ExternalIdentityRepository
+}
+
+func NewOIDCUseCase(providers map[string]domain.OIDCProvider, userRepository domain.UserRepository, externalIdentityRepository domain.ExternalIdentityRepository) *oidcUseCase {
+	return &oidcUseCase{providers, userRepository, externalIdentityRepository}
+}
+
+func (oidcUseCase *oidcUseCase) Start(ctx context.Context, providerName string) (login domain.OIDCLogin, err error) {
+	provider, ok := oidcUseCase.providers[providerName]
+
+	if !ok {
+		return login, domain.ErrOIDCProviderUnknown
+	}
+
+	pending := oidcLogin{Provider: providerName}
+
+	for _, value := range []*string{&pending.State, &pending.Nonce, &pending.CodeVerifier} {
+		if *value, err = helpers.GenerateRandomToken(32); err != nil {
+			return login, err
+		}
+	}
+
+	if login.URL, err = provider.AuthCodeURL(ctx, pending.State, pending.Nonce, pending.CodeVerifier); err != nil {
+		return domain.OIDCLogin{}, err
+	}
+
+	if login.Flow, err = helpers.Sign(oidcLoginPurpose, pending, helpers.OIDCLoginTTL()); err != nil {
+		return domain.OIDCLogin{}, err
+	}
+
+	return login, nil
+}
+
+// Callback finishes the login started for flow and returns the user it signs
+// in. A known identity signs in its user; otherwise the identity is linked to
+// the account with the same verified email, or a new account is created.
+func (oidcUseCase *oidcUseCase) Callback(ctx context.Context, providerName string, flow string, state string, code string) (user domain.User, err error) {
+	provider, ok := oidcUseCase.providers[providerName]
+
+	if !ok {
+		return user, domain.ErrOIDCProviderUnknown
+	}
+
+	pending := oidcLogin{}
+
+	if err = helpers.Verify(oidcLoginPurpose, flow, &pending); err != nil || pending.Provider != providerName || subtle.ConstantTimeCompare([]byte(pending.State), []byte(state)) != 1 {
+		return user, domain.ErrOIDCLoginInvalid
+	}
+
+	claims, err := provider.Exchange(ctx, code, pending.CodeVerifier, pending.Nonce)
+
+	if err != nil {
+		return user, err
+	}
+
+	identity := domain.ExternalIdentity{}
+
+	if err = oidcUseCase.externalIdentityRepository.GetBySubject(ctx, &identity, providerName, claims.Subject); err == nil {
+		if err = oidcUseCase.userRepository.GetByID(ctx, &user, identity.UserID); err != nil {
+			return domain.User{}, err
+		}
+
+		return user, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return user, domain.ErrOIDCEmailUnverified
+	}
+
+	if err = oidcUseCase.userRepository.GetByEmail(ctx, &user, claims.Email); err == nil {
+		// Whoever registered an unverified address may not own it, and linking
+		// would let them back into the account with their password.
+		if user.EmailVerifiedAt == nil {
+			return domain.User{}, domain.ErrOIDCAccountUnverified
+		}
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		if user, err = oidcUseCase.register(ctx, claims); err != nil {
+			return domain.User{}, err
+		}
+	} else {
+		return domain.User{}, err
+	}
+
+	identity = domain.ExternalIdentity{
+		UserID:   user.ID,
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+
+	if err = oidcUseCase.externalIdentityRepository.Store(ctx, &identity); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// register creates an account for claims. Its password is random, a password
+// reset sets one for users who want to sign in without the provider as well.
+func (oidcUseCase *oidcUseCase) register(ctx context.Context, claims domain.OIDCClaims) (user domain.User, err error) {
+	password, err := helpers.GenerateRandomToken(32)
+
+	if err != nil {
+		return user, err
+	}
+
+	suffix, err := gonanoid.Generate(usernameAlphabet, 6)
+
+	if err != nil {
+		return user, err
+	}
+
+	now := time.Now()
+	user = domain.User{
+		Username:        usernameBase(claims) + "-" + suffix,
+		Email:           claims.Email,
+		Password:        password,
+		EmailVerifiedAt: &now,
+	}
+
+	if err = oidcUseCase.userRepository.Register(ctx, &user); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// usernameBase picks a readable start for the username of a new account from
+// the provider's preferred username or the local part of the email.
+func usernameBase(claims domain.OIDCClaims) string {
+	base := claims.PreferredUsername
+
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	base = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		default:
+			return -1
+		}
+	}, strings.ToLower(base))
+
+	if len(base) > 32 {
+		base = base[:32]
+	}
+
+	if base == "" {
+		base = "user"
+	}
+
+	return base
+}
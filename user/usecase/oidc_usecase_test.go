@@ -0,0 +1,189 @@
+package usecase_test
+
+import (
+	"context"
+	"mygram-api/domain"
+	"mygram-api/domain/mocks"
+	"mygram-api/oidc"
+	"mygram-api/oidc/oidctest"
+	"strings"
+	"testing"
+	"time"
+
+	userUseCase "mygram-api/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// startOIDCLogin starts a login and signs in at the stand-in provider, returning
+// the flow, state and code the callback receives.
+func startOIDCLogin(t *testing.T, server *oidctest.Server, useCase domain.OIDCUseCase) (flow string, state string, code string) {
+	login, err := useCase.Start(context.Background(), "test")
+	require.NoError(t, err)
+
+	code, state, err = server.Authorize(login.URL)
+	require.NoError(t, err)
+
+	return login.Flow, state, code
+}
+
+func TestOIDCCallback(t *testing.T) {
+	t.Setenv("SIGNING_KEY", "secret")
+
+	now := time.Now()
+	server := oidctest.NewServer("mygram", "")
+	defer server.Close()
+
+	providers := map[string]domain.OIDCProvider{
+		"test": oidc.NewProvider(oidc.Config{
+			Issuer:      server.URL,
+			ClientID:    "mygram",
+			RedirectURL: "http://localhost:8080/auth/test/callback",
+			Scopes:      []string{"openid", "email"},
+		}, nil),
+	}
+
+	claims := map[string]interface{}{
+		"sub":                "subject-123",
+		"email":              "johndoe@example.com",
+		"email_verified":     true,
+		"preferred_username": "John.Doe",
+	}
+
+	t.Run("callback creates a new account", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockExternalIdentityRepository := new(mocks.ExternalIdentityRepository)
+		oidcUseCase := userUseCase.NewOIDCUseCase(providers, mockUserRepository, mockExternalIdentityRepository)
+
+		server.SetClaims(claims)
+
+		mockExternalIdentityRepository.On("GetBySubject", mock.Anything, mock.AnythingOfType("*domain.ExternalIdentity"), "test", "subject-123").Return(gorm.ErrRecordNotFound).Once()
+		mockUserRepository.On("GetByEmail", mock.Anything, mock.AnythingOfType("*domain.User"), "johndoe@example.com").Return(gorm.ErrRecordNotFound).Once()
+		mockUserRepository.On("Register", mock.Anything, mock.AnythingOfType("*domain.User")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.User).ID = "user-123"
+		}).Return(nil).Once()
+		mockExternalIdentityRepository.On("Store", mock.Anything, mock.MatchedBy(func(identity *domain.ExternalIdentity) bool {
+			return identity.UserID == "user-123" && identity.Provider == "test" && identity.Subject == "subject-123"
+		})).Return(nil).Once()
+
+		flow, state, code := startOIDCLogin(t, server, oidcUseCase)
+		user, err := oidcUseCase.Callback(context.Background(), "test", flow, state, code)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", user.ID)
+		assert.Equal(t, "johndoe@example.com", user.Email)
+		assert.True(t, strings.HasPrefix(user.Username, "john.doe-"))
+		assert.NotNil(t, user.EmailVerifiedAt)
+		assert.NotEmpty(t, user.Password)
+		mockUserRepository.AssertExpectations(t)
+		mockExternalIdentityRepository.AssertExpectations(t)
+	})
+
+	t.Run("callback signs in a linked identity", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockExternalIdentityRepository := new(mocks.ExternalIdentityRepository)
+		oidcUseCase := userUseCase.NewOIDCUseCase(providers, mockUserRepository, mockExternalIdentityRepository)
+
+		server.SetClaims(claims)
+
+		mockExternalIdentityRepository.On("GetBySubject", mock.Anything, mock.AnythingOfType("*domain.ExternalIdentity"), "test", "subject-123").Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.ExternalIdentity).UserID = "user-123"
+		}).Return(nil).Once()
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-123").Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.User).ID = "user-123"
+		}).Return(nil).Once()
+
+		flow, state, code := startOIDCLogin(t, server, oidcUseCase)
+		user, err := oidcUseCase.Callback(context.Background(), "test", flow, state, code)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", user.ID)
+		mockExternalIdentityRepository.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("callback links an account with the same verified email", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockExternalIdentityRepository := new(mocks.ExternalIdentityRepository)
+		oidcUseCase := userUseCase.NewOIDCUseCase(providers, mockUserRepository, mockExternalIdentityRepository)
+
+		server.SetClaims(claims)
+
+		mockExternalIdentityRepository.On("GetBySubject", mock.Anything, mock.AnythingOfType("*domain.ExternalIdentity"), "test", "subject-123").Return(gorm.ErrRecordNotFound).Once()
+		mockUserRepository.On("GetByEmail", mock.Anything, mock.AnythingOfType("*domain.User"), "johndoe@example.com").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = domain.User{ID: "user-234", Email: "johndoe@example.com", EmailVerifiedAt: &now}
+		}).Return(nil).Once()
+		mockExternalIdentityRepository.On("Store", mock.Anything, mock.MatchedBy(func(identity *domain.ExternalIdentity) bool {
+			return identity.UserID == "user-234"
+		})).Return(nil).Once()
+
+		flow, state, code := startOIDCLogin(t, server, oidcUseCase)
+		user, err := oidcUseCase.Callback(context.Background(), "test", flow, state, code)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-234", user.ID)
+		mockUserRepository.AssertNotCalled(t, "Register", mock.Anything, mock.Anything)
+		mockExternalIdentityRepository.AssertExpectations(t)
+	})
+
+	t.Run("callback refuses to link an account with an unverified email", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockExternalIdentityRepository := new(mocks.ExternalIdentityRepository)
+		oidcUseCase := userUseCase.NewOIDCUseCase(providers, mockUserRepository, mockExternalIdentityRepository)
+
+		server.SetClaims(claims)
+
+		mockExternalIdentityRepository.On("GetBySubject", mock.Anything, mock.AnythingOfType("*domain.ExternalIdentity"), "test", "subject-123").Return(gorm.ErrRecordNotFound).Once()
+		mockUserRepository.On("GetByEmail", mock.Anything, mock.AnythingOfType("*domain.User"), "johndoe@example.com").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = domain.User{ID: "user-234", Email: "johndoe@example.com"}
+		}).Return(nil).Once()
+
+		flow, state, code := startOIDCLogin(t, server, oidcUseCase)
+		_, err := oidcUseCase.Callback(context.Background(), "test", flow, state, code)
+
+		assert.ErrorIs(t, err, domain.ErrOIDCAccountUnverified)
+		mockExternalIdentityRepository.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("callback with an email the provider didn't verify", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockExternalIdentityRepository := new(mocks.ExternalIdentityRepository)
+		oidcUseCase := userUseCase.NewOIDCUseCase(providers, mockUserRepository, mockExternalIdentityRepository)
+
+		server.SetClaims(map[string]interface{}{"sub": "subject-123", "email": "johndoe@example.com", "email_verified": false})
+
+		mockExternalIdentityRepository.On("GetBySubject", mock.Anything, mock.AnythingOfType("*domain.ExternalIdentity"), "test", "subject-123").Return(gorm.ErrRecordNotFound).Once()
+
+		flow, state, code := startOIDCLogin(t, server, oidcUseCase)
+		_, err := oidcUseCase.Callback(context.Background(), "test", flow, state, code)
+
+		assert.ErrorIs(t, err, domain.ErrOIDCEmailUnverified)
+		mockUserRepository.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("callback with a state from another login", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockExternalIdentityRepository := new(mocks.ExternalIdentityRepository)
+		oidcUseCase := userUseCase.NewOIDCUseCase(providers, mockUserRepository, mockExternalIdentityRepository)
+
+		server.SetClaims(claims)
+
+		flow, _, _ := startOIDCLogin(t, server, oidcUseCase)
+		_, state, code := startOIDCLogin(t, server, oidcUseCase)
+		_, err := oidcUseCase.Callback(context.Background(), "test", flow, state, code)
+
+		assert.ErrorIs(t, err, domain.ErrOIDCLoginInvalid)
+		mockExternalIdentityRepository.AssertNotCalled(t, "GetBySubject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("callback for an unknown provider", func(t *testing.T) {
+		oidcUseCase := userUseCase.NewOIDCUseCase(providers, new(mocks.UserRepository), new(mocks.ExternalIdentityRepository))
+
+		_, err := oidcUseCase.Callback(context.Background(), "unknown", "flow", "state", "code")
+
+		assert.ErrorIs(t, err, domain.ErrOIDCProviderUnknown)
+	})
+}
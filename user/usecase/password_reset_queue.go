@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"mygram-api/domain"
+	"sync"
+	"time"
+)
+
+// passwordResetQueueSize bounds the password reset requests waiting to be
+// mailed, so a burst of them can't fan out into unbounded lookups and mail.
+const passwordResetQueueSize = 64
+
+// passwordResetTimeout is how long a single request may take to look up the
+// account, store the token and mail the link.
+const passwordResetTimeout = 30 * time.Second
+
+type passwordResetQueue struct {
+	passwordResetUseCase domain.PasswordResetUseCase
+	emails               chan string
+	waitGroup            sync.WaitGroup
+}
+
+// NewPasswordResetQueue starts workers goroutines handing the emails passed to
+// Enqueue to passwordResetUseCase.Forgot.
+func NewPasswordResetQueue(passwordResetUseCase domain.PasswordResetUseCase, workers int) *passwordResetQueue {
+	passwordResetQueue := &passwordResetQueue{
+		passwordResetUseCase: passwordResetUseCase,
+		emails:               make(chan string, passwordResetQueueSize),
+	}
+
+	for i := 0; i < workers; i++ {
+		passwordResetQueue.waitGroup.Add(1)
+
+		go passwordResetQueue.work()
+	}
+
+	return passwordResetQueue
+}
+
+// Enqueue never blocks the request. When the workers are that far behind the
+// request is dropped and the user has to ask again.
+func (passwordResetQueue *passwordResetQueue) Enqueue(email string) {
+	select {
+	case passwordResetQueue.emails <- email:
+	default:
+		log.Printf("the password reset queue is full, skipping %s", email)
+	}
+}
+
+// Close waits for the queued requests to be mailed and stops the workers.
+func (passwordResetQueue *passwordResetQueue) Close() {
+	close(passwordResetQueue.emails)
+	passwordResetQueue.waitGroup.Wait()
+}
+
+func (passwordResetQueue *passwordResetQueue) work() {
+	defer passwordResetQueue.waitGroup.Done()
+
+	for email := range passwordResetQueue.emails {
+		ctx, cancel := context.WithTimeout(context.Background(), passwordResetTimeout)
+
+		if err := passwordResetQueue.passwordResetUseCase.Forgot(ctx, email); err != nil {
+			log.Printf("sending the password reset email to %s failed: %s", email, err)
+		}
+
+		cancel()
+	}
+}
@@ -0,0 +1,27 @@
+package usecase_test
+
+import (
+	"errors"
+	"mygram-api/domain/mocks"
+	"testing"
+
+	userUseCase "mygram-api/user/usecase"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPasswordResetQueue(t *testing.T) {
+	t.Run("mail every queued request before closing", func(t *testing.T) {
+		mockPasswordResetUseCase := new(mocks.PasswordResetUseCase)
+		passwordResetQueue := userUseCase.NewPasswordResetQueue(mockPasswordResetUseCase, 2)
+
+		mockPasswordResetUseCase.On("Forgot", mock.Anything, "johndoe@example.com").Return(nil).Once()
+		mockPasswordResetUseCase.On("Forgot", mock.Anything, "janedoe@example.com").Return(errors.New("fail")).Once()
+
+		passwordResetQueue.Enqueue("johndoe@example.com")
+		passwordResetQueue.Enqueue("janedoe@example.com")
+		passwordResetQueue.Close()
+
+		mockPasswordResetUseCase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"time"
+)
+
+type passwordResetUseCase struct {
+	userRepository          domain.UserRepository
+	passwordResetRepository domain.PasswordResetRepository
+	tokenUseCase            domain.TokenUseCase
+	mailer                  domain.Mailer
+}
+
+func NewPasswordResetUseCase(userRepository domain.UserRepository, passwordResetRepository domain.PasswordResetRepository, tokenUseCase domain.TokenUseCase, mailer domain.Mailer) *passwordResetUseCase {
+	return &passwordResetUseCase{userRepository, passwordResetRepository, tokenUseCase, mailer}
+}
+
+// Forgot mails a reset link when email belongs to an account. It reports success
+// for unknown emails too so callers cannot probe which addresses are registered.
+func (passwordResetUseCase *passwordResetUseCase) Forgot(ctx context.Context, email string) (err error) {
+	user := domain.User{}
+
+	if err = passwordResetUseCase.userRepository.GetByEmail(ctx, &user, email); err != nil {
+		return nil
+	}
+
+	raw, err := helpers.GenerateRandomToken(32)
+
+	if err != nil {
+		return err
+	}
+
+	ttl := helpers.PasswordResetTTL()
+	expiresAt := time.Now().Add(ttl)
+	passwordReset := domain.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: helpers.HashToken(raw),
+		ExpiresAt: &expiresAt,
+	}
+
+	if err = passwordResetUseCase.passwordResetRepository.Store(ctx, &passwordReset); err != nil {
+		return err
+	}
+
+	return passwordResetUseCase.mailer.Send(ctx, domain.Mail{
+		To:      []string{user.Email},
+		Subject: "Reset your MyGram password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to reset the password of your MyGram account. Use the link below within %s to choose a new one:\n\n%s\n\nIf it wasn't you, you can ignore this email.\n",
+			user.Username, ttl, helpers.PasswordResetURL(raw),
+		),
+	})
+}
+
+func (passwordResetUseCase *passwordResetUseCase) Reset(ctx context.Context, token string, password string) (err error) {
+	if err = domain.ValidatePassword(password); err != nil {
+		return err
+	}
+
+	// Hash before consuming the token so a password the hasher rejects does not
+	// burn the reset link.
+	hash, err := helpers.Hash(password)
+
+	if err != nil {
+		return err
+	}
+
+	passwordReset := domain.PasswordReset{}
+
+	if err = passwordResetUseCase.passwordResetRepository.Consume(ctx, &passwordReset, helpers.HashToken(token)); err != nil {
+		return domain.ErrPasswordResetInvalid
+	}
+
+	if err = passwordResetUseCase.userRepository.UpdatePassword(ctx, passwordReset.UserID, hash); err != nil {
+		return err
+	}
+
+	if err = passwordResetUseCase.tokenUseCase.RevokeAll(ctx, passwordReset.UserID); err != nil {
+		return err
+	}
+
+	return
+}
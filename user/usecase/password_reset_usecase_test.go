@@ -0,0 +1,126 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/domain/mocks"
+	"mygram-api/helpers"
+	"mygram-api/mailer"
+	"net/url"
+	"strings"
+	"testing"
+
+	userUseCase "mygram-api/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestForgotPassword(t *testing.T) {
+	mockUser := domain.User{
+		ID:       "user-123",
+		Username: "johndoe",
+		Email:    "johndoe@example.com",
+	}
+
+	t.Run("forgot password correctly", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockPasswordResetRepository := new(mocks.PasswordResetRepository)
+		mockTokenUseCase := new(mocks.TokenUseCase)
+		memoryMailer := mailer.NewMemoryMailer()
+		passwordResetUseCase := userUseCase.NewPasswordResetUseCase(mockUserRepository, mockPasswordResetRepository, mockTokenUseCase, memoryMailer)
+
+		var stored *domain.PasswordReset
+
+		mockUserRepository.On("GetByEmail", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.Email).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.User) = mockUser
+		}).Return(nil).Once()
+		mockPasswordResetRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.PasswordReset")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.PasswordReset)
+		}).Return(nil).Once()
+
+		err := passwordResetUseCase.Forgot(context.Background(), mockUser.Email)
+
+		assert.NoError(t, err)
+		assert.Len(t, memoryMailer.Sent(), 1)
+
+		mail := memoryMailer.Sent()[0]
+		link := mail.Body[strings.Index(mail.Body, "http"):]
+		link = link[:strings.Index(link, "\n")]
+		parsed, err := url.Parse(link)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{mockUser.Email}, mail.To)
+		assert.Equal(t, mockUser.ID, stored.UserID)
+		assert.Equal(t, helpers.HashToken(parsed.Query().Get("token")), stored.TokenHash)
+		mockUserRepository.AssertExpectations(t)
+		mockPasswordResetRepository.AssertExpectations(t)
+	})
+
+	t.Run("forgot password with unknown email", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockPasswordResetRepository := new(mocks.PasswordResetRepository)
+		mockTokenUseCase := new(mocks.TokenUseCase)
+		memoryMailer := mailer.NewMemoryMailer()
+		passwordResetUseCase := userUseCase.NewPasswordResetUseCase(mockUserRepository, mockPasswordResetRepository, mockTokenUseCase, memoryMailer)
+
+		mockUserRepository.On("GetByEmail", mock.Anything, mock.AnythingOfType("*domain.User"), "nobody@example.com").Return(errors.New("record not found")).Once()
+
+		err := passwordResetUseCase.Forgot(context.Background(), "nobody@example.com")
+
+		assert.NoError(t, err)
+		assert.Empty(t, memoryMailer.Sent())
+		mockPasswordResetRepository.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockPasswordResetRepository := new(mocks.PasswordResetRepository)
+	mockTokenUseCase := new(mocks.TokenUseCase)
+	passwordResetUseCase := userUseCase.NewPasswordResetUseCase(mockUserRepository, mockPasswordResetRepository, mockTokenUseCase, mailer.NewMemoryMailer())
+
+	t.Run("reset password correctly", func(t *testing.T) {
+		mockPasswordResetRepository.On("Consume", mock.Anything, mock.AnythingOfType("*domain.PasswordReset"), helpers.HashToken("token")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.PasswordReset) = domain.PasswordReset{UserID: "user-123"}
+		}).Return(nil).Once()
+		mockUserRepository.On("UpdatePassword", mock.Anything, "user-123", mock.MatchedBy(func(hash string) bool {
+			return helpers.Compare([]byte(hash), []byte("newsecret"))
+		})).Return(nil).Once()
+		mockTokenUseCase.On("RevokeAll", mock.Anything, "user-123").Return(nil).Once()
+
+		err := passwordResetUseCase.Reset(context.Background(), "token", "newsecret")
+
+		assert.NoError(t, err)
+		mockPasswordResetRepository.AssertExpectations(t)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenUseCase.AssertExpectations(t)
+	})
+
+	t.Run("reset password with used or expired token", func(t *testing.T) {
+		mockPasswordResetRepository.On("Consume", mock.Anything, mock.AnythingOfType("*domain.PasswordReset"), helpers.HashToken("token")).Return(errors.New("record not found")).Once()
+
+		err := passwordResetUseCase.Reset(context.Background(), "token", "newsecret")
+
+		assert.ErrorIs(t, err, domain.ErrPasswordResetInvalid)
+		mockPasswordResetRepository.AssertExpectations(t)
+	})
+
+	t.Run("reset password with short password keeps the token", func(t *testing.T) {
+		err := passwordResetUseCase.Reset(context.Background(), "token", "short")
+
+		assert.ErrorIs(t, err, domain.ErrPasswordTooShort)
+		mockPasswordResetRepository.AssertNumberOfCalls(t, "Consume", 2)
+	})
+
+	t.Run("reset password over bcrypt's length limit keeps the token", func(t *testing.T) {
+		t.Setenv("PASSWORD_HASH_ALGORITHM", "bcrypt")
+
+		err := passwordResetUseCase.Reset(context.Background(), "token", strings.Repeat("a", 73))
+
+		assert.ErrorIs(t, err, helpers.ErrPasswordTooLong)
+		mockPasswordResetRepository.AssertNumberOfCalls(t, "Consume", 2)
+	})
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"mygram-api/domain"
+)
+
+// sessionHistoryLimit caps how many past logins the history reaches back.
+const sessionHistoryLimit = 50
+
+type sessionUseCase struct {
+	sessionRepository domain.SessionRepository
+}
+
+func NewSessionUseCase(sessionRepository domain.SessionRepository) *sessionUseCase {
+	return &sessionUseCase{sessionRepository}
+}
+
+func (sessionUseCase *sessionUseCase) Fetch(ctx context.Context, sessions *[]domain.Session, userID string) (err error) {
+	if err = sessionUseCase.sessionRepository.Fetch(ctx, sessions, userID); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (sessionUseCase *sessionUseCase) History(ctx context.Context, sessions *[]domain.Session, userID string) (err error) {
+	if err = sessionUseCase.sessionRepository.FetchHistory(ctx, sessions, userID, sessionHistoryLimit); err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,63 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/domain/mocks"
+	"testing"
+
+	userUseCase "mygram-api/user/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFetchSessions(t *testing.T) {
+	mockSessions := []domain.Session{
+		{ID: "session-123", UserID: "user-123"},
+		{ID: "session-234", UserID: "user-123"},
+	}
+
+	mockSessionRepository := new(mocks.SessionRepository)
+	sessionUseCase := userUseCase.NewSessionUseCase(mockSessionRepository)
+
+	t.Run("fetch sessions correctly", func(t *testing.T) {
+		mockSessionRepository.On("Fetch", mock.Anything, mock.AnythingOfType("*[]domain.Session"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*[]domain.Session) = mockSessions
+		}).Return(nil).Once()
+
+		sessions := []domain.Session{}
+
+		err := sessionUseCase.Fetch(context.Background(), &sessions, "user-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockSessions, sessions)
+		mockSessionRepository.AssertExpectations(t)
+	})
+
+	t.Run("fetch login history with a limit", func(t *testing.T) {
+		mockSessionRepository.On("FetchHistory", mock.Anything, mock.AnythingOfType("*[]domain.Session"), "user-123", 50).Run(func(args mock.Arguments) {
+			*args.Get(1).(*[]domain.Session) = mockSessions
+		}).Return(nil).Once()
+
+		sessions := []domain.Session{}
+
+		err := sessionUseCase.History(context.Background(), &sessions, "user-123")
+
+		assert.NoError(t, err)
+		assert.Len(t, sessions, 2)
+		mockSessionRepository.AssertExpectations(t)
+	})
+
+	t.Run("fetch login history with failing repository", func(t *testing.T) {
+		mockSessionRepository.On("FetchHistory", mock.Anything, mock.AnythingOfType("*[]domain.Session"), "user-123", 50).Return(errors.New("fail")).Once()
+
+		sessions := []domain.Session{}
+
+		err := sessionUseCase.History(context.Background(), &sessions, "user-123")
+
+		assert.Error(t, err)
+		mockSessionRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"mygram-api/domain"
+	"mygram-api/helpers"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type tokenUseCase struct {
+	refreshTokenRepository domain.RefreshTokenRepository
+	revocationRepository   domain.RevocationRepository
+	sessionRepository      domain.SessionRepository
+	loginNotifier          domain.LoginNotifier
+}
+
+func NewTokenUseCase(refreshTokenRepository domain.RefreshTokenRepository, revocationRepository domain.RevocationRepository, sessionRepository domain.SessionRepository, loginNotifier domain.LoginNotifier) *tokenUseCase {
+	return &tokenUseCase{refreshTokenRepository, revocationRepository, sessionRepository, loginNotifier}
+}
+
+// Issue starts a new session for the user on client and hands out its first tokens.
+func (tokenUseCase *tokenUseCase) Issue(ctx context.Context, user domain.User, client domain.Client) (token domain.Token, err error) {
+	now := time.Now()
+	expiresAt := now.Add(helpers.RefreshTokenTTL())
+
+	session := domain.Session{
+		UserID:     user.ID,
+		UserAgent:  truncate(client.UserAgent, 255),
+		IP:         client.IP,
+		DeviceHash: helpers.HashToken(client.UserAgent),
+		LastSeenAt: &now,
+		ExpiresAt:  &expiresAt,
+	}
+
+	newDevice, err := tokenUseCase.isNewDevice(ctx, session)
+
+	if err != nil {
+		return token, err
+	}
+
+	if err = tokenUseCase.sessionRepository.Store(ctx, &session); err != nil {
+		return token, err
+	}
+
+	refreshToken := domain.RefreshToken{UserID: user.ID, FamilyID: session.ID}
+
+	if token.RefreshToken, err = newRefreshToken(&refreshToken); err != nil {
+		return token, err
+	}
+
+	if err = tokenUseCase.refreshTokenRepository.Store(ctx, &refreshToken); err != nil {
+		return token, err
+	}
+
+	// Telling the user is a courtesy, a mail that cannot go out must not stop the login.
+	if newDevice {
+		if err := tokenUseCase.loginNotifier.NewDevice(ctx, user, session); err != nil {
+			log.Printf("notifying user %s of a login from a new device failed: %s", user.ID, err)
+		}
+	}
+
+	return signAccessToken(token, user, session.ID)
+}
+
+func (tokenUseCase *tokenUseCase) Refresh(ctx context.Context, presented string) (token domain.Token, err error) {
+	current := domain.RefreshToken{}
+
+	if err = tokenUseCase.refreshTokenRepository.GetByHash(ctx, &current, helpers.HashToken(presented)); err != nil || current.User == nil {
+		return token, domain.ErrRefreshTokenInvalid
+	}
+
+	// A token that was already rotated out is being replayed, so whoever holds
+	// the rest of the chain can no longer be trusted either.
+	if current.RevokedAt != nil {
+		if err = tokenUseCase.refreshTokenRepository.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return token, err
+		}
+
+		return token, domain.ErrRefreshTokenReused
+	}
+
+	if current.ExpiresAt == nil || current.ExpiresAt.Before(time.Now()) {
+		return token, domain.ErrRefreshTokenExpired
+	}
+
+	next := domain.RefreshToken{UserID: current.UserID}
+
+	if token.RefreshToken, err = newRefreshToken(&next); err != nil {
+		return token, err
+	}
+
+	if err = tokenUseCase.refreshTokenRepository.Rotate(ctx, &current, &next); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			if revokeErr := tokenUseCase.refreshTokenRepository.RevokeFamily(ctx, current.FamilyID); revokeErr != nil {
+				return token, revokeErr
+			}
+		}
+
+		return token, err
+	}
+
+	if err = tokenUseCase.sessionRepository.Touch(ctx, current.FamilyID, time.Now(), *next.ExpiresAt); err != nil {
+		return token, err
+	}
+
+	return signAccessToken(token, *current.User, current.FamilyID)
+}
+
+func (tokenUseCase *tokenUseCase) Revoke(ctx context.Context, revokedToken domain.RevokedToken) (err error) {
+	if err = tokenUseCase.revocationRepository.RevokeToken(ctx, revokedToken); err != nil {
+		return err
+	}
+
+	return
+}
+
+// RevokeRefreshToken ends the login the presented refresh token belongs to. Tokens that are
+// unknown or belong to someone else are ignored so logout never leaks which is which.
+func (tokenUseCase *tokenUseCase) RevokeRefreshToken(ctx context.Context, userID string, presented string) (err error) {
+	refreshToken := domain.RefreshToken{}
+
+	if err = tokenUseCase.refreshTokenRepository.GetByHash(ctx, &refreshToken, helpers.HashToken(presented)); err != nil || refreshToken.UserID != userID {
+		return nil
+	}
+
+	if err = tokenUseCase.refreshTokenRepository.RevokeFamily(ctx, refreshToken.FamilyID); err != nil {
+		return err
+	}
+
+	return
+}
+
+// RevokeSession ends one of the sessions of the user, together with every token it
+// handed out. Sessions of other users are reported as not found.
+func (tokenUseCase *tokenUseCase) RevokeSession(ctx context.Context, userID string, id string) (err error) {
+	session := domain.Session{}
+
+	if err = tokenUseCase.sessionRepository.GetByID(ctx, &session, id); err != nil {
+		return err
+	}
+
+	if session.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+
+	if err = tokenUseCase.revocationRepository.RevokeSession(ctx, session.ID); err != nil {
+		return err
+	}
+
+	if err = tokenUseCase.refreshTokenRepository.RevokeFamily(ctx, session.ID); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (tokenUseCase *tokenUseCase) RevokeAll(ctx context.Context, userID string) (err error) {
+	if err = tokenUseCase.revocationRepository.RevokeUser(ctx, userID, time.Now()); err != nil {
+		return err
+	}
+
+	if err = tokenUseCase.refreshTokenRepository.RevokeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err = tokenUseCase.sessionRepository.RevokeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return
+}
+
+// isNewDevice tells whether session comes from a device the user has not logged in
+// from before. The very first login of a user is not worth a notification.
+func (tokenUseCase *tokenUseCase) isNewDevice(ctx context.Context, session domain.Session) (bool, error) {
+	known, err := tokenUseCase.sessionRepository.HasDevice(ctx, session.UserID, session.DeviceHash)
+
+	if err != nil || known {
+		return false, err
+	}
+
+	return tokenUseCase.sessionRepository.HasAny(ctx, session.UserID)
+}
+
+func newRefreshToken(refreshToken *domain.RefreshToken) (string, error) {
+	raw, err := helpers.GenerateRandomToken(32)
+
+	if err != nil {
+		return "", err
+	}
+
+	expiresAt := time.Now().Add(helpers.RefreshTokenTTL())
+
+	refreshToken.TokenHash = helpers.HashToken(raw)
+	refreshToken.ExpiresAt = &expiresAt
+
+	return raw, nil
+}
+
+func signAccessToken(token domain.Token, user domain.User, sessionID string) (domain.Token, error) {
+	var err error
+
+	if token.AccessToken, err = helpers.GenerateToken(helpers.AccessClaims{
+		ID:            user.ID,
+		SessionID:     sessionID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		Role:          string(user.Role),
+	}); err != nil {
+		return token, err
+	}
+
+	token.ExpiresIn = int64(helpers.AccessTokenTTL().Seconds())
+
+	return token, nil
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+
+	return strings.ToValidUTF8(value[:length], "")
+}
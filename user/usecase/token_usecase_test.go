@@ -0,0 +1,334 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"mygram-api/domain"
+	"mygram-api/domain/mocks"
+	"mygram-api/helpers"
+	"testing"
+	"time"
+
+	userUseCase "mygram-api/user/usecase"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestIssueToken(t *testing.T) {
+	t.Setenv("APP_ENV", "development")
+
+	mockUser := domain.User{
+		ID:    "user-123",
+		Email: "johndoe@example.com",
+	}
+	mockClient := domain.Client{
+		UserAgent: "Mozilla/5.0 (X11; Linux x86_64) Firefox/118.0",
+		IP:        "203.0.113.7",
+	}
+	deviceHash := helpers.HashToken(mockClient.UserAgent)
+
+	mockRefreshTokenRepository := new(mocks.RefreshTokenRepository)
+	mockRevocationRepository := new(mocks.RevocationRepository)
+	mockSessionRepository := new(mocks.SessionRepository)
+	mockLoginNotifier := new(mocks.LoginNotifier)
+	tokenUseCase := userUseCase.NewTokenUseCase(mockRefreshTokenRepository, mockRevocationRepository, mockSessionRepository, mockLoginNotifier)
+
+	storeSession := func(args mock.Arguments) {
+		args.Get(1).(*domain.Session).ID = "session-123"
+	}
+
+	t.Run("issue token correctly", func(t *testing.T) {
+		var (
+			stored  *domain.RefreshToken
+			session *domain.Session
+		)
+
+		mockSessionRepository.On("HasDevice", mock.Anything, mockUser.ID, deviceHash).Return(true, nil).Once()
+		mockSessionRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.Session")).Run(func(args mock.Arguments) {
+			storeSession(args)
+			session = args.Get(1).(*domain.Session)
+		}).Return(nil).Once()
+		mockRefreshTokenRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.RefreshToken)
+		}).Return(nil).Once()
+
+		token, err := tokenUseCase.Issue(context.Background(), mockUser, mockClient)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token.AccessToken)
+		assert.NotEmpty(t, token.RefreshToken)
+		assert.Equal(t, int64(helpers.AccessTokenTTL().Seconds()), token.ExpiresIn)
+		assert.Equal(t, mockUser.ID, stored.UserID)
+		assert.Equal(t, "session-123", stored.FamilyID)
+		assert.Equal(t, helpers.HashToken(token.RefreshToken), stored.TokenHash)
+		assert.NotEqual(t, token.RefreshToken, stored.TokenHash)
+		assert.Equal(t, mockClient.UserAgent, session.UserAgent)
+		assert.Equal(t, mockClient.IP, session.IP)
+
+		claims := jwt.MapClaims{}
+		_, _, err = new(jwt.Parser).ParseUnverified(token.AccessToken, claims)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "session-123", claims["sid"])
+		mockSessionRepository.AssertExpectations(t)
+		mockRefreshTokenRepository.AssertExpectations(t)
+		mockLoginNotifier.AssertNotCalled(t, "NewDevice", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("issue token from a new device notifies the user", func(t *testing.T) {
+		mockSessionRepository.On("HasDevice", mock.Anything, mockUser.ID, deviceHash).Return(false, nil).Once()
+		mockSessionRepository.On("HasAny", mock.Anything, mockUser.ID).Return(true, nil).Once()
+		mockSessionRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.Session")).Run(storeSession).Return(nil).Once()
+		mockRefreshTokenRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Once()
+		mockLoginNotifier.On("NewDevice", mock.Anything, mockUser, mock.AnythingOfType("domain.Session")).Return(errors.New("fail")).Once()
+
+		token, err := tokenUseCase.Issue(context.Background(), mockUser, mockClient)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token.AccessToken)
+		mockSessionRepository.AssertExpectations(t)
+		mockLoginNotifier.AssertExpectations(t)
+	})
+
+	t.Run("issue token for the first login does not notify", func(t *testing.T) {
+		mockLoginNotifier := new(mocks.LoginNotifier)
+		tokenUseCase := userUseCase.NewTokenUseCase(mockRefreshTokenRepository, mockRevocationRepository, mockSessionRepository, mockLoginNotifier)
+
+		mockSessionRepository.On("HasDevice", mock.Anything, mockUser.ID, deviceHash).Return(false, nil).Once()
+		mockSessionRepository.On("HasAny", mock.Anything, mockUser.ID).Return(false, nil).Once()
+		mockSessionRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.Session")).Run(storeSession).Return(nil).Once()
+		mockRefreshTokenRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Once()
+
+		_, err := tokenUseCase.Issue(context.Background(), mockUser, mockClient)
+
+		assert.NoError(t, err)
+		mockSessionRepository.AssertExpectations(t)
+		mockLoginNotifier.AssertNotCalled(t, "NewDevice", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("issue token with failing repository", func(t *testing.T) {
+		mockSessionRepository.On("HasDevice", mock.Anything, mockUser.ID, deviceHash).Return(true, nil).Once()
+		mockSessionRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.Session")).Run(storeSession).Return(nil).Once()
+		mockRefreshTokenRepository.On("Store", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(errors.New("fail")).Once()
+
+		token, err := tokenUseCase.Issue(context.Background(), mockUser, mockClient)
+
+		assert.Error(t, err)
+		assert.Empty(t, token.AccessToken)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+}
+
+func TestRefreshToken(t *testing.T) {
+	t.Setenv("APP_ENV", "development")
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	mockUser := domain.User{
+		ID:    "user-123",
+		Email: "johndoe@example.com",
+	}
+
+	mockRefreshTokenRepository := new(mocks.RefreshTokenRepository)
+	mockRevocationRepository := new(mocks.RevocationRepository)
+	mockSessionRepository := new(mocks.SessionRepository)
+	tokenUseCase := userUseCase.NewTokenUseCase(mockRefreshTokenRepository, mockRevocationRepository, mockSessionRepository, new(mocks.LoginNotifier))
+
+	withToken := func(refreshToken domain.RefreshToken) func(args mock.Arguments) {
+		return func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = refreshToken
+		}
+	}
+
+	t.Run("refresh token correctly", func(t *testing.T) {
+		current := domain.RefreshToken{
+			ID:        "refreshtoken-123",
+			UserID:    mockUser.ID,
+			FamilyID:  "refreshtoken-123",
+			ExpiresAt: &future,
+			User:      &mockUser,
+		}
+
+		mockRefreshTokenRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), helpers.HashToken("presented")).Run(withToken(current)).Return(nil).Once()
+		mockRefreshTokenRepository.On("Rotate", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Once()
+		mockSessionRepository.On("Touch", mock.Anything, "refreshtoken-123", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		token, err := tokenUseCase.Refresh(context.Background(), "presented")
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token.AccessToken)
+		assert.NotEmpty(t, token.RefreshToken)
+		assert.NotEqual(t, "presented", token.RefreshToken)
+		mockRefreshTokenRepository.AssertExpectations(t)
+		mockSessionRepository.AssertExpectations(t)
+	})
+
+	t.Run("refresh token with unknown token", func(t *testing.T) {
+		mockRefreshTokenRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Return(errors.New("record not found")).Once()
+
+		_, err := tokenUseCase.Refresh(context.Background(), "unknown")
+
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenInvalid)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("refresh token with expired token", func(t *testing.T) {
+		current := domain.RefreshToken{
+			ID:        "refreshtoken-123",
+			UserID:    mockUser.ID,
+			FamilyID:  "refreshtoken-123",
+			ExpiresAt: &past,
+			User:      &mockUser,
+		}
+
+		mockRefreshTokenRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Run(withToken(current)).Return(nil).Once()
+
+		_, err := tokenUseCase.Refresh(context.Background(), "presented")
+
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenExpired)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("refresh token with already rotated token revokes the family", func(t *testing.T) {
+		current := domain.RefreshToken{
+			ID:        "refreshtoken-123",
+			UserID:    mockUser.ID,
+			FamilyID:  "refreshtoken-family",
+			ExpiresAt: &future,
+			RevokedAt: &past,
+			User:      &mockUser,
+		}
+
+		mockRefreshTokenRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Run(withToken(current)).Return(nil).Once()
+		mockRefreshTokenRepository.On("RevokeFamily", mock.Anything, "refreshtoken-family").Return(nil).Once()
+
+		_, err := tokenUseCase.Refresh(context.Background(), "presented")
+
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("refresh token losing a concurrent rotation revokes the family", func(t *testing.T) {
+		current := domain.RefreshToken{
+			ID:        "refreshtoken-123",
+			UserID:    mockUser.ID,
+			FamilyID:  "refreshtoken-family",
+			ExpiresAt: &future,
+			User:      &mockUser,
+		}
+
+		mockRefreshTokenRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Run(withToken(current)).Return(nil).Once()
+		mockRefreshTokenRepository.On("Rotate", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("*domain.RefreshToken")).Return(domain.ErrRefreshTokenReused).Once()
+		mockRefreshTokenRepository.On("RevokeFamily", mock.Anything, "refreshtoken-family").Return(nil).Once()
+
+		_, err := tokenUseCase.Refresh(context.Background(), "presented")
+
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+}
+
+func TestRevokeToken(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	mockRevokedToken := domain.RevokedToken{
+		JTI:       "jti-123",
+		UserID:    "user-123",
+		ExpiresAt: &expiresAt,
+	}
+
+	mockRefreshTokenRepository := new(mocks.RefreshTokenRepository)
+	mockRevocationRepository := new(mocks.RevocationRepository)
+	mockSessionRepository := new(mocks.SessionRepository)
+	tokenUseCase := userUseCase.NewTokenUseCase(mockRefreshTokenRepository, mockRevocationRepository, mockSessionRepository, new(mocks.LoginNotifier))
+
+	t.Run("revoke token correctly", func(t *testing.T) {
+		mockRevocationRepository.On("RevokeToken", mock.Anything, mockRevokedToken).Return(nil).Once()
+
+		err := tokenUseCase.Revoke(context.Background(), mockRevokedToken)
+
+		assert.NoError(t, err)
+		mockRevocationRepository.AssertExpectations(t)
+	})
+
+	t.Run("revoke refresh token of the same user", func(t *testing.T) {
+		mockRefreshTokenRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), helpers.HashToken("presented")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = domain.RefreshToken{UserID: "user-123", FamilyID: "refreshtoken-family"}
+		}).Return(nil).Once()
+		mockRefreshTokenRepository.On("RevokeFamily", mock.Anything, "refreshtoken-family").Return(nil).Once()
+
+		err := tokenUseCase.RevokeRefreshToken(context.Background(), "user-123", "presented")
+
+		assert.NoError(t, err)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("revoke refresh token of another user", func(t *testing.T) {
+		mockRefreshTokenRepository := new(mocks.RefreshTokenRepository)
+		tokenUseCase := userUseCase.NewTokenUseCase(mockRefreshTokenRepository, mockRevocationRepository, mockSessionRepository, new(mocks.LoginNotifier))
+
+		mockRefreshTokenRepository.On("GetByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), helpers.HashToken("presented")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = domain.RefreshToken{UserID: "user-234", FamilyID: "refreshtoken-family"}
+		}).Return(nil).Once()
+
+		err := tokenUseCase.RevokeRefreshToken(context.Background(), "user-123", "presented")
+
+		assert.NoError(t, err)
+		mockRefreshTokenRepository.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("revoke all tokens of a user", func(t *testing.T) {
+		mockRevocationRepository.On("RevokeUser", mock.Anything, "user-123", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		mockRefreshTokenRepository.On("RevokeUser", mock.Anything, "user-123").Return(nil).Once()
+		mockSessionRepository.On("RevokeUser", mock.Anything, "user-123").Return(nil).Once()
+
+		err := tokenUseCase.RevokeAll(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+		mockRevocationRepository.AssertExpectations(t)
+		mockRefreshTokenRepository.AssertExpectations(t)
+		mockSessionRepository.AssertExpectations(t)
+	})
+
+	t.Run("revoke a session of the same user", func(t *testing.T) {
+		mockSessionRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Session"), "session-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Session) = domain.Session{ID: "session-123", UserID: "user-123"}
+		}).Return(nil).Once()
+		mockRevocationRepository.On("RevokeSession", mock.Anything, "session-123").Return(nil).Once()
+		mockRefreshTokenRepository.On("RevokeFamily", mock.Anything, "session-123").Return(nil).Once()
+
+		err := tokenUseCase.RevokeSession(context.Background(), "user-123", "session-123")
+
+		assert.NoError(t, err)
+		mockSessionRepository.AssertExpectations(t)
+		mockRevocationRepository.AssertExpectations(t)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("revoke a session of another user", func(t *testing.T) {
+		mockRevocationRepository := new(mocks.RevocationRepository)
+		tokenUseCase := userUseCase.NewTokenUseCase(mockRefreshTokenRepository, mockRevocationRepository, mockSessionRepository, new(mocks.LoginNotifier))
+
+		mockSessionRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.Session"), "session-234").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Session) = domain.Session{ID: "session-234", UserID: "user-234"}
+		}).Return(nil).Once()
+
+		err := tokenUseCase.RevokeSession(context.Background(), "user-123", "session-234")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockRevocationRepository.AssertNotCalled(t, "RevokeSession", mock.Anything, mock.Anything)
+		mockSessionRepository.AssertExpectations(t)
+	})
+
+	t.Run("revoke all tokens with failing revocation store", func(t *testing.T) {
+		mockRevocationRepository.On("RevokeUser", mock.Anything, "user-123", mock.AnythingOfType("time.Time")).Return(errors.New("fail")).Once()
+
+		err := tokenUseCase.RevokeAll(context.Background(), "user-123")
+
+		assert.Error(t, err)
+		mockRevocationRepository.AssertExpectations(t)
+	})
+}
@@ -3,17 +3,32 @@ package usecase
 import (
 	"context"
 	"mygram-api/domain"
+	"mygram-api/helpers"
 )
 
 type userUseCase struct {
 	userRepository domain.UserRepository
+	tokenUseCase   domain.TokenUseCase
+	avatarUseCase  domain.AvatarUseCase
 }
 
-func NewUserUseCase(userRepository domain.UserRepository) *userUseCase {
-	return &userUseCase{userRepository}
+func NewUserUseCase(userRepository domain.UserRepository, tokenUseCase domain.TokenUseCase, avatarUseCase domain.AvatarUseCase) *userUseCase {
+	return &userUseCase{userRepository, tokenUseCase, avatarUseCase}
 }
 
 func (userUseCase *userUseCase) Register(ctx context.Context, user *domain.User) (err error) {
+	if user.Age == 0 {
+		return domain.ErrAgeRequired
+	}
+
+	if domain.IsReservedUsername(user.Username) {
+		return domain.ErrUsernameReserved
+	}
+
+	if len(user.Password) > helpers.PasswordMaxBytes {
+		return helpers.ErrPasswordTooLong
+	}
+
 	if err = userUseCase.userRepository.Register(ctx, user); err != nil {
 		return err
 	}
@@ -29,18 +44,111 @@ func (userUseCase *userUseCase) Login(ctx context.Context, user *domain.User) (e
 	return
 }
 
-func (userUseCase *userUseCase) Update(ctx context.Context, user domain.User) (u domain.User, err error) {
-	if u, err = userUseCase.userRepository.Update(ctx, user); err != nil {
+func (userUseCase *userUseCase) GetByID(ctx context.Context, user *domain.User, id string) (err error) {
+	if err = userUseCase.userRepository.GetByID(ctx, user, id); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userUseCase *userUseCase) Fetch(ctx context.Context, users *[]domain.User) (err error) {
+	if err = userUseCase.userRepository.Fetch(ctx, users); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userUseCase *userUseCase) Update(ctx context.Context, id string, userUpdate domain.UserUpdate) (u domain.User, err error) {
+	if err = userUpdate.Validate(); err != nil {
+		return u, err
+	}
+
+	if u, err = userUseCase.userRepository.Update(ctx, id, userUpdate); err != nil {
 		return u, err
 	}
 
 	return u, nil
 }
 
+func (userUseCase *userUseCase) GetProfileByID(ctx context.Context, profile *domain.Profile, id string) (err error) {
+	if err = userUseCase.userRepository.GetProfileByID(ctx, profile, id); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userUseCase *userUseCase) GetProfileByUsername(ctx context.Context, profile *domain.Profile, username string) (err error) {
+	if err = userUseCase.userRepository.GetProfileByUsername(ctx, profile, username); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userUseCase *userUseCase) ChangePassword(ctx context.Context, id string, currentPassword string, newPassword string) (err error) {
+	user := domain.User{}
+
+	if err = userUseCase.userRepository.GetByID(ctx, &user, id); err != nil {
+		return err
+	}
+
+	if isValid := helpers.Compare([]byte(user.Password), []byte(currentPassword)); !isValid {
+		return domain.ErrPasswordIncorrect
+	}
+
+	if err = domain.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+
+	hash, err := helpers.Hash(newPassword)
+
+	if err != nil {
+		return err
+	}
+
+	if err = userUseCase.userRepository.UpdatePassword(ctx, id, hash); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userUseCase *userUseCase) ChangeRole(ctx context.Context, id string, role domain.Role) (err error) {
+	if !role.IsValid() {
+		return domain.ErrRoleInvalid
+	}
+
+	if err = userUseCase.userRepository.UpdateRole(ctx, id, role); err != nil {
+		return err
+	}
+
+	return
+}
+
+// Delete removes the user with id and revokes every token they still hold. The
+// files of their avatar only go once the account is gone, a refused or failed
+// delete leaves the avatar in place.
 func (userUseCase *userUseCase) Delete(ctx context.Context, id string) (err error) {
+	user := domain.User{}
+
+	if err = userUseCase.userRepository.GetByID(ctx, &user, id); err != nil {
+		return err
+	}
+
 	if err = userUseCase.userRepository.Delete(ctx, id); err != nil {
 		return err
 	}
 
+	if user.AvatarKey != "" {
+		userUseCase.avatarUseCase.RemoveFiles(ctx, user.AvatarKey)
+	}
+
+	if err = userUseCase.tokenUseCase.RevokeAll(ctx, id); err != nil {
+		return err
+	}
+
 	return
 }
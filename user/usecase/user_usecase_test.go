@@ -6,6 +6,7 @@ import (
 	"mygram-api/domain"
 	"mygram-api/domain/mocks"
 	"mygram-api/helpers"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/asaskevich/govalidator"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func TestRegister(t *testing.T) {
@@ -26,7 +28,7 @@ func TestRegister(t *testing.T) {
 	}
 
 	mockUserRepository := new(mocks.UserRepository)
-	userUseCase := userUseCase.NewUserUseCase(mockUserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.TokenUseCase), new(mocks.AvatarUseCase))
 
 	t.Run("register user correctly", func(t *testing.T) {
 		tempMockRegisterUser := domain.User{
@@ -242,19 +244,55 @@ func TestRegister(t *testing.T) {
 
 		mockUserRepository.AssertExpectations(t)
 	})
+
+	t.Run("register user without age", func(t *testing.T) {
+		err := userUseCase.Register(context.Background(), &domain.User{
+			Email:    "johndoe@example.com",
+			Password: "secret",
+			Username: "johndoe",
+		})
+
+		assert.ErrorIs(t, err, domain.ErrAgeRequired)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("register user with a reserved username", func(t *testing.T) {
+		err := userUseCase.Register(context.Background(), &domain.User{
+			Age:      8,
+			Email:    "johndoe@example.com",
+			Password: "secret",
+			Username: "Me",
+		})
+
+		assert.ErrorIs(t, err, domain.ErrUsernameReserved)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("register user with a password over the length limit", func(t *testing.T) {
+		err := userUseCase.Register(context.Background(), &domain.User{
+			Age:      8,
+			Email:    "johndoe@example.com",
+			Password: strings.Repeat("a", helpers.PasswordMaxBytes+1),
+			Username: "johndoe",
+		})
+
+		assert.ErrorIs(t, err, helpers.ErrPasswordTooLong)
+		mockUserRepository.AssertExpectations(t)
+	})
 }
 
 func TestLogin(t *testing.T) {
+	hashedPassword, _ := helpers.Hash("secret")
 	mockRegisteredUser := domain.User{
 		ID:       "user-123",
 		Age:      8,
 		Email:    "johndoe@example.com",
-		Password: helpers.Hash("secret"),
+		Password: hashedPassword,
 		Username: "johndoe",
 	}
 
 	mockUserRepository := new(mocks.UserRepository)
-	userUseCase := userUseCase.NewUserUseCase(mockUserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.TokenUseCase), new(mocks.AvatarUseCase))
 
 	t.Run("login user correctly", func(t *testing.T) {
 		tempMockLoginUser := domain.User{
@@ -357,202 +395,367 @@ func TestLogin(t *testing.T) {
 	})
 }
 
+func TestGetProfile(t *testing.T) {
+	mockProfile := domain.Profile{
+		User: domain.User{
+			ID:       "user-123",
+			Username: "johndoe",
+			Bio:      "Sunsets and street food.",
+		},
+		PhotoCount: 2,
+		SocialMedias: []domain.SocialMedia{
+			{ID: "socialmedia-123", Name: "Instagram", SocialMediaUrl: "https://www.instagram.com/johndoe", UserID: "user-123"},
+		},
+	}
+
+	mockUserRepository := new(mocks.UserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.TokenUseCase), new(mocks.AvatarUseCase))
+
+	t.Run("get profile by username correctly", func(t *testing.T) {
+		mockUserRepository.On("GetProfileByUsername", mock.Anything, mock.AnythingOfType("*domain.Profile"), "johndoe").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Profile) = mockProfile
+		}).Return(nil).Once()
+
+		profile := domain.Profile{}
+
+		err := userUseCase.GetProfileByUsername(context.Background(), &profile, "johndoe")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockProfile, profile)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("get profile by unknown username", func(t *testing.T) {
+		mockUserRepository.On("GetProfileByUsername", mock.Anything, mock.AnythingOfType("*domain.Profile"), "janedoe").Return(gorm.ErrRecordNotFound).Once()
+
+		profile := domain.Profile{}
+
+		err := userUseCase.GetProfileByUsername(context.Background(), &profile, "janedoe")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("get profile by id correctly", func(t *testing.T) {
+		mockUserRepository.On("GetProfileByID", mock.Anything, mock.AnythingOfType("*domain.Profile"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Profile) = mockProfile
+		}).Return(nil).Once()
+
+		profile := domain.Profile{}
+
+		err := userUseCase.GetProfileByID(context.Background(), &profile, "user-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), profile.PhotoCount)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
 func TestUpdate(t *testing.T) {
 	now := time.Now()
+	username := "newjohndoe"
+	bio := "Sunsets and street food."
+	age := uint(20)
 	mockUpdatedUser := domain.User{
 		ID:        "user-123",
-		Email:     "newjohndoe@example.com",
-		Username:  "newjohndoe",
-		Age:       8,
-		Password:  "secret",
+		Email:     "johndoe@example.com",
+		Username:  username,
+		Bio:       bio,
+		Age:       age,
 		UpdatedAt: &now,
 	}
 
 	mockUserRepository := new(mocks.UserRepository)
-	userUseCase := userUseCase.NewUserUseCase(mockUserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.TokenUseCase), new(mocks.AvatarUseCase))
+
+	t.Run("update user with invalid fields", func(t *testing.T) {
+		emptyUsername := ""
+		longUsername := strings.Repeat("a", 51)
+		tooYoung := uint(7)
+		longBio := strings.Repeat("a", 301)
+		imageUrl := "not a url"
+		email := "newjohndoe"
+		reservedUsername := "sessions"
+
+		for _, tc := range []struct {
+			userUpdate domain.UserUpdate
+			err        error
+		}{
+			{domain.UserUpdate{Username: &emptyUsername}, domain.ErrUsernameRequired},
+			{domain.UserUpdate{Username: &longUsername}, domain.ErrUsernameTooLong},
+			{domain.UserUpdate{Username: &reservedUsername}, domain.ErrUsernameReserved},
+			{domain.UserUpdate{Age: &tooYoung}, domain.ErrAgeInvalid},
+			{domain.UserUpdate{Bio: &longBio}, domain.ErrBioTooLong},
+			{domain.UserUpdate{ProfileImageUrl: &imageUrl}, domain.ErrProfileImageUrlInvalid},
+			{domain.UserUpdate{PendingEmail: &email}, domain.ErrEmailInvalid},
+		} {
+			_, err := userUseCase.Update(context.Background(), "user-123", tc.userUpdate)
+
+			assert.ErrorIs(t, err, tc.err)
+		}
+
+		mockUserRepository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
 
 	t.Run("update user correctly", func(t *testing.T) {
-		tempMockUpdateUser := domain.User{
-			Email:    "newjohndoe@example.com",
-			Username: "newjohndoe",
-		}
+		userUpdate := domain.UserUpdate{Username: &username, Bio: &bio, Age: &age}
 
-		mockUserRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.User")).Return(mockUpdatedUser, nil).Once()
+		mockUserRepository.On("Update", mock.Anything, "user-123", userUpdate).Return(mockUpdatedUser, nil).Once()
 
-		user, err := userUseCase.Update(context.Background(), tempMockUpdateUser)
+		user, err := userUseCase.Update(context.Background(), "user-123", userUpdate)
 
 		assert.NoError(t, err)
+		assert.Equal(t, mockUpdatedUser, user)
+		mockUserRepository.AssertExpectations(t)
+	})
 
-		tempMockUpdatedUser := domain.User{
-			ID:        "user-123",
-			Email:     tempMockUpdateUser.Email,
-			Username:  tempMockUpdateUser.Username,
-			Age:       8,
-			Password:  "secret",
-			UpdatedAt: &now,
-		}
+	t.Run("update user with only some fields", func(t *testing.T) {
+		userUpdate := domain.UserUpdate{Bio: &bio}
+
+		mockUserRepository.On("Update", mock.Anything, "user-123", userUpdate).Return(mockUpdatedUser, nil).Once()
 
-		_, err = govalidator.ValidateStruct(tempMockUpdatedUser)
+		_, err := userUseCase.Update(context.Background(), "user-123", userUpdate)
 
 		assert.NoError(t, err)
-		assert.Equal(t, user, tempMockUpdatedUser)
-		assert.Equal(t, mockUpdatedUser.Email, tempMockUpdateUser.Email)
-		assert.Equal(t, mockUpdatedUser.Username, tempMockUpdatedUser.Username)
 		mockUserRepository.AssertExpectations(t)
 	})
 
-	t.Run("update user with empty email", func(t *testing.T) {
-		tempMockUpdateUser := domain.User{
-			Email:    "",
-			Username: "newjohndoe",
-		}
+	t.Run("update user with a new email", func(t *testing.T) {
+		email := "newjohndoe@example.com"
+		userUpdate := domain.UserUpdate{Username: &username, PendingEmail: &email}
+		pendingUser := mockUpdatedUser
 
-		mockUserRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.User")).Return(mockUpdatedUser, nil).Once()
+		pendingUser.PendingEmail = email
 
-		user, err := userUseCase.Update(context.Background(), tempMockUpdateUser)
+		mockUserRepository.On("Update", mock.Anything, "user-123", userUpdate).Return(pendingUser, nil).Once()
+
+		user, err := userUseCase.Update(context.Background(), "user-123", userUpdate)
 
 		assert.NoError(t, err)
+		assert.Equal(t, email, user.PendingEmail)
+		mockUserRepository.AssertExpectations(t)
+	})
 
-		tempMockUpdatedUser := domain.User{
-			ID:        "user-123",
-			Email:     tempMockUpdateUser.Email,
-			Username:  tempMockUpdateUser.Username,
-			Age:       8,
-			Password:  "secret",
-			UpdatedAt: &now,
-		}
+	t.Run("update user with a taken username", func(t *testing.T) {
+		userUpdate := domain.UserUpdate{Username: &username}
 
-		_, err = govalidator.ValidateStruct(tempMockUpdatedUser)
+		mockUserRepository.On("Update", mock.Anything, "user-123", userUpdate).Return(domain.User{}, errors.New(`duplicate key value violates unique constraint "idx_users_username"`)).Once()
 
-		assert.Error(t, err)
-		assert.NotEqual(t, user, tempMockUpdatedUser)
-		assert.NotEqual(t, mockUpdatedUser.Email, tempMockUpdatedUser.Email)
-		assert.Equal(t, mockUpdatedUser.Username, tempMockUpdateUser.Username)
+		_, err := userUseCase.Update(context.Background(), "user-123", userUpdate)
+
+		assert.ErrorContains(t, err, "idx_users_username")
 		mockUserRepository.AssertExpectations(t)
 	})
+}
 
-	t.Run("update user with empty username", func(t *testing.T) {
-		tempMockUpdateUser := domain.User{
-			Email:    "newjohndoe@example.com",
-			Username: "",
-		}
+func TestChangePassword(t *testing.T) {
+	hashedPassword, _ := helpers.NewBcryptHasher(4).Hash("secret")
+	mockUser := domain.User{
+		ID:       "user-123",
+		Email:    "johndoe@example.com",
+		Password: hashedPassword,
+		Username: "johndoe",
+	}
 
-		mockUserRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.User")).Return(mockUpdatedUser, nil).Once()
+	withUser := func(args mock.Arguments) {
+		*args.Get(1).(*domain.User) = mockUser
+	}
 
-		user, err := userUseCase.Update(context.Background(), tempMockUpdateUser)
+	t.Run("change password correctly", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.TokenUseCase), new(mocks.AvatarUseCase))
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+		mockUserRepository.On("UpdatePassword", mock.Anything, mockUser.ID, mock.MatchedBy(func(hash string) bool {
+			return helpers.Compare([]byte(hash), []byte("newsecret"))
+		})).Return(nil).Once()
+
+		err := userUseCase.ChangePassword(context.Background(), mockUser.ID, "secret", "newsecret")
 
 		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
 
-		tempMockUpdatedUser := domain.User{
-			ID:        "user-123",
-			Email:     tempMockUpdateUser.Email,
-			Username:  tempMockUpdateUser.Username,
-			Age:       8,
-			Password:  "secret",
-			UpdatedAt: &now,
+	t.Run("change password hashes with the configured algorithm", func(t *testing.T) {
+		for algorithm, prefix := range map[string]string{"argon2id": "$argon2id$v=19$", "bcrypt": "$2a$"} {
+			t.Setenv("PASSWORD_HASH_ALGORITHM", algorithm)
+
+			mockUserRepository := new(mocks.UserRepository)
+			userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.TokenUseCase), new(mocks.AvatarUseCase))
+
+			mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+			mockUserRepository.On("UpdatePassword", mock.Anything, mockUser.ID, mock.MatchedBy(func(hash string) bool {
+				return strings.HasPrefix(hash, prefix) && helpers.Compare([]byte(hash), []byte("newsecret")) && !helpers.NeedsRehash([]byte(hash))
+			})).Return(nil).Once()
+
+			err := userUseCase.ChangePassword(context.Background(), mockUser.ID, "secret", "newsecret")
+
+			assert.NoError(t, err)
+			mockUserRepository.AssertExpectations(t)
 		}
+	})
 
-		_, err = govalidator.ValidateStruct(tempMockUpdatedUser)
+	t.Run("change password over bcrypt's length limit", func(t *testing.T) {
+		t.Setenv("PASSWORD_HASH_ALGORITHM", "bcrypt")
 
-		assert.Error(t, err)
-		assert.NotEqual(t, user, tempMockUpdatedUser)
-		assert.Equal(t, mockUpdatedUser.Email, tempMockUpdatedUser.Email)
-		assert.NotEqual(t, mockUpdatedUser.Username, tempMockUpdatedUser.Username)
+		mockUserRepository := new(mocks.UserRepository)
+		userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.TokenUseCase), new(mocks.AvatarUseCase))
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+
+		err := userUseCase.ChangePassword(context.Background(), mockUser.ID, "secret", strings.Repeat("a", 73))
+
+		assert.ErrorIs(t, err, helpers.ErrPasswordTooLong)
+		mockUserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
 		mockUserRepository.AssertExpectations(t)
 	})
 
-	t.Run("update user with empty username and email", func(t *testing.T) {
-		tempMockUpdateUser := domain.User{
-			Email:    "",
-			Username: "",
-		}
+	t.Run("change password over the length limit with argon2id", func(t *testing.T) {
+		t.Setenv("PASSWORD_HASH_ALGORITHM", "argon2id")
 
-		mockUserRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.User")).Return(mockUpdatedUser, nil).Once()
+		mockUserRepository := new(mocks.UserRepository)
+		userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.TokenUseCase), new(mocks.AvatarUseCase))
 
-		user, err := userUseCase.Update(context.Background(), tempMockUpdateUser)
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
 
-		assert.NoError(t, err)
+		err := userUseCase.ChangePassword(context.Background(), mockUser.ID, "secret", strings.Repeat("a", 1<<20))
 
-		tempMockUpdatedUser := domain.User{
-			ID:        "user-123",
-			Email:     tempMockUpdateUser.Email,
-			Username:  tempMockUpdateUser.Username,
-			Age:       8,
-			Password:  "secret",
-			UpdatedAt: &now,
-		}
+		assert.ErrorIs(t, err, helpers.ErrPasswordTooLong)
+		mockUserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+		mockUserRepository.AssertExpectations(t)
+	})
 
-		assert.NoError(t, err)
+	t.Run("change password with incorrect current password", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.TokenUseCase), new(mocks.AvatarUseCase))
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+
+		err := userUseCase.ChangePassword(context.Background(), mockUser.ID, "wrong", "newsecret")
+
+		assert.ErrorIs(t, err, domain.ErrPasswordIncorrect)
+		mockUserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("change password with password under limit character", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.TokenUseCase), new(mocks.AvatarUseCase))
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Run(withUser).Return(nil).Once()
+
+		err := userUseCase.ChangePassword(context.Background(), mockUser.ID, "secret", "short")
 
-		_, err = govalidator.ValidateStruct(tempMockUpdatedUser)
+		assert.ErrorIs(t, err, domain.ErrPasswordTooShort)
+		mockUserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("change password with not found user", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.TokenUseCase), new(mocks.AvatarUseCase))
+
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-234").Return(errors.New("fail")).Once()
+
+		err := userUseCase.ChangePassword(context.Background(), "user-234", "secret", "newsecret")
 
 		assert.Error(t, err)
-		assert.NotEqual(t, user, tempMockUpdatedUser)
-		assert.NotEqual(t, mockUpdatedUser.Email, tempMockUpdatedUser.Email)
-		assert.NotEqual(t, mockUpdatedUser.Username, tempMockUpdatedUser.Username)
 		mockUserRepository.AssertExpectations(t)
 	})
+}
 
-	t.Run("update user with invalid email format", func(t *testing.T) {
-		tempMockUpdateUser := domain.User{
-			Email:    "newjohndoe",
-			Username: "newjohndoe",
-		}
+func TestChangeRole(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, new(mocks.TokenUseCase), new(mocks.AvatarUseCase))
 
-		mockUserRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.User")).Return(mockUpdatedUser, nil).Once()
+	t.Run("change role correctly", func(t *testing.T) {
+		mockUserRepository.On("UpdateRole", mock.Anything, "user-123", domain.RoleModerator).Return(nil).Once()
 
-		user, err := userUseCase.Update(context.Background(), tempMockUpdateUser)
+		err := userUseCase.ChangeRole(context.Background(), "user-123", domain.RoleModerator)
 
 		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
 
-		tempMockUpdatedUser := domain.User{
-			ID:        "user-123",
-			Email:     tempMockUpdateUser.Email,
-			Username:  tempMockUpdateUser.Username,
-			Age:       8,
-			Password:  "secret",
-			UpdatedAt: &now,
-		}
+	t.Run("change role to an unknown role", func(t *testing.T) {
+		err := userUseCase.ChangeRole(context.Background(), "user-123", domain.Role("superuser"))
 
-		assert.NoError(t, err)
+		assert.ErrorIs(t, err, domain.ErrRoleInvalid)
+		mockUserRepository.AssertNumberOfCalls(t, "UpdateRole", 1)
+	})
 
-		_, err = govalidator.ValidateStruct(tempMockUpdatedUser)
+	t.Run("change role with not found user", func(t *testing.T) {
+		mockUserRepository.On("UpdateRole", mock.Anything, "user-234", domain.RoleAdmin).Return(errors.New("fail")).Once()
+
+		err := userUseCase.ChangeRole(context.Background(), "user-234", domain.RoleAdmin)
 
 		assert.Error(t, err)
-		assert.NotEqual(t, user, tempMockUpdatedUser)
-		assert.NotEqual(t, mockUpdatedUser.Email, tempMockUpdatedUser.Email)
-		assert.Equal(t, mockUpdatedUser.Username, tempMockUpdatedUser.Username)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("change role of the last admin", func(t *testing.T) {
+		mockUserRepository.On("UpdateRole", mock.Anything, "user-345", domain.RoleUser).Return(domain.ErrLastAdmin).Once()
+
+		err := userUseCase.ChangeRole(context.Background(), "user-345", domain.RoleUser)
+
+		assert.ErrorIs(t, err, domain.ErrLastAdmin)
 		mockUserRepository.AssertExpectations(t)
 	})
 }
 
 func TestDelete(t *testing.T) {
 	mockUser := domain.User{
-		ID:       "user-123",
-		Age:      8,
-		Email:    "johndoe@example.com",
-		Password: "secret",
-		Username: "johndoe",
+		ID:        "user-123",
+		Age:       8,
+		Email:     "johndoe@example.com",
+		Password:  "secret",
+		Username:  "johndoe",
+		AvatarKey: "avatars/user-123/avatar",
 	}
 
 	mockUserRepository := new(mocks.UserRepository)
-	userUseCase := userUseCase.NewUserUseCase(mockUserRepository)
+	mockTokenUseCase := new(mocks.TokenUseCase)
+	mockAvatarUseCase := new(mocks.AvatarUseCase)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository, mockTokenUseCase, mockAvatarUseCase)
 
 	t.Run("delete user correctly", func(t *testing.T) {
-		mockUserRepository.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Return(nil).Run(func(args mock.Arguments) {
+			arg := args.Get(1).(*domain.User)
+			*arg = mockUser
+		}).Once()
+		mockUserRepository.On("Delete", mock.Anything, mockUser.ID).Return(nil).Once()
+		mockAvatarUseCase.On("RemoveFiles", mock.Anything, mockUser.AvatarKey).Once()
+		mockTokenUseCase.On("RevokeAll", mock.Anything, mockUser.ID).Return(nil).Once()
 
 		err := userUseCase.Delete(context.Background(), mockUser.ID)
 
 		assert.NoError(t, err)
 		mockUserRepository.AssertExpectations(t)
+		mockAvatarUseCase.AssertExpectations(t)
+		mockTokenUseCase.AssertExpectations(t)
 	})
 
 	t.Run("delete user with not found user", func(t *testing.T) {
-		mockUserRepository.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(errors.New("fail")).Once()
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), "user-234").Return(gorm.ErrRecordNotFound).Once()
 
 		err := userUseCase.Delete(context.Background(), "user-234")
 
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("delete the last admin keeps the avatar", func(t *testing.T) {
+		mockUserRepository.On("GetByID", mock.Anything, mock.AnythingOfType("*domain.User"), mockUser.ID).Return(nil).Run(func(args mock.Arguments) {
+			arg := args.Get(1).(*domain.User)
+			*arg = mockUser
+		}).Once()
+		mockUserRepository.On("Delete", mock.Anything, mockUser.ID).Return(domain.ErrLastAdmin).Once()
+
+		err := userUseCase.Delete(context.Background(), mockUser.ID)
+
+		assert.ErrorIs(t, err, domain.ErrLastAdmin)
 		mockUserRepository.AssertExpectations(t)
+		mockAvatarUseCase.AssertNumberOfCalls(t, "RemoveFiles", 1)
+		mockTokenUseCase.AssertNumberOfCalls(t, "RevokeAll", 1)
 	})
 }
@@ -0,0 +1,32 @@
+package utils
+
+import "time"
+
+type AdminUser struct {
+	ID              string     `json:"id" example:"here is the generated user id"`
+	Username        string     `json:"username" example:"johndoe"`
+	Email           string     `json:"email" example:"johndoe@example.com"`
+	Role            string     `json:"role" example:"user"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at" example:"the verified at generated here"`
+	TwoFactor       bool       `json:"two_factor" example:"false"`
+	CreatedAt       *time.Time `json:"created_at" example:"the created at generated here"`
+}
+
+type ResponseDataAdminUsers struct {
+	Status string      `json:"status" example:"success"`
+	Data   []AdminUser `json:"data"`
+}
+
+type ChangeRole struct {
+	Role string `json:"role" binding:"required" example:"moderator"`
+}
+
+type ResponseMessageChangedRole struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"the role has been successfully changed"`
+}
+
+type ResponseMessageAdminDeletedUser struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"the account has been successfully deleted"`
+}
@@ -0,0 +1,39 @@
+package utils
+
+import "time"
+
+type CreateAPIKey struct {
+	Name      string     `json:"name" binding:"required,max=50" example:"nightly backup"`
+	Scopes    []string   `json:"scopes" binding:"required" example:"photos:read,comments:write"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2030-01-01T00:00:00Z"`
+}
+
+type APIKey struct {
+	ID         string     `json:"id" example:"here is the generated api key id"`
+	Name       string     `json:"name" example:"nightly backup"`
+	Prefix     string     `json:"prefix" example:"mgk_Xq3vB9aL"`
+	Scopes     []string   `json:"scopes" example:"photos:read,comments:write"`
+	ExpiresAt  *time.Time `json:"expires_at" example:"2030-01-01T00:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at" example:"the last used at generated here"`
+	CreatedAt  *time.Time `json:"created_at" example:"the created at generated here"`
+}
+
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key" example:"the api key generated here, shown only once"`
+}
+
+type ResponseDataCreatedAPIKey struct {
+	Status string        `json:"status" example:"success"`
+	Data   CreatedAPIKey `json:"data"`
+}
+
+type ResponseDataAPIKeys struct {
+	Status string   `json:"status" example:"success"`
+	Data   []APIKey `json:"data"`
+}
+
+type ResponseMessageDeletedAPIKey struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"the API key has been successfully deleted"`
+}
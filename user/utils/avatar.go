@@ -0,0 +1,21 @@
+package utils
+
+type AvatarSize struct {
+	Size int    `json:"size" example:"512"`
+	URL  string `json:"url" example:"/media/avatars/user-123/V1StGXR8Z5jdHi6B-512.jpg"`
+}
+
+type Avatar struct {
+	ProfileImageUrl string       `json:"profile_image_url" example:"/media/avatars/user-123/V1StGXR8Z5jdHi6B-512.jpg"`
+	Sizes           []AvatarSize `json:"sizes"`
+}
+
+type ResponseDataAvatar struct {
+	Status string `json:"status" example:"success"`
+	Data   Avatar `json:"data"`
+}
+
+type ResponseMessageDeletedAvatar struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"your avatar has been successfully deleted"`
+}
@@ -0,0 +1,15 @@
+package utils
+
+type VerifyEmail struct {
+	Token string `json:"token" binding:"required" example:"the token from the verification email"`
+}
+
+type ResponseMessageVerifiedEmail struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"your email has been successfully verified"`
+}
+
+type ResponseMessageResentVerification struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"a new verification link has been sent to your email"`
+}
@@ -0,0 +1,50 @@
+package utils
+
+type MFASetup struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	OtpauthURI string `json:"otpauth_uri" example:"otpauth://totp/MyGram:johndoe@example.com?algorithm=SHA1&digits=6&issuer=MyGram&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+}
+
+type ResponseDataMFASetup struct {
+	Status string   `json:"status" example:"success"`
+	Data   MFASetup `json:"data"`
+}
+
+type MFAConfirm struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+type MFARecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"ABCDE-FGHIJ,KLMNO-PQRST"`
+}
+
+type ResponseDataMFARecoveryCodes struct {
+	Status string           `json:"status" example:"success"`
+	Data   MFARecoveryCodes `json:"data"`
+}
+
+type MFADisable struct {
+	Password string `json:"password" binding:"required" example:"secret"`
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+type ResponseMessageMFADisabled struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"two-factor authentication has been disabled"`
+}
+
+type MFALogin struct {
+	MFAToken string `json:"mfa_token" binding:"required" example:"the mfa token from the login response"`
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+type MFAPending struct {
+	MFAToken  string `json:"mfa_token" example:"the mfa token generated here"`
+	TokenType string `json:"token_type" example:"mfa_pending"`
+	ExpiresIn int64  `json:"expires_in" example:"300"`
+}
+
+type ResponseDataMFAPending struct {
+	Status string     `json:"status" example:"mfa_required"`
+	Data   MFAPending `json:"data"`
+}
@@ -0,0 +1,8 @@
+package utils
+
+type OIDCCallback struct {
+	Code             string `form:"code" example:"the authorization code from the provider"`
+	State            string `form:"state" example:"the state from the provider"`
+	Error            string `form:"error" example:"access_denied"`
+	ErrorDescription string `form:"error_description" example:"the user declined"`
+}
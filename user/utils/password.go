@@ -0,0 +1,25 @@
+package utils
+
+type ForgotPassword struct {
+	Email string `json:"email" binding:"required" example:"johndoe@example.com"`
+}
+
+type ResponseMessageForgotPassword struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"if the email you entered is registered, a password reset link has been sent to it"`
+}
+
+type ResetPassword struct {
+	Token    string `json:"token" binding:"required" example:"the token from the password reset email"`
+	Password string `json:"password" binding:"required" example:"newsecret"`
+}
+
+type ResponseMessageResetPassword struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"your password has been successfully reset"`
+}
+
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password" binding:"required" example:"secret"`
+	NewPassword     string `json:"new_password" binding:"required" example:"newsecret"`
+}
@@ -0,0 +1,37 @@
+package utils
+
+import "time"
+
+type Session struct {
+	ID         string     `json:"id" example:"here is the generated session id"`
+	UserAgent  string     `json:"user_agent" example:"Mozilla/5.0 (X11; Linux x86_64) Firefox/118.0"`
+	IP         string     `json:"ip" example:"203.0.113.7"`
+	Current    bool       `json:"current" example:"true"`
+	LastSeenAt *time.Time `json:"last_seen_at" example:"the last seen at generated here"`
+	ExpiresAt  *time.Time `json:"expires_at" example:"the expires at generated here"`
+	CreatedAt  *time.Time `json:"created_at" example:"the created at generated here"`
+}
+
+type LoginHistoryEntry struct {
+	ID        string     `json:"id" example:"here is the generated session id"`
+	UserAgent string     `json:"user_agent" example:"Mozilla/5.0 (X11; Linux x86_64) Firefox/118.0"`
+	IP        string     `json:"ip" example:"203.0.113.7"`
+	Active    bool       `json:"active" example:"false"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" example:"the revoked at generated here"`
+	CreatedAt *time.Time `json:"created_at" example:"the created at generated here"`
+}
+
+type ResponseDataSessions struct {
+	Status string    `json:"status" example:"success"`
+	Data   []Session `json:"data"`
+}
+
+type ResponseDataLoginHistory struct {
+	Status string              `json:"status" example:"success"`
+	Data   []LoginHistoryEntry `json:"data"`
+}
+
+type ResponseMessageRevokedSession struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"the session has been successfully ended"`
+}
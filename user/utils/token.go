@@ -0,0 +1,26 @@
+package utils
+
+type RefreshToken struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"the refresh token generated here"`
+}
+
+type RefreshedToken struct {
+	Token        string `json:"token" example:"the token generated here"`
+	RefreshToken string `json:"refresh_token" example:"the refresh token generated here"`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int64  `json:"expires_in" example:"900"`
+}
+
+type ResponseDataRefreshedToken struct {
+	Status string         `json:"status" example:"success"`
+	Data   RefreshedToken `json:"data"`
+}
+
+type Logout struct {
+	RefreshToken string `json:"refresh_token" example:"the refresh token generated here"`
+}
+
+type ResponseMessageLoggedOut struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"you have been successfully logged out"`
+}
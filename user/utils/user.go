@@ -27,7 +27,10 @@ type LoginUser struct {
 }
 
 type LoggedinUser struct {
-	Token string `json:"token" example:"the token generated here"`
+	Token        string `json:"token" example:"the token generated here"`
+	RefreshToken string `json:"refresh_token" example:"the refresh token generated here"`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int64  `json:"expires_in" example:"900"`
 }
 
 type ResponseDataLoggedinUser struct {
@@ -36,16 +39,22 @@ type ResponseDataLoggedinUser struct {
 }
 
 type UpdateUser struct {
-	Email    string `json:"email" example:"newjohndoe@example.com"`
-	Username string `json:"username" example:"newjohndoe"`
+	Email           *string `json:"email" example:"newjohndoe@example.com"`
+	Username        *string `json:"username" example:"newjohndoe"`
+	Age             *uint   `json:"age" example:"8"`
+	ProfileImageUrl *string `json:"profile_image_url" example:"https://www.example.com/image.jpg"`
+	Bio             *string `json:"bio" example:"Sunsets and street food."`
 }
 
 type UpdatedUser struct {
-	ID        string     `json:"id" example:"here is the generated user id"`
-	Email     string     `json:"email" example:"newjohndoe@example.com"`
-	Username  string     `json:"username" example:"newjohndoe"`
-	Age       uint       `json:"age" example:"8"`
-	UpdatedAt *time.Time `json:"updated_at" example:"the updated at generated here"`
+	ID              string     `json:"id" example:"here is the generated user id"`
+	Email           string     `json:"email" example:"johndoe@example.com"`
+	PendingEmail    string     `json:"pending_email,omitempty" example:"newjohndoe@example.com"`
+	Username        string     `json:"username" example:"newjohndoe"`
+	Age             uint       `json:"age" example:"8"`
+	ProfileImageUrl string     `json:"profile_image_url" example:"https://www.example.com/image.jpg"`
+	Bio             string     `json:"bio" example:"Sunsets and street food."`
+	UpdatedAt       *time.Time `json:"updated_at" example:"the updated at generated here"`
 }
 
 type ResponseDataUpdatedUser struct {
@@ -62,3 +71,48 @@ type ResponseMessage struct {
 	Status string `json:"status" example:"fail"`
 	Data   string `json:"data" example:"the error explained here"`
 }
+
+type ProfileSocialMedia struct {
+	ID             string `json:"id" example:"here is the generated social media id"`
+	Name           string `json:"name" example:"Social Media"`
+	SocialMediaUrl string `json:"social_media_url" example:"https://www.example.com/social-media"`
+}
+
+type PublicProfile struct {
+	ID              string               `json:"id" example:"here is the generated user id"`
+	Username        string               `json:"username" example:"johndoe"`
+	DisplayName     string               `json:"display_name" example:"John Doe"`
+	Bio             string               `json:"bio" example:"Sunsets and street food."`
+	ProfileImageUrl string               `json:"profile_image_url" example:"https://www.example.com/image.jpg"`
+	PhotoCount      int64                `json:"photo_count" example:"12"`
+	FollowerCount   int64                `json:"follower_count" example:"0"`
+	SocialMedias    []ProfileSocialMedia `json:"social_medias"`
+	CreatedAt       *time.Time           `json:"created_at" example:"the created at generated here"`
+}
+
+type MyProfile struct {
+	PublicProfile
+	Email         string `json:"email" example:"johndoe@example.com"`
+	PendingEmail  string `json:"pending_email,omitempty" example:"newjohndoe@example.com"`
+	EmailVerified bool   `json:"email_verified" example:"true"`
+	Age           uint   `json:"age" example:"8"`
+	Role          string `json:"role" example:"user"`
+	MFAEnabled    bool   `json:"mfa_enabled" example:"false"`
+}
+
+type ResponseDataPublicProfile struct {
+	Status string        `json:"status" example:"success"`
+	Data   PublicProfile `json:"data"`
+}
+
+type ResponseDataMyProfile struct {
+	Status string    `json:"status" example:"success"`
+	Data   MyProfile `json:"data"`
+}
+
+type UpdateProfile struct {
+	DisplayName     *string `json:"display_name" example:"John Doe"`
+	Bio             *string `json:"bio" example:"Sunsets and street food."`
+	Age             *uint   `json:"age" example:"8"`
+	ProfileImageUrl *string `json:"profile_image_url" example:"https://www.example.com/image.jpg"`
+}